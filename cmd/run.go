@@ -32,10 +32,27 @@ var (
 		Short: "Run rosetta-kava",
 		RunE:  runRunCmd,
 	}
+
+	configFile string
 )
 
+func init() {
+	runCmd.Flags().StringVar(&configFile, "config", "", "path to a toml config file, overridden by environment variables")
+}
+
 func runRunCmd(cmd *cobra.Command, args []string) error {
-	configLoader := &configuration.EnvLoader{}
+	var configLoader configuration.ConfigLoader = &configuration.EnvLoader{}
+
+	if configFile != "" {
+		fileLoader, err := configuration.NewFileLoader(configFile)
+		if err != nil {
+			return fmt.Errorf("%w: unable to load configuration", err)
+		}
+
+		configLoader = &configuration.MultiLoader{
+			Loaders: []configuration.ConfigLoader{configLoader, fileLoader},
+		}
+	}
 
 	config, err := configuration.LoadConfig(configLoader)
 	if err != nil {
@@ -19,13 +19,18 @@
 package configuration
 
 import (
+	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kava-labs/rosetta-kava/kava"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // MiddlewareVersion represents the kava rosetta service version
@@ -58,8 +63,114 @@ const (
 
 	// KavaRPCURLEnv specifies the environment variable to read server port from
 	KavaRPCURLEnv = "KAVA_RPC_URL"
+
+	// KavaRPCFailoverURLsEnv specifies the environment variable to read a comma separated list of
+	// additional rpc urls from, used in order when KavaRPCURLEnv is unavailable
+	KavaRPCFailoverURLsEnv = "KAVA_RPC_FAILOVER_URLS"
+
+	// ChainIDEnv specifies the environment variable to read the chain id used for signing from
+	ChainIDEnv = "CHAIN_ID"
+
+	// CurrenciesFileEnv specifies the environment variable to read the path of a json file
+	// mapping denoms to currencies from.  These currencies override any loaded from the chain.
+	CurrenciesFileEnv = "CURRENCIES_FILE"
+
+	// LenientParsingEnv specifies the environment variable to enable returning transactions that
+	// can not be parsed without operations instead of failing the block
+	LenientParsingEnv = "LENIENT_PARSING"
+
+	// BlockCacheSizeEnv specifies the environment variable to read the number of blocks,
+	// block results and block responses to keep in memory from.  Zero disables caching.
+	BlockCacheSizeEnv = "BLOCK_CACHE_SIZE"
+
+	// DefaultBlockCacheSize is the block cache size used when BlockCacheSizeEnv is not set
+	DefaultBlockCacheSize = 100
+
+	// MempoolTxLimitEnv specifies the environment variable to read the maximum number of
+	// unconfirmed transactions requested from the node for the mempool endpoints from
+	MempoolTxLimitEnv = "MEMPOOL_TX_LIMIT"
+
+	// DefaultMempoolTxLimit is the mempool tx limit used when MempoolTxLimitEnv is not set
+	DefaultMempoolTxLimit = 100
+
+	// MaxMempoolTxLimit is the largest number of unconfirmed transactions returned by a single
+	// unconfirmed_txs request, which the node does not page
+	MaxMempoolTxLimit = 100
+
+	// ReadinessMaxBlockAgeEnv specifies the environment variable to read the maximum age of the
+	// node's latest block from, as a duration, before the service reports that it is not ready
+	ReadinessMaxBlockAgeEnv = "READINESS_MAX_BLOCK_AGE"
+
+	// DefaultReadinessMaxBlockAge is the maximum block age used when ReadinessMaxBlockAgeEnv is not set
+	DefaultReadinessMaxBlockAge = time.Minute
+
+	// ChainIDsEnv specifies the environment variable to read a comma separated list of
+	// network=chain-id pairs from, extending DefaultChainIDs
+	ChainIDsEnv = "CHAIN_IDS"
+
+	// CurrenciesEnv specifies the environment variable to read a json object mapping denoms
+	// to currencies from.  These currencies override those of CurrenciesFileEnv.
+	CurrenciesEnv = "CURRENCIES"
+
+	// GasPriceCurveEnv specifies the environment variable to read a comma separated list of
+	// gas prices from, used for suggested fee multipliers of 0, 1, 2 and so on
+	GasPriceCurveEnv = "GAS_PRICE_CURVE"
+
+	// ReadTimeoutEnv specifies the environment variable to read the maximum duration for
+	// reading an entire request from
+	ReadTimeoutEnv = "READ_TIMEOUT"
+
+	// WriteTimeoutEnv specifies the environment variable to read the maximum duration
+	// before timing out writes of a response from
+	WriteTimeoutEnv = "WRITE_TIMEOUT"
+
+	// IdleTimeoutEnv specifies the environment variable to read the maximum duration to
+	// wait for the next request when keep-alives are enabled from
+	IdleTimeoutEnv = "IDLE_TIMEOUT"
+
+	// KavaRPCTimeoutEnv specifies the environment variable to read the maximum duration of
+	// a kava rpc request from.  Zero disables the timeout.
+	KavaRPCTimeoutEnv = "KAVA_RPC_TIMEOUT"
+
+	// IBCTransferTimeoutEnv specifies the environment variable to read the duration after the latest
+	// block time used as the timeout of ibc transfers that do not set a timeout from
+	IBCTransferTimeoutEnv = "IBC_TRANSFER_TIMEOUT"
+
+	// DefaultIBCTransferTimeout is the ibc transfer timeout used when IBCTransferTimeoutEnv is not set
+	DefaultIBCTransferTimeout = 10 * time.Minute
+
+	// DefaultReadTimeout is the read timeout used when ReadTimeoutEnv is not set
+	DefaultReadTimeout = 5 * time.Second
+
+	// DefaultWriteTimeout is the write timeout used when WriteTimeoutEnv is not set
+	DefaultWriteTimeout = 120 * time.Second
+
+	// DefaultIdleTimeout is the idle timeout used when IdleTimeoutEnv is not set
+	DefaultIdleTimeout = 30 * time.Second
+
+	// AdditionalNetworksEnv specifies the environment variable to read a comma separated list of
+	// networks served alongside NetworkEnv from.  The rpc urls, chain id and erc20 contracts of each
+	// network are read from KavaRPCURLEnv, KavaRPCFailoverURLsEnv, ChainIDEnv and ERC20ContractsEnv
+	// prefixed by NetworkEnvPrefix.
+	AdditionalNetworksEnv = "ADDITIONAL_NETWORKS"
+
+	// ERC20ContractsEnv specifies the environment variable to read a json object mapping erc20
+	// contract addresses to currencies of NetworkEnv from.  Transfers of these contracts are parsed
+	// from evm logs.
+	ERC20ContractsEnv = "ERC20_CONTRACTS"
 )
 
+// DefaultGasPriceCurve is the gas price curve used when GasPriceCurveEnv is not set
+var DefaultGasPriceCurve = []float64{0.001, 0.005, 0.05, 0.25}
+
+// DefaultChainIDs maps well known networks to the chain id used when ChainIDEnv is not set.
+// Networks not listed use the network as their chain id unless set with ChainIDEnv or ChainIDsEnv.
+var DefaultChainIDs = map[string]string{
+	"kava-mainnet":  "kava_2222-10",
+	"kava-testnet":  "kava_2221-16000",
+	"kava-localnet": "kavalocalnet_8888-1",
+}
+
 // ModeFromString returns a Mode from a string value
 func ModeFromString(val string) (m Mode, err error) {
 	switch val {
@@ -94,10 +205,57 @@ func (l *EnvLoader) Get(key string) string {
 // Configuration represents values to configure behavior of
 // rosetta-kava and network to communicate with.
 type Configuration struct {
-	Mode              Mode
-	NetworkIdentifier *types.NetworkIdentifier
-	Port              int
-	KavaRPCURL        string
+	Mode                 Mode
+	NetworkIdentifier    *types.NetworkIdentifier
+	Port                 int
+	KavaRPCURL           string
+	KavaRPCFailoverURLs  []string
+	ChainID              string
+	Currencies           map[string]*types.Currency
+	ERC20Contracts       map[string]*types.Currency
+	LenientParsing       bool
+	BlockCacheSize       int
+	MempoolTxLimit       int
+	ReadinessMaxBlockAge time.Duration
+	GasPriceCurve        []float64
+	ReadTimeout          time.Duration
+	WriteTimeout         time.Duration
+	IdleTimeout          time.Duration
+	KavaRPCTimeout       time.Duration
+	IBCTransferTimeout   time.Duration
+	AdditionalNetworks   []*Configuration
+}
+
+// Networks returns the configuration of each network served, starting with the
+// configured network.  Additional networks share every value except the network
+// identifier, rpc urls, chain id and erc20 contracts.
+func (c *Configuration) Networks() []*Configuration {
+	return append([]*Configuration{c}, c.AdditionalNetworks...)
+}
+
+// NetworkIdentifiers returns the identifier of each network served
+func (c *Configuration) NetworkIdentifiers() []*types.NetworkIdentifier {
+	networks := c.Networks()
+
+	networkIdentifiers := make([]*types.NetworkIdentifier, len(networks))
+	for i, network := range networks {
+		networkIdentifiers[i] = network.NetworkIdentifier
+	}
+
+	return networkIdentifiers
+}
+
+// NetworkEnvPrefix returns the prefix of the environment variables configuring an
+// additional network, such as KAVA_TESTNET_ for kava-testnet
+func NetworkEnvPrefix(network string) string {
+	prefix := []rune(strings.ToUpper(network))
+	for i, r := range prefix {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			prefix[i] = '_'
+		}
+	}
+
+	return string(prefix) + "_"
 }
 
 // LoadConfig loads keys from a provided loader and returns a
@@ -136,16 +294,341 @@ func LoadConfig(loader ConfigLoader) (*Configuration, error) {
 		return nil, fmt.Errorf("invalid port '%s'", port)
 	}
 
-	kavaRPCURL := loader.Get(KavaRPCURLEnv)
+	chainIDs, err := parseChainIDs(loader.Get(ChainIDsEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	kavaRPCURL, kavaRPCFailoverURLs, chainID, err := loadNetwork(loader, "", network, mode, chainIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var currencies map[string]*types.Currency
+
+	if currenciesFile := loader.Get(CurrenciesFileEnv); currenciesFile != "" {
+		currencies, err = loadCurrencies(currenciesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rawCurrencies := loader.Get(CurrenciesEnv); rawCurrencies != "" {
+		overrides, err := parseCurrencies([]byte(rawCurrencies), CurrenciesEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		if currencies == nil {
+			currencies = make(map[string]*types.Currency, len(overrides))
+		}
+		for denom, currency := range overrides {
+			currencies[denom] = currency
+		}
+	}
+
+	erc20Contracts, err := loadERC20Contracts(loader, "")
+	if err != nil {
+		return nil, err
+	}
+
+	lenientParsing := false
+
+	if lenient := loader.Get(LenientParsingEnv); lenient != "" {
+		lenientParsing, err = strconv.ParseBool(lenient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s '%s'", LenientParsingEnv, lenient)
+		}
+	}
+
+	blockCacheSize := DefaultBlockCacheSize
+
+	if size := loader.Get(BlockCacheSizeEnv); size != "" {
+		blockCacheSize, err = strconv.Atoi(size)
+		if err != nil || blockCacheSize < 0 {
+			return nil, fmt.Errorf("invalid %s '%s'", BlockCacheSizeEnv, size)
+		}
+	}
+
+	mempoolTxLimit := DefaultMempoolTxLimit
+
+	if limit := loader.Get(MempoolTxLimitEnv); limit != "" {
+		mempoolTxLimit, err = strconv.Atoi(limit)
+		if err != nil || mempoolTxLimit <= 0 || mempoolTxLimit > MaxMempoolTxLimit {
+			return nil, fmt.Errorf("invalid %s '%s', must be between 1 and %d", MempoolTxLimitEnv, limit, MaxMempoolTxLimit)
+		}
+	}
+
+	readinessMaxBlockAge, err := loadDuration(loader, ReadinessMaxBlockAgeEnv, DefaultReadinessMaxBlockAge, false)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPriceCurve := DefaultGasPriceCurve
+
+	if curve := loader.Get(GasPriceCurveEnv); curve != "" {
+		gasPriceCurve, err = parseGasPriceCurve(curve)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	readTimeout, err := loadDuration(loader, ReadTimeoutEnv, DefaultReadTimeout, false)
+	if err != nil {
+		return nil, err
+	}
+
+	writeTimeout, err := loadDuration(loader, WriteTimeoutEnv, DefaultWriteTimeout, false)
+	if err != nil {
+		return nil, err
+	}
+
+	idleTimeout, err := loadDuration(loader, IdleTimeoutEnv, DefaultIdleTimeout, false)
+	if err != nil {
+		return nil, err
+	}
+
+	kavaRPCTimeout, err := loadDuration(loader, KavaRPCTimeoutEnv, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ibcTransferTimeout, err := loadDuration(loader, IBCTransferTimeoutEnv, DefaultIBCTransferTimeout, false)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Configuration{
+		Mode:                 mode,
+		NetworkIdentifier:    networkIdentifier,
+		Port:                 portNum,
+		KavaRPCURL:           kavaRPCURL,
+		KavaRPCFailoverURLs:  kavaRPCFailoverURLs,
+		ChainID:              chainID,
+		Currencies:           currencies,
+		ERC20Contracts:       erc20Contracts,
+		LenientParsing:       lenientParsing,
+		BlockCacheSize:       blockCacheSize,
+		MempoolTxLimit:       mempoolTxLimit,
+		ReadinessMaxBlockAge: readinessMaxBlockAge,
+		GasPriceCurve:        gasPriceCurve,
+		ReadTimeout:          readTimeout,
+		WriteTimeout:         writeTimeout,
+		IdleTimeout:          idleTimeout,
+		KavaRPCTimeout:       kavaRPCTimeout,
+		IBCTransferTimeout:   ibcTransferTimeout,
+	}
+
+	if additionalNetworks := loader.Get(AdditionalNetworksEnv); additionalNetworks != "" {
+		served := map[string]bool{network: true}
+
+		for _, additionalNetwork := range strings.Split(additionalNetworks, ",") {
+			additionalNetwork = strings.TrimSpace(additionalNetwork)
+			if additionalNetwork == "" {
+				return nil, fmt.Errorf("invalid %s '%s'", AdditionalNetworksEnv, additionalNetworks)
+			}
+
+			if served[additionalNetwork] {
+				return nil, fmt.Errorf("network %s is configured more than once", additionalNetwork)
+			}
+			served[additionalNetwork] = true
+
+			networkConfig := *config
+			networkConfig.AdditionalNetworks = nil
+			networkConfig.NetworkIdentifier = &types.NetworkIdentifier{
+				Blockchain: kava.Blockchain,
+				Network:    additionalNetwork,
+			}
+
+			networkConfig.KavaRPCURL, networkConfig.KavaRPCFailoverURLs, networkConfig.ChainID, err = loadNetwork(
+				loader,
+				NetworkEnvPrefix(additionalNetwork),
+				additionalNetwork,
+				mode,
+				chainIDs,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// erc20 contracts are deployed separately on each chain and are not shared
+			networkConfig.ERC20Contracts, err = loadERC20Contracts(loader, NetworkEnvPrefix(additionalNetwork))
+			if err != nil {
+				return nil, err
+			}
+
+			config.AdditionalNetworks = append(config.AdditionalNetworks, &networkConfig)
+		}
+	}
+
+	return config, nil
+}
+
+// loadNetwork reads the rpc urls and chain id of a network from keys with the provided prefix
+func loadNetwork(
+	loader ConfigLoader,
+	prefix string,
+	network string,
+	mode Mode,
+	chainIDs map[string]string,
+) (kavaRPCURL string, kavaRPCFailoverURLs []string, chainID string, err error) {
+	kavaRPCURLKey := prefix + KavaRPCURLEnv
+	kavaRPCURL = loader.Get(kavaRPCURLKey)
 
 	if kavaRPCURL == "" {
-		return nil, fmt.Errorf("%s must be set", KavaRPCURLEnv)
+		return "", nil, "", fmt.Errorf("%s must be set", kavaRPCURLKey)
+	}
+
+	failoverURLsKey := prefix + KavaRPCFailoverURLsEnv
+
+	if failoverURLs := loader.Get(failoverURLsKey); failoverURLs != "" {
+		for _, failoverURL := range strings.Split(failoverURLs, ",") {
+			failoverURL = strings.TrimSpace(failoverURL)
+			if failoverURL == "" {
+				return "", nil, "", fmt.Errorf("invalid %s '%s'", failoverURLsKey, failoverURLs)
+			}
+
+			kavaRPCFailoverURLs = append(kavaRPCFailoverURLs, failoverURL)
+		}
+	}
+
+	chainIDKey := prefix + ChainIDEnv
+	chainID = loader.Get(chainIDKey)
+
+	if chainID == "" {
+		var ok bool
+		if chainID, ok = defaultChainID(chainIDs, network); !ok {
+			// offline mode signs with the chain id and can not verify it against the node
+			if mode == Offline {
+				return "", nil, "", fmt.Errorf("%s must be set in offline mode for network %s without a default chain id", chainIDKey, network)
+			}
+
+			// networks have historically been named after their chain id
+			log.Printf("%s is not set and network %s has no default chain id, using the network as the chain id", chainIDKey, network)
+			chainID = network
+		}
+	}
+
+	return kavaRPCURL, kavaRPCFailoverURLs, chainID, nil
+}
+
+// loadCurrencies reads a json object of denoms to rosetta currencies
+func loadCurrencies(path string) (map[string]*types.Currency, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read currencies file", err)
+	}
+
+	return parseCurrencies(bz, path)
+}
+
+// parseCurrencies parses a json object of denoms to rosetta currencies read from source
+func parseCurrencies(bz []byte, source string) (map[string]*types.Currency, error) {
+	var currencies map[string]*types.Currency
+	if err := json.Unmarshal(bz, &currencies); err != nil {
+		return nil, fmt.Errorf("%w: could not parse currencies from %s", err, source)
+	}
+
+	for denom, currency := range currencies {
+		if currency == nil || currency.Symbol == "" {
+			return nil, fmt.Errorf("currency for denom %s in %s must have a symbol", denom, source)
+		}
+	}
+
+	return currencies, nil
+}
+
+// loadERC20Contracts reads the erc20 contracts of a network from ERC20ContractsEnv with the provided prefix
+func loadERC20Contracts(loader ConfigLoader, prefix string) (map[string]*types.Currency, error) {
+	key := prefix + ERC20ContractsEnv
+
+	rawContracts := loader.Get(key)
+	if rawContracts == "" {
+		return nil, nil
+	}
+
+	return parseERC20Contracts([]byte(rawContracts), key)
+}
+
+// parseERC20Contracts parses a json object of erc20 contract addresses to rosetta currencies read from source
+func parseERC20Contracts(bz []byte, source string) (map[string]*types.Currency, error) {
+	var contracts map[string]*types.Currency
+	if err := json.Unmarshal(bz, &contracts); err != nil {
+		return nil, fmt.Errorf("%w: could not parse erc20 contracts from %s", err, source)
+	}
+
+	for contract, currency := range contracts {
+		if !common.IsHexAddress(contract) {
+			return nil, fmt.Errorf("invalid contract address %s in %s", contract, source)
+		}
+
+		if currency == nil || currency.Symbol == "" {
+			return nil, fmt.Errorf("currency for contract %s in %s must have a symbol", contract, source)
+		}
+	}
+
+	return contracts, nil
+}
+
+// parseChainIDs parses a comma separated list of network=chain-id pairs
+func parseChainIDs(value string) (map[string]string, error) {
+	chainIDs := make(map[string]string)
+	if value == "" {
+		return chainIDs, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		network, chainID, ok := strings.Cut(pair, "=")
+		network = strings.TrimSpace(network)
+		chainID = strings.TrimSpace(chainID)
+
+		if !ok || network == "" || chainID == "" {
+			return nil, fmt.Errorf("invalid %s '%s'", ChainIDsEnv, value)
+		}
+
+		chainIDs[network] = chainID
+	}
+
+	return chainIDs, nil
+}
+
+// parseGasPriceCurve parses a comma separated list of non-negative, non-decreasing gas prices
+func parseGasPriceCurve(value string) ([]float64, error) {
+	var curve []float64
+
+	for _, rawPrice := range strings.Split(value, ",") {
+		price, err := strconv.ParseFloat(strings.TrimSpace(rawPrice), 64)
+		if err != nil || price < 0 || (len(curve) > 0 && price < curve[len(curve)-1]) {
+			return nil, fmt.Errorf("invalid %s '%s'", GasPriceCurveEnv, value)
+		}
+
+		curve = append(curve, price)
+	}
+
+	return curve, nil
+}
+
+// loadDuration reads a duration from the loader, returning defaultValue when the key is not set
+func loadDuration(loader ConfigLoader, key string, defaultValue time.Duration, allowZero bool) (time.Duration, error) {
+	value := loader.Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 || (duration == 0 && !allowZero) {
+		return 0, fmt.Errorf("invalid %s '%s'", key, value)
+	}
+
+	return duration, nil
+}
+
+// defaultChainID returns the chain id of a network from ChainIDsEnv or DefaultChainIDs
+func defaultChainID(chainIDs map[string]string, network string) (string, bool) {
+	if chainID, ok := chainIDs[network]; ok {
+		return chainID, true
 	}
 
-	return &Configuration{
-		Mode:              mode,
-		NetworkIdentifier: networkIdentifier,
-		Port:              portNum,
-		KavaRPCURL:        kavaRPCURL,
-	}, nil
+	chainID, ok := DefaultChainIDs[network]
+	return chainID, ok
 }
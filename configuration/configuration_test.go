@@ -20,11 +20,14 @@ package configuration
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testEnvLoader struct {
@@ -101,12 +104,48 @@ func TestLoadConfig_Mode(t *testing.T) {
 			},
 			ExpectedErr: fmt.Errorf("%s must be set", KavaRPCURLEnv),
 		},
+		"chain id not set for unknown network": {
+			Env: map[string]string{
+				ModeEnv:       Online.String(),
+				NetworkEnv:    testChainID,
+				PortEnv:       testPort,
+				KavaRPCURLEnv: testKavaRPCURL,
+			},
+			ExpectedConfig: &Configuration{
+				Mode: Online,
+				NetworkIdentifier: &types.NetworkIdentifier{
+					Blockchain: blockchain,
+					Network:    testChainID,
+				},
+				Port:                 testPortNum,
+				KavaRPCURL:           testKavaRPCURL,
+				ChainID:              testChainID,
+				BlockCacheSize:       DefaultBlockCacheSize,
+				MempoolTxLimit:       DefaultMempoolTxLimit,
+				ReadinessMaxBlockAge: DefaultReadinessMaxBlockAge,
+				GasPriceCurve:        DefaultGasPriceCurve,
+				ReadTimeout:          DefaultReadTimeout,
+				WriteTimeout:         DefaultWriteTimeout,
+				IdleTimeout:          DefaultIdleTimeout,
+				IBCTransferTimeout:   DefaultIBCTransferTimeout,
+			},
+		},
+		"chain id not set for unknown network in offline mode": {
+			Env: map[string]string{
+				ModeEnv:       Offline.String(),
+				NetworkEnv:    testChainID,
+				PortEnv:       testPort,
+				KavaRPCURLEnv: testKavaRPCURL,
+			},
+			ExpectedErr: fmt.Errorf("%s must be set in offline mode for network %s without a default chain id", ChainIDEnv, testChainID),
+		},
 		"env set with online mode": {
 			Env: map[string]string{
 				ModeEnv:       Online.String(),
 				NetworkEnv:    testChainID,
 				PortEnv:       testPort,
 				KavaRPCURLEnv: testKavaRPCURL,
+				ChainIDEnv:    testChainID,
 			},
 			ExpectedConfig: &Configuration{
 				Mode: Online,
@@ -114,8 +153,17 @@ func TestLoadConfig_Mode(t *testing.T) {
 					Blockchain: blockchain,
 					Network:    testChainID,
 				},
-				Port:       testPortNum,
-				KavaRPCURL: testKavaRPCURL,
+				Port:                 testPortNum,
+				KavaRPCURL:           testKavaRPCURL,
+				ChainID:              testChainID,
+				BlockCacheSize:       DefaultBlockCacheSize,
+				MempoolTxLimit:       DefaultMempoolTxLimit,
+				ReadinessMaxBlockAge: DefaultReadinessMaxBlockAge,
+				GasPriceCurve:        DefaultGasPriceCurve,
+				ReadTimeout:          DefaultReadTimeout,
+				WriteTimeout:         DefaultWriteTimeout,
+				IdleTimeout:          DefaultIdleTimeout,
+				IBCTransferTimeout:   DefaultIBCTransferTimeout,
 			},
 		},
 		"env set with offline mode": {
@@ -124,6 +172,7 @@ func TestLoadConfig_Mode(t *testing.T) {
 				NetworkEnv:    testChainID,
 				PortEnv:       testPort,
 				KavaRPCURLEnv: testKavaRPCURL,
+				ChainIDEnv:    testChainID,
 			},
 			ExpectedConfig: &Configuration{
 				Mode: Offline,
@@ -131,8 +180,70 @@ func TestLoadConfig_Mode(t *testing.T) {
 					Blockchain: blockchain,
 					Network:    testChainID,
 				},
-				Port:       testPortNum,
-				KavaRPCURL: testKavaRPCURL,
+				Port:                 testPortNum,
+				KavaRPCURL:           testKavaRPCURL,
+				ChainID:              testChainID,
+				BlockCacheSize:       DefaultBlockCacheSize,
+				MempoolTxLimit:       DefaultMempoolTxLimit,
+				ReadinessMaxBlockAge: DefaultReadinessMaxBlockAge,
+				GasPriceCurve:        DefaultGasPriceCurve,
+				ReadTimeout:          DefaultReadTimeout,
+				WriteTimeout:         DefaultWriteTimeout,
+				IdleTimeout:          DefaultIdleTimeout,
+				IBCTransferTimeout:   DefaultIBCTransferTimeout,
+			},
+		},
+		"env set with chain id": {
+			Env: map[string]string{
+				ModeEnv:       Online.String(),
+				NetworkEnv:    testChainID,
+				PortEnv:       testPort,
+				KavaRPCURLEnv: testKavaRPCURL,
+				ChainIDEnv:    "kava_2221-17000",
+			},
+			ExpectedConfig: &Configuration{
+				Mode: Online,
+				NetworkIdentifier: &types.NetworkIdentifier{
+					Blockchain: blockchain,
+					Network:    testChainID,
+				},
+				Port:                 testPortNum,
+				KavaRPCURL:           testKavaRPCURL,
+				ChainID:              "kava_2221-17000",
+				BlockCacheSize:       DefaultBlockCacheSize,
+				MempoolTxLimit:       DefaultMempoolTxLimit,
+				ReadinessMaxBlockAge: DefaultReadinessMaxBlockAge,
+				GasPriceCurve:        DefaultGasPriceCurve,
+				ReadTimeout:          DefaultReadTimeout,
+				WriteTimeout:         DefaultWriteTimeout,
+				IdleTimeout:          DefaultIdleTimeout,
+				IBCTransferTimeout:   DefaultIBCTransferTimeout,
+			},
+		},
+		"env set with well known network": {
+			Env: map[string]string{
+				ModeEnv:       Online.String(),
+				NetworkEnv:    "kava-mainnet",
+				PortEnv:       testPort,
+				KavaRPCURLEnv: testKavaRPCURL,
+			},
+			ExpectedConfig: &Configuration{
+				Mode: Online,
+				NetworkIdentifier: &types.NetworkIdentifier{
+					Blockchain: blockchain,
+					Network:    "kava-mainnet",
+				},
+				Port:                 testPortNum,
+				KavaRPCURL:           testKavaRPCURL,
+				ChainID:              "kava_2222-10",
+				BlockCacheSize:       DefaultBlockCacheSize,
+				MempoolTxLimit:       DefaultMempoolTxLimit,
+				ReadinessMaxBlockAge: DefaultReadinessMaxBlockAge,
+				GasPriceCurve:        DefaultGasPriceCurve,
+				ReadTimeout:          DefaultReadTimeout,
+				WriteTimeout:         DefaultWriteTimeout,
+				IdleTimeout:          DefaultIdleTimeout,
+				IBCTransferTimeout:   DefaultIBCTransferTimeout,
 			},
 		},
 	}
@@ -153,6 +264,403 @@ func TestLoadConfig_Mode(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_CurrenciesFile(t *testing.T) {
+	dir := t.TempDir()
+	env := map[string]string{
+		ModeEnv:       Offline.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+	}
+
+	validFile := filepath.Join(dir, "currencies.json")
+	err := os.WriteFile(validFile, []byte(`{"erc20/tether/usdt": {"symbol": "USDT", "decimals": 6}}`), 0o600)
+	require.NoError(t, err)
+
+	env[CurrenciesFileEnv] = validFile
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*types.Currency{
+		"erc20/tether/usdt": {Symbol: "USDT", Decimals: 6},
+	}, cfg.Currencies)
+
+	invalidFile := filepath.Join(dir, "invalid.json")
+	err = os.WriteFile(invalidFile, []byte(`{"erc20/tether/usdt": {"decimals": 6}}`), 0o600)
+	require.NoError(t, err)
+
+	env[CurrenciesFileEnv] = invalidFile
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, fmt.Sprintf("currency for denom erc20/tether/usdt in %s must have a symbol", invalidFile))
+
+	env[CurrenciesFileEnv] = filepath.Join(dir, "missing.json")
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadConfig_LenientParsing(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.False(t, cfg.LenientParsing)
+
+	env[LenientParsingEnv] = "true"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.True(t, cfg.LenientParsing)
+
+	env[LenientParsingEnv] = "sometimes"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid LENIENT_PARSING 'sometimes'")
+}
+
+func TestLoadConfig_BlockCacheSize(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBlockCacheSize, cfg.BlockCacheSize)
+
+	env[BlockCacheSizeEnv] = "0"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.BlockCacheSize)
+
+	for _, size := range []string{"-1", "lots"} {
+		env[BlockCacheSizeEnv] = size
+		cfg, err = LoadConfig(&testEnvLoader{Env: env})
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, fmt.Sprintf("invalid BLOCK_CACHE_SIZE '%s'", size))
+	}
+}
+
+func TestLoadConfig_MempoolTxLimit(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMempoolTxLimit, cfg.MempoolTxLimit)
+
+	env[MempoolTxLimitEnv] = "25"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, 25, cfg.MempoolTxLimit)
+
+	for _, limit := range []string{"0", "101", "lots"} {
+		env[MempoolTxLimitEnv] = limit
+		cfg, err = LoadConfig(&testEnvLoader{Env: env})
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, fmt.Sprintf("invalid MEMPOOL_TX_LIMIT '%s', must be between 1 and 100", limit))
+	}
+}
+
+func TestLoadConfig_KavaRPCFailoverURLs(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "http://node-0:26657",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Nil(t, cfg.KavaRPCFailoverURLs)
+
+	env[KavaRPCFailoverURLsEnv] = "http://node-1:26657, http://archive:26657"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, "http://node-0:26657", cfg.KavaRPCURL)
+	assert.Equal(t, []string{"http://node-1:26657", "http://archive:26657"}, cfg.KavaRPCFailoverURLs)
+
+	env[KavaRPCFailoverURLsEnv] = "http://node-1:26657,,"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid KAVA_RPC_FAILOVER_URLS 'http://node-1:26657,,'")
+}
+
+func TestLoadConfig_ReadinessMaxBlockAge(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReadinessMaxBlockAge, cfg.ReadinessMaxBlockAge)
+
+	env[ReadinessMaxBlockAgeEnv] = "30s"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.ReadinessMaxBlockAge)
+
+	for _, age := range []string{"0s", "-1m", "30"} {
+		env[ReadinessMaxBlockAgeEnv] = age
+		cfg, err = LoadConfig(&testEnvLoader{Env: env})
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, fmt.Sprintf("invalid READINESS_MAX_BLOCK_AGE '%s'", age))
+	}
+}
+
+func TestLoadConfig_ChainIDs(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-internal-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+		ChainIDsEnv:   "kava-internal-testnet=kava_2221-20000, kava-testnet=kava_2221-17000",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, "kava_2221-20000", cfg.ChainID)
+
+	// mappings override well known networks
+	env[NetworkEnv] = "kava-testnet"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, "kava_2221-17000", cfg.ChainID)
+
+	for _, chainIDs := range []string{"kava-testnet", "kava-testnet=", "=kava_2221-17000", "a=b,,"} {
+		env[ChainIDsEnv] = chainIDs
+		cfg, err = LoadConfig(&testEnvLoader{Env: env})
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, fmt.Sprintf("invalid CHAIN_IDS '%s'", chainIDs))
+	}
+}
+
+func TestLoadConfig_Currencies(t *testing.T) {
+	dir := t.TempDir()
+	currenciesFile := filepath.Join(dir, "currencies.json")
+	err := os.WriteFile(currenciesFile, []byte(`{"erc20/tether/usdt": {"symbol": "USDT", "decimals": 6}, "ukava": {"symbol": "KAVA", "decimals": 6}}`), 0o600)
+	require.NoError(t, err)
+
+	env := map[string]string{
+		ModeEnv:           Offline.String(),
+		NetworkEnv:        "kava-testnet",
+		PortEnv:           "8000",
+		KavaRPCURLEnv:     "https://rpc.testnet.kava.io:443",
+		CurrenciesFileEnv: currenciesFile,
+		CurrenciesEnv:     `{"erc20/tether/usdt": {"symbol": "USDt", "decimals": 6}}`,
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*types.Currency{
+		"erc20/tether/usdt": {Symbol: "USDt", Decimals: 6},
+		"ukava":             {Symbol: "KAVA", Decimals: 6},
+	}, cfg.Currencies)
+
+	env[CurrenciesEnv] = `{"ukava": {"decimals": 6}}`
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "currency for denom ukava in CURRENCIES must have a symbol")
+}
+
+func TestLoadConfig_ERC20Contracts(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:           Online.String(),
+		NetworkEnv:        "kava-mainnet",
+		PortEnv:           "8000",
+		KavaRPCURLEnv:     "https://rpc.kava.io:443",
+		ERC20ContractsEnv: `{"0x919C1c267BC06a7039e03fcc2eF738525769109c": {"symbol": "USDt", "decimals": 6}}`,
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*types.Currency{
+		"0x919C1c267BC06a7039e03fcc2eF738525769109c": {Symbol: "USDt", Decimals: 6},
+	}, cfg.ERC20Contracts)
+
+	env[ERC20ContractsEnv] = `{"erc20/tether/usdt": {"symbol": "USDt", "decimals": 6}}`
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid contract address erc20/tether/usdt in ERC20_CONTRACTS")
+
+	env[ERC20ContractsEnv] = `{"0x919C1c267BC06a7039e03fcc2eF738525769109c": {"decimals": 6}}`
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "currency for contract 0x919C1c267BC06a7039e03fcc2eF738525769109c in ERC20_CONTRACTS must have a symbol")
+
+	delete(env, ERC20ContractsEnv)
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Nil(t, cfg.ERC20Contracts)
+}
+
+func TestLoadConfig_GasPriceCurve(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:       Online.String(),
+		NetworkEnv:    "kava-testnet",
+		PortEnv:       "8000",
+		KavaRPCURLEnv: "https://rpc.testnet.kava.io:443",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultGasPriceCurve, cfg.GasPriceCurve)
+
+	env[GasPriceCurveEnv] = "0.002, 0.002, 0.5"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.002, 0.002, 0.5}, cfg.GasPriceCurve)
+
+	for _, curve := range []string{"cheap", "0.01,", "-0.01", "0.05,0.01"} {
+		env[GasPriceCurveEnv] = curve
+		cfg, err = LoadConfig(&testEnvLoader{Env: env})
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, fmt.Sprintf("invalid GAS_PRICE_CURVE '%s'", curve))
+	}
+}
+
+func TestLoadConfig_Timeouts(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:           Online.String(),
+		NetworkEnv:        "kava-testnet",
+		PortEnv:           "8000",
+		KavaRPCURLEnv:     "https://rpc.testnet.kava.io:443",
+		ReadTimeoutEnv:    "10s",
+		WriteTimeoutEnv:   "1m",
+		IdleTimeoutEnv:    "1m30s",
+		KavaRPCTimeoutEnv: "0s",
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, cfg.ReadTimeout)
+	assert.Equal(t, time.Minute, cfg.WriteTimeout)
+	assert.Equal(t, 90*time.Second, cfg.IdleTimeout)
+	assert.Equal(t, time.Duration(0), cfg.KavaRPCTimeout)
+	assert.Equal(t, DefaultIBCTransferTimeout, cfg.IBCTransferTimeout)
+
+	env[IBCTransferTimeoutEnv] = "1h"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.IBCTransferTimeout)
+
+	for _, key := range []string{ReadTimeoutEnv, WriteTimeoutEnv, IdleTimeoutEnv, IBCTransferTimeoutEnv} {
+		invalidEnv := map[string]string{key: "0s"}
+		for k, v := range env {
+			if k != key {
+				invalidEnv[k] = v
+			}
+		}
+
+		cfg, err = LoadConfig(&testEnvLoader{Env: invalidEnv})
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, fmt.Sprintf("invalid %s '0s'", key))
+	}
+
+	env[KavaRPCTimeoutEnv] = "-5s"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid KAVA_RPC_TIMEOUT '-5s'")
+}
+
+func TestLoadConfig_AdditionalNetworks(t *testing.T) {
+	env := map[string]string{
+		ModeEnv:                               Online.String(),
+		NetworkEnv:                            "kava-mainnet",
+		PortEnv:                               "8000",
+		KavaRPCURLEnv:                         "http://mainnet:26657",
+		LenientParsingEnv:                     "true",
+		AdditionalNetworksEnv:                 "kava-testnet, kava-devnet",
+		"KAVA_TESTNET_KAVA_RPC_URL":           "http://testnet:26657",
+		"KAVA_TESTNET_KAVA_RPC_FAILOVER_URLS": "http://testnet-archive:26657",
+		"KAVA_DEVNET_KAVA_RPC_URL":            "http://devnet:26657",
+		"KAVA_DEVNET_CHAIN_ID":                "kavadevnet_2225-1",
+		ERC20ContractsEnv:                     `{"0x919C1c267BC06a7039e03fcc2eF738525769109c": {"symbol": "USDt", "decimals": 6}}`,
+		"KAVA_TESTNET_ERC20_CONTRACTS":        `{"0xfA9343C3897324496A05fC75abeD6bAC29f8A40f": {"symbol": "USDC", "decimals": 6}}`,
+	}
+
+	cfg, err := LoadConfig(&testEnvLoader{Env: env})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*types.NetworkIdentifier{
+		{Blockchain: "Kava", Network: "kava-mainnet"},
+		{Blockchain: "Kava", Network: "kava-testnet"},
+		{Blockchain: "Kava", Network: "kava-devnet"},
+	}, cfg.NetworkIdentifiers())
+
+	networks := cfg.Networks()
+	require.Len(t, networks, 3)
+	assert.Same(t, cfg, networks[0])
+	assert.Equal(t, "kava_2222-10", networks[0].ChainID)
+	assert.Equal(t, map[string]*types.Currency{
+		"0x919C1c267BC06a7039e03fcc2eF738525769109c": {Symbol: "USDt", Decimals: 6},
+	}, networks[0].ERC20Contracts)
+
+	testnet := networks[1]
+	assert.Equal(t, "http://testnet:26657", testnet.KavaRPCURL)
+	assert.Equal(t, []string{"http://testnet-archive:26657"}, testnet.KavaRPCFailoverURLs)
+	assert.Equal(t, "kava_2221-16000", testnet.ChainID)
+	assert.Equal(t, map[string]*types.Currency{
+		"0xfA9343C3897324496A05fC75abeD6bAC29f8A40f": {Symbol: "USDC", Decimals: 6},
+	}, testnet.ERC20Contracts)
+	assert.True(t, testnet.LenientParsing)
+	assert.Nil(t, testnet.AdditionalNetworks)
+
+	devnet := networks[2]
+	assert.Equal(t, "http://devnet:26657", devnet.KavaRPCURL)
+	assert.Nil(t, devnet.KavaRPCFailoverURLs)
+	assert.Equal(t, "kavadevnet_2225-1", devnet.ChainID)
+	assert.Nil(t, devnet.ERC20Contracts)
+	assert.Nil(t, devnet.AdditionalNetworks)
+
+	env["KAVA_TESTNET_ERC20_CONTRACTS"] = `{"usdc": {"symbol": "USDC", "decimals": 6}}`
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid contract address usdc in KAVA_TESTNET_ERC20_CONTRACTS")
+	delete(env, "KAVA_TESTNET_ERC20_CONTRACTS")
+
+	env[ModeEnv] = Offline.String()
+	delete(env, "KAVA_DEVNET_CHAIN_ID")
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "KAVA_DEVNET_CHAIN_ID must be set in offline mode for network kava-devnet without a default chain id")
+	env[ModeEnv] = Online.String()
+	env["KAVA_DEVNET_CHAIN_ID"] = "kavadevnet_2225-1"
+
+	delete(env, "KAVA_DEVNET_KAVA_RPC_URL")
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "KAVA_DEVNET_KAVA_RPC_URL must be set")
+
+	env[AdditionalNetworksEnv] = "kava-testnet,kava-mainnet"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "network kava-mainnet is configured more than once")
+
+	env[AdditionalNetworksEnv] = "kava-testnet,"
+	cfg, err = LoadConfig(&testEnvLoader{Env: env})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid ADDITIONAL_NETWORKS 'kava-testnet,'")
+}
+
+func TestNetworkEnvPrefix(t *testing.T) {
+	assert.Equal(t, "KAVA_TESTNET_", NetworkEnvPrefix("kava-testnet"))
+	assert.Equal(t, "KAVA_2222_10_", NetworkEnvPrefix("kava_2222-10"))
+}
+
 func TestConfigLoader(t *testing.T) {
 	testVarName := "ROSETTA_KAVA_TEST_VAR"
 	testVarVal := "a test value"
@@ -0,0 +1,268 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// fileConfig is the structure of a toml config file
+type fileConfig struct {
+	Mode           string                     `toml:"mode"`
+	Network        string                     `toml:"network"`
+	Port           *int                       `toml:"port"`
+	ChainID        string                     `toml:"chain_id"`
+	ChainIDs       map[string]string          `toml:"chain_ids"`
+	CurrenciesFile string                     `toml:"currencies_file"`
+	Currencies     map[string]*types.Currency `toml:"currencies"`
+	ERC20Contracts map[string]*types.Currency `toml:"erc20_contracts"`
+	LenientParsing *bool                      `toml:"lenient_parsing"`
+	BlockCacheSize *int                       `toml:"block_cache_size"`
+	MempoolTxLimit *int                       `toml:"mempool_tx_limit"`
+	GasPriceCurve  []float64                  `toml:"gas_price_curve"`
+	RPC            fileRPCConfig              `toml:"rpc"`
+	Timeouts       fileTimeoutsConfig         `toml:"timeouts"`
+	Networks       []fileNetworkConfig        `toml:"networks"`
+}
+
+type fileNetworkConfig struct {
+	Network        string                     `toml:"network"`
+	ChainID        string                     `toml:"chain_id"`
+	ERC20Contracts map[string]*types.Currency `toml:"erc20_contracts"`
+	RPC            fileRPCConfig              `toml:"rpc"`
+}
+
+type fileRPCConfig struct {
+	URL          string   `toml:"url"`
+	FailoverURLs []string `toml:"failover_urls"`
+}
+
+type fileTimeoutsConfig struct {
+	Read                 string `toml:"read"`
+	Write                string `toml:"write"`
+	Idle                 string `toml:"idle"`
+	RPC                  string `toml:"rpc"`
+	ReadinessMaxBlockAge string `toml:"readiness_max_block_age"`
+	IBCTransfer          string `toml:"ibc_transfer"`
+}
+
+// FileLoader loads keys from a toml config file
+// and implements ConfigLoader.  Values are returned in the
+// format of the matching environment variable so they are
+// validated by LoadConfig.
+type FileLoader struct {
+	values map[string]string
+}
+
+var _ ConfigLoader = (*FileLoader)(nil)
+
+// NewFileLoader reads and decodes a toml config file, returning an
+// error for invalid toml or keys that are not supported
+func NewFileLoader(path string) (*FileLoader, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read config file", err)
+	}
+
+	var config fileConfig
+	decoder := toml.NewDecoder(bytes.NewReader(bz)).DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, configFileError(path, err)
+	}
+
+	values, err := config.values()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return &FileLoader{values: values}, nil
+}
+
+// Get retrieves key from the config file
+func (l *FileLoader) Get(key string) string {
+	return l.values[key]
+}
+
+// MultiLoader loads keys from the first loader with a value for the key
+// and implements ConfigLoader
+type MultiLoader struct {
+	Loaders []ConfigLoader
+}
+
+var _ ConfigLoader = (*MultiLoader)(nil)
+
+// Get retrieves key from the first loader returning a non-empty value
+func (l *MultiLoader) Get(key string) string {
+	for _, loader := range l.Loaders {
+		if value := loader.Get(key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// values returns the config file values keyed by environment variable
+func (c *fileConfig) values() (map[string]string, error) {
+	values := map[string]string{
+		ModeEnv:                 c.Mode,
+		NetworkEnv:              c.Network,
+		KavaRPCURLEnv:           c.RPC.URL,
+		KavaRPCFailoverURLsEnv:  strings.Join(c.RPC.FailoverURLs, ","),
+		ChainIDEnv:              c.ChainID,
+		CurrenciesFileEnv:       c.CurrenciesFile,
+		ReadTimeoutEnv:          c.Timeouts.Read,
+		WriteTimeoutEnv:         c.Timeouts.Write,
+		IdleTimeoutEnv:          c.Timeouts.Idle,
+		KavaRPCTimeoutEnv:       c.Timeouts.RPC,
+		ReadinessMaxBlockAgeEnv: c.Timeouts.ReadinessMaxBlockAge,
+		IBCTransferTimeoutEnv:   c.Timeouts.IBCTransfer,
+	}
+
+	if c.Port != nil {
+		values[PortEnv] = strconv.Itoa(*c.Port)
+	}
+
+	if c.LenientParsing != nil {
+		values[LenientParsingEnv] = strconv.FormatBool(*c.LenientParsing)
+	}
+
+	if c.BlockCacheSize != nil {
+		values[BlockCacheSizeEnv] = strconv.Itoa(*c.BlockCacheSize)
+	}
+
+	if c.MempoolTxLimit != nil {
+		values[MempoolTxLimitEnv] = strconv.Itoa(*c.MempoolTxLimit)
+	}
+
+	for _, url := range c.RPC.FailoverURLs {
+		if strings.Contains(url, ",") {
+			return nil, fmt.Errorf("invalid rpc.failover_urls entry '%s'", url)
+		}
+	}
+
+	if len(c.Networks) > 0 {
+		networks := make([]string, len(c.Networks))
+		for i, network := range c.Networks {
+			if network.Network == "" || strings.Contains(network.Network, ",") {
+				return nil, fmt.Errorf("invalid networks entry '%s'", network.Network)
+			}
+
+			for _, url := range network.RPC.FailoverURLs {
+				if strings.Contains(url, ",") {
+					return nil, fmt.Errorf("invalid rpc.failover_urls entry '%s' for network %s", url, network.Network)
+				}
+			}
+
+			prefix := NetworkEnvPrefix(network.Network)
+			values[prefix+KavaRPCURLEnv] = network.RPC.URL
+			values[prefix+KavaRPCFailoverURLsEnv] = strings.Join(network.RPC.FailoverURLs, ",")
+			values[prefix+ChainIDEnv] = network.ChainID
+
+			if len(network.ERC20Contracts) > 0 {
+				bz, err := json.Marshal(network.ERC20Contracts)
+				if err != nil {
+					return nil, err
+				}
+
+				values[prefix+ERC20ContractsEnv] = string(bz)
+			}
+
+			networks[i] = network.Network
+		}
+
+		values[AdditionalNetworksEnv] = strings.Join(networks, ",")
+	}
+
+	if len(c.ChainIDs) > 0 {
+		networks := make([]string, 0, len(c.ChainIDs))
+		for network, chainID := range c.ChainIDs {
+			if strings.ContainsAny(network+chainID, ",=") {
+				return nil, fmt.Errorf("invalid chain_ids entry '%s'", network)
+			}
+
+			networks = append(networks, network)
+		}
+		sort.Strings(networks)
+
+		pairs := make([]string, len(networks))
+		for i, network := range networks {
+			pairs[i] = network + "=" + c.ChainIDs[network]
+		}
+
+		values[ChainIDsEnv] = strings.Join(pairs, ",")
+	}
+
+	if len(c.Currencies) > 0 {
+		bz, err := json.Marshal(c.Currencies)
+		if err != nil {
+			return nil, err
+		}
+
+		values[CurrenciesEnv] = string(bz)
+	}
+
+	if len(c.ERC20Contracts) > 0 {
+		bz, err := json.Marshal(c.ERC20Contracts)
+		if err != nil {
+			return nil, err
+		}
+
+		values[ERC20ContractsEnv] = string(bz)
+	}
+
+	if len(c.GasPriceCurve) > 0 {
+		prices := make([]string, len(c.GasPriceCurve))
+		for i, price := range c.GasPriceCurve {
+			prices[i] = strconv.FormatFloat(price, 'f', -1, 64)
+		}
+
+		values[GasPriceCurveEnv] = strings.Join(prices, ",")
+	}
+
+	return values, nil
+}
+
+// configFileError returns a decoding error including the line of each invalid or unknown key
+func configFileError(path string, err error) error {
+	var strictErr *toml.StrictMissingError
+	if errors.As(err, &strictErr) {
+		keys := make([]string, len(strictErr.Errors))
+		for i, keyErr := range strictErr.Errors {
+			line, _ := keyErr.Position()
+			keys[i] = fmt.Sprintf("%s (line %d)", strings.Join(keyErr.Key(), "."), line)
+		}
+
+		return fmt.Errorf("unknown keys in config file %s: %s", path, strings.Join(keys, ", "))
+	}
+
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		line, column := decodeErr.Position()
+		return fmt.Errorf("invalid config file %s at line %d, column %d: %w", path, line, column, err)
+	}
+
+	return fmt.Errorf("invalid config file %s: %w", path, err)
+}
@@ -0,0 +1,237 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `
+mode = "online"
+network = "kava-testnet"
+port = 8000
+lenient_parsing = true
+block_cache_size = 0
+mempool_tx_limit = 50
+gas_price_curve = [0.002, 0.01, 0.1]
+
+[rpc]
+url = "http://node-0:26657"
+failover_urls = ["http://node-1:26657", "http://archive:26657"]
+
+[chain_ids]
+kava-testnet = "kava_2221-17000"
+
+[timeouts]
+read = "10s"
+write = "1m"
+idle = "45s"
+rpc = "20s"
+readiness_max_block_age = "30s"
+ibc_transfer = "30m"
+
+[currencies."erc20/tether/usdt"]
+symbol = "USDT"
+decimals = 6
+
+[erc20_contracts."0x919C1c267BC06a7039e03fcc2eF738525769109c"]
+symbol = "USDt"
+decimals = 6
+`
+
+func writeConfigFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func TestFileLoader(t *testing.T) {
+	loader, err := NewFileLoader(writeConfigFile(t, testConfigFile))
+	require.NoError(t, err)
+
+	cfg, err := LoadConfig(loader)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Configuration{
+		Mode: Online,
+		NetworkIdentifier: &types.NetworkIdentifier{
+			Blockchain: "Kava",
+			Network:    "kava-testnet",
+		},
+		Port:                 8000,
+		KavaRPCURL:           "http://node-0:26657",
+		KavaRPCFailoverURLs:  []string{"http://node-1:26657", "http://archive:26657"},
+		ChainID:              "kava_2221-17000",
+		Currencies:           map[string]*types.Currency{"erc20/tether/usdt": {Symbol: "USDT", Decimals: 6}},
+		ERC20Contracts:       map[string]*types.Currency{"0x919C1c267BC06a7039e03fcc2eF738525769109c": {Symbol: "USDt", Decimals: 6}},
+		LenientParsing:       true,
+		BlockCacheSize:       0,
+		MempoolTxLimit:       50,
+		ReadinessMaxBlockAge: 30 * time.Second,
+		GasPriceCurve:        []float64{0.002, 0.01, 0.1},
+		ReadTimeout:          10 * time.Second,
+		WriteTimeout:         time.Minute,
+		IdleTimeout:          45 * time.Second,
+		KavaRPCTimeout:       20 * time.Second,
+		IBCTransferTimeout:   30 * time.Minute,
+	}, cfg)
+}
+
+func TestMultiLoader_EnvOverridesFile(t *testing.T) {
+	fileLoader, err := NewFileLoader(writeConfigFile(t, testConfigFile))
+	require.NoError(t, err)
+
+	envLoader := &testEnvLoader{Env: map[string]string{
+		PortEnv:       "9000",
+		KavaRPCURLEnv: "http://node-2:26657",
+		ChainIDEnv:    "kava_2221-16000",
+	}}
+
+	cfg, err := LoadConfig(&MultiLoader{Loaders: []ConfigLoader{envLoader, fileLoader}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.Port)
+	assert.Equal(t, "http://node-2:26657", cfg.KavaRPCURL)
+	assert.Equal(t, "kava_2221-16000", cfg.ChainID)
+	assert.Equal(t, []string{"http://node-1:26657", "http://archive:26657"}, cfg.KavaRPCFailoverURLs)
+}
+
+func TestFileLoader_Errors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contents    string
+		expectedErr string
+	}{
+		{
+			name:        "unknown keys",
+			contents:    "mode = \"online\"\nprot = 8000\n\n[rpc]\nurls = []\n",
+			expectedErr: "unknown keys in config file %s: prot (line 2), rpc.urls (line 5)",
+		},
+		{
+			name:        "unknown currency field",
+			contents:    "[currencies.ukava]\nsymbol = \"KAVA\"\nprecision = 6\n",
+			expectedErr: "unknown keys in config file %s: currencies.ukava.precision (line 3)",
+		},
+		{
+			name:        "invalid type",
+			contents:    "port = \"8000\"\n",
+			expectedErr: "invalid config file %s at line 1, column 8: toml: cannot decode TOML string into struct field configuration.fileConfig.Port of type *int",
+		},
+		{
+			name:        "invalid toml",
+			contents:    "mode = \n",
+			expectedErr: "invalid config file %s at line 1, column 8: toml: incomplete number",
+		},
+		{
+			name:        "invalid chain id mapping",
+			contents:    "[chain_ids]\n\"kava-testnet,kava-mainnet\" = \"kava_2222-10\"\n",
+			expectedErr: "invalid config file %s: invalid chain_ids entry 'kava-testnet,kava-mainnet'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeConfigFile(t, tc.contents)
+
+			loader, err := NewFileLoader(path)
+			assert.Nil(t, loader)
+			assert.EqualError(t, err, fmt.Sprintf(tc.expectedErr, path))
+		})
+	}
+
+	_, err := NewFileLoader(filepath.Join(t.TempDir(), "missing.toml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFileLoader_Networks(t *testing.T) {
+	loader, err := NewFileLoader(writeConfigFile(t, `
+mode = "online"
+network = "kava-mainnet"
+port = 8000
+
+[rpc]
+url = "http://mainnet:26657"
+
+[[networks]]
+network = "kava-testnet"
+
+[networks.rpc]
+url = "http://testnet:26657"
+failover_urls = ["http://testnet-archive:26657"]
+
+[networks.erc20_contracts."0xfA9343C3897324496A05fC75abeD6bAC29f8A40f"]
+symbol = "USDC"
+decimals = 6
+
+[[networks]]
+network = "kava-devnet"
+chain_id = "kavadevnet_2225-1"
+
+[networks.rpc]
+url = "http://devnet:26657"
+`))
+	require.NoError(t, err)
+
+	cfg, err := LoadConfig(loader)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.AdditionalNetworks, 2)
+	assert.Equal(t, "kava-testnet", cfg.AdditionalNetworks[0].NetworkIdentifier.Network)
+	assert.Equal(t, "http://testnet:26657", cfg.AdditionalNetworks[0].KavaRPCURL)
+	assert.Equal(t, []string{"http://testnet-archive:26657"}, cfg.AdditionalNetworks[0].KavaRPCFailoverURLs)
+	assert.Equal(t, "kava_2221-16000", cfg.AdditionalNetworks[0].ChainID)
+	assert.Equal(t, map[string]*types.Currency{
+		"0xfA9343C3897324496A05fC75abeD6bAC29f8A40f": {Symbol: "USDC", Decimals: 6},
+	}, cfg.AdditionalNetworks[0].ERC20Contracts)
+	assert.Nil(t, cfg.AdditionalNetworks[1].ERC20Contracts)
+	assert.Equal(t, "kava-devnet", cfg.AdditionalNetworks[1].NetworkIdentifier.Network)
+	assert.Equal(t, "kavadevnet_2225-1", cfg.AdditionalNetworks[1].ChainID)
+
+	// environment variables override values of additional networks
+	envLoader := &testEnvLoader{Env: map[string]string{"KAVA_DEVNET_KAVA_RPC_URL": "http://devnet-2:26657"}}
+	cfg, err = LoadConfig(&MultiLoader{Loaders: []ConfigLoader{envLoader, loader}})
+	require.NoError(t, err)
+	assert.Equal(t, "http://devnet-2:26657", cfg.AdditionalNetworks[1].KavaRPCURL)
+}
+
+func TestFileLoader_InvalidValues(t *testing.T) {
+	loader, err := NewFileLoader(writeConfigFile(t, testConfigFile))
+	require.NoError(t, err)
+
+	envLoader := &testEnvLoader{Env: map[string]string{
+		ReadTimeoutEnv: "soon",
+	}}
+
+	// values from files are validated the same as environment variables
+	cfg, err := LoadConfig(&MultiLoader{Loaders: []ConfigLoader{envLoader, loader}})
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid READ_TIMEOUT 'soon'")
+
+	loader, err = NewFileLoader(writeConfigFile(t, "mode = \"online\"\nnetwork = \"kava-testnet\"\nport = 8000\ngas_price_curve = [0.1, 0.01]\n\n[rpc]\nurl = \"http://node-0:26657\"\n"))
+	require.NoError(t, err)
+
+	cfg, err = LoadConfig(loader)
+	assert.Nil(t, cfg)
+	assert.EqualError(t, err, "invalid GAS_PRICE_CURVE '0.1,0.01'")
+}
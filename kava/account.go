@@ -162,17 +162,11 @@ func (b *rpcVestingBalance) delegated(ctx context.Context) (sdk.Coins, sdk.Coins
 		return nil, nil, err
 	}
 
-	delegated := delegatedCoins.AmountOf(stakingDenom)
-	unbonding := unbondingCoins.AmountOf(stakingDenom)
-	totalStaked := delegated.Add(unbonding)
-	delegatedFree := b.vacc.GetDelegatedFree().AmountOf(stakingDenom)
-
-	// total number of staked and unbonding tokens considered to be liquid
-	totalFree := sdkmath.MinInt(totalStaked, delegatedFree)
-	// any coins that are not considered liquid, are vesting up to a maximum of delegated
-	stakedVesting := sdkmath.MinInt(totalStaked.Sub(totalFree), delegated)
-	// staked free coins are left over
-	stakedFree := delegated.Sub(stakedVesting)
+	stakedFree, stakedVesting := splitDelegated(
+		b.vacc.GetDelegatedFree().AmountOf(stakingDenom),
+		delegatedCoins.AmountOf(stakingDenom),
+		unbondingCoins.AmountOf(stakingDenom),
+	)
 
 	liquidCoins := sdk.NewCoins(newKavaCoin(stakedFree))
 	vestingCoins := sdk.NewCoins(newKavaCoin(stakedVesting))
@@ -186,17 +180,40 @@ func (b *rpcVestingBalance) unbonding(ctx context.Context) (sdk.Coins, sdk.Coins
 		return nil, nil, err
 	}
 
-	unbonding := unbondingCoins.AmountOf(stakingDenom)
-	delegatedFree := b.vacc.GetDelegatedFree().AmountOf(stakingDenom)
-
-	unbondingFree := sdkmath.MinInt(delegatedFree, unbonding)
-	unbondingVesting := unbonding.Sub(unbondingFree)
+	unbondingFree, unbondingVesting := splitUnbonding(
+		b.vacc.GetDelegatedFree().AmountOf(stakingDenom),
+		unbondingCoins.AmountOf(stakingDenom),
+	)
 
 	liquidCoins := sdk.NewCoins(newKavaCoin(unbondingFree))
 	vestingCoins := sdk.NewCoins(newKavaCoin(unbondingVesting))
 	return liquidCoins, vestingCoins, nil
 }
 
+// splitDelegated returns the delegated coins of a vesting account that are free and vesting.  Staked
+// and unbonding coins are free up to the delegated free coins of the account, and unbonding coins
+// are considered free first.
+func splitDelegated(delegatedFree, delegated, unbonding sdkmath.Int) (sdkmath.Int, sdkmath.Int) {
+	totalStaked := delegated.Add(unbonding)
+
+	// total number of staked and unbonding tokens considered to be liquid
+	totalFree := sdkmath.MinInt(totalStaked, delegatedFree)
+	// any coins that are not considered liquid, are vesting up to a maximum of delegated
+	stakedVesting := sdkmath.MinInt(totalStaked.Sub(totalFree), delegated)
+	// staked free coins are left over
+	stakedFree := delegated.Sub(stakedVesting)
+
+	return stakedFree, stakedVesting
+}
+
+// splitUnbonding returns the unbonding coins of a vesting account that are free and vesting
+func splitUnbonding(delegatedFree, unbonding sdkmath.Int) (sdkmath.Int, sdkmath.Int) {
+	unbondingFree := sdkmath.MinInt(delegatedFree, unbonding)
+	unbondingVesting := unbonding.Sub(unbondingFree)
+
+	return unbondingFree, unbondingVesting
+}
+
 func (b *rpcVestingBalance) totalDelegated(ctx context.Context) (sdk.Coins, error) {
 	delegations, err := b.rpc.Delegations(ctx, b.vacc.GetAddress(), b.blockHeader.Height)
 	if err != nil {
@@ -599,3 +599,154 @@ func TestRPCAccountBalance_VestingAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestRPCAccountBalance_VestingStakingOperations(t *testing.T) {
+	ctx := context.Background()
+	addr, err := sdk.AccAddressFromBech32("kava1vlpsrmdyuywvaqrv7rx6xga224sqfwz3fyfhwq")
+	require.NoError(t, err)
+
+	stakedSubAccounts := []string{
+		kava.AccLiquidDelegated,
+		kava.AccVestingDelegated,
+		kava.AccLiquidUnbonding,
+		kava.AccVestingUnbonding,
+	}
+
+	type stake struct {
+		delegatedFree, delegatedVesting, delegated, unbonding int64
+	}
+
+	// stakedBalances returns the staked sub-account balances of a vesting account reported by the balance service
+	stakedBalances := func(s stake) map[string]sdkmath.Int {
+		_, blockHeader, mockRPCClient, serviceFactory := setupFactory(t, time.Now())
+
+		acc := &vestingtypes.ContinuousVestingAccount{
+			BaseVestingAccount: &vestingtypes.BaseVestingAccount{
+				BaseAccount:      &authtypes.BaseAccount{Address: addr.String()},
+				OriginalVesting:  sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(1000000))),
+				DelegatedFree:    sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(s.delegatedFree))),
+				DelegatedVesting: sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(s.delegatedVesting))),
+				EndTime:          time.Now().Add(time.Hour).Unix(),
+			},
+			StartTime: time.Now().Add(-time.Hour).Unix(),
+		}
+
+		mockRPCClient.On("Account", ctx, addr, blockHeader.Height).Return(acc, nil)
+		mockRPCClient.On("Balance", ctx, addr, blockHeader.Height).Return(sdk.NewCoins(), nil)
+		mockRPCClient.On("Delegations", ctx, addr, blockHeader.Height).Return(stakingtypes.DelegationResponses{
+			{Balance: sdk.NewCoin("ukava", sdkmath.NewInt(s.delegated))},
+		}, nil)
+		mockRPCClient.On("UnbondingDelegations", ctx, addr, blockHeader.Height).Return(stakingtypes.UnbondingDelegations{
+			{Entries: []stakingtypes.UnbondingDelegationEntry{{Balance: sdkmath.NewInt(s.unbonding)}}},
+		}, nil)
+
+		balanceService, err := serviceFactory(ctx, addr, blockHeader)
+		require.NoError(t, err)
+
+		balances := make(map[string]sdkmath.Int)
+		for _, subAccount := range stakedSubAccounts {
+			coins, _, err := balanceService.GetCoinsAndSequenceForSubAccount(ctx, &types.SubAccountIdentifier{Address: subAccount})
+			require.NoError(t, err)
+			balances[subAccount] = coins.AmountOf("ukava")
+		}
+
+		return balances
+	}
+
+	stakingEvent := func(eventType string, amount string) sdk.StringEvent {
+		return sdk.StringEvent{
+			Type: eventType,
+			Attributes: []sdk.Attribute{
+				{Key: stakingtypes.AttributeKeyValidator, Value: "kavavaloper1xy7hrjy9r0algz9w3gzm8u6mrpq97kwta747gj"},
+				{Key: stakingtypes.AttributeKeyDelegator, Value: addr.String()},
+				{Key: sdk.AttributeKeyAmount, Value: amount},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		event  sdk.StringEvent
+		before stake
+		after  stake
+	}{
+		{
+			name:   "delegate vesting and free coins",
+			event:  stakingEvent(stakingtypes.EventTypeDelegate, "1250000ukava"),
+			before: stake{0, 0, 0, 0},
+			after:  stake{250000, 1000000, 1250000, 0},
+		},
+		{
+			name:   "delegate free coins after vesting coins are delegated",
+			event:  stakingEvent(stakingtypes.EventTypeDelegate, "500000ukava"),
+			before: stake{0, 1000000, 1000000, 0},
+			after:  stake{500000, 1000000, 1500000, 0},
+		},
+		{
+			name:   "unbond vesting and free coins",
+			event:  stakingEvent(stakingtypes.EventTypeUnbond, "500000ukava"),
+			before: stake{250000, 1000000, 1250000, 0},
+			after:  stake{250000, 1000000, 750000, 500000},
+		},
+		{
+			name:   "cancel unbonding vesting and free coins",
+			event:  stakingEvent(stakingtypes.EventTypeCancelUnbondingDelegation, "500000ukava"),
+			before: stake{250000, 1000000, 750000, 500000},
+			after:  stake{250000, 1000000, 1250000, 0},
+		},
+		{
+			name:   "complete unbonding vesting and free coins",
+			event:  stakingEvent(stakingtypes.EventTypeCompleteUnbonding, "500000ukava"),
+			before: stake{250000, 1000000, 750000, 500000},
+			after:  stake{0, 750000, 750000, 0},
+		},
+	}
+
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := kava.SuccessStatus
+			ops, err := kava.EventToOperations(registry, tc.event, &status, 0)
+			require.NoError(t, err)
+			require.NotEmpty(t, ops)
+
+			stakes := kava.NewVestingStakes(func(delegator sdk.AccAddress) (*kava.VestingStake, error) {
+				require.Equal(t, addr, delegator)
+				return &kava.VestingStake{
+					Vesting:          sdkmath.NewInt(1000000),
+					DelegatedFree:    sdkmath.NewInt(tc.before.delegatedFree),
+					DelegatedVesting: sdkmath.NewInt(tc.before.delegatedVesting),
+					Delegated:        sdkmath.NewInt(tc.before.delegated),
+					Unbonding:        sdkmath.NewInt(tc.before.unbonding),
+				}, nil
+			})
+			ops, err = stakes.SplitOperations(registry, ops)
+			require.NoError(t, err)
+
+			opChanges := make(map[string]sdkmath.Int)
+			for _, subAccount := range stakedSubAccounts {
+				opChanges[subAccount] = sdkmath.ZeroInt()
+			}
+			for i, op := range ops {
+				assert.Equal(t, int64(i), op.OperationIdentifier.Index)
+				require.NotNil(t, op.Account.SubAccount)
+				amount, ok := sdkmath.NewIntFromString(op.Amount.Value)
+				require.True(t, ok)
+				opChanges[op.Account.SubAccount.Address] = opChanges[op.Account.SubAccount.Address].Add(amount)
+			}
+
+			before := stakedBalances(tc.before)
+			after := stakedBalances(tc.after)
+
+			mismatches := 0
+			for _, subAccount := range stakedSubAccounts {
+				balanceChange := after[subAccount].Sub(before[subAccount])
+				if !assert.True(t, balanceChange.Equal(opChanges[subAccount]), "%s changed by %s with operations of %s", subAccount, balanceChange, opChanges[subAccount]) {
+					mismatches++
+				}
+			}
+			assert.Zero(t, mismatches)
+		})
+	}
+}
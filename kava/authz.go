@@ -0,0 +1,41 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+// granteeMetadataKey is the operation metadata key for the grantee of an authz grant, or the
+// grantee executing messages on behalf of a granter
+const granteeMetadataKey = "grantee"
+
+// markAuthzExecOperations adds the grantee of an authz exec to the metadata of the debit operations.
+// The executed messages emit events for the granter, so debits are already attributed to it.
+func markAuthzExecOperations(ops []*types.Operation, msg *authz.MsgExec) {
+	for _, op := range ops {
+		if op.Amount == nil || !strings.HasPrefix(op.Amount.Value, "-") {
+			continue
+		}
+
+		if op.Metadata == nil {
+			op.Metadata = make(map[string]interface{})
+		}
+		op.Metadata[granteeMetadataKey] = msg.Grantee
+	}
+}
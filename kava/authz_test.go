@@ -0,0 +1,90 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgToOperations_AuthzExec(t *testing.T) {
+	granter := testAddresses[0]
+	grantee := testAddresses[1]
+	recipient := testAddresses[2]
+
+	send := banktypes.NewMsgSend(getAccAddr(t, granter), getAccAddr(t, recipient), sdk.NewCoins(sdk.NewInt64Coin("ukava", 5000000)))
+	msg := authz.NewMsgExec(getAccAddr(t, grantee), []sdk.Msg{send})
+	kava := &types.Currency{Symbol: "KAVA", Decimals: 6}
+
+	t.Run("success", func(t *testing.T) {
+		status := SuccessStatus
+		log := sdk.ABCIMessageLog{
+			Events: sdk.StringEvents{
+				{Type: banktypes.EventTypeTransfer, Attributes: []sdk.Attribute{
+					{Key: banktypes.AttributeKeyRecipient, Value: recipient},
+					{Key: banktypes.AttributeKeySender, Value: granter},
+					{Key: sdk.AttributeKeyAmount, Value: "5000000ukava"},
+					{Key: "authz_msg_index", Value: "0"},
+				}},
+			},
+		}
+
+		ops, err := MsgToOperations(testRegistry, &msg, log, &status, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []*types.Operation{
+			{
+				OperationIdentifier: newOpID(0),
+				Type:                TransferOpType,
+				Status:              &status,
+				Account:             newAccountID(granter),
+				Amount:              &types.Amount{Value: "-5000000", Currency: kava},
+				Metadata:            map[string]interface{}{granteeMetadataKey: grantee},
+			},
+			{
+				OperationIdentifier: newOpID(1),
+				RelatedOperations:   []*types.OperationIdentifier{newOpID(0)},
+				Type:                TransferOpType,
+				Status:              &status,
+				Account:             newAccountID(recipient),
+				Amount:              &types.Amount{Value: "5000000", Currency: kava},
+			},
+		}, ops)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		status := FailureStatus
+
+		ops, err := MsgToOperations(testRegistry, &msg, sdk.ABCIMessageLog{}, &status, 2)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+
+		assert.Equal(t, int64(2), ops[0].OperationIdentifier.Index)
+		assert.Equal(t, &status, ops[0].Status)
+		assert.Equal(t, newAccountID(granter), ops[0].Account)
+		assert.Equal(t, "-5000000", ops[0].Amount.Value)
+		assert.Equal(t, map[string]interface{}{granteeMetadataKey: grantee}, ops[0].Metadata)
+
+		assert.Equal(t, int64(3), ops[1].OperationIdentifier.Index)
+		assert.Equal(t, newAccountID(recipient), ops[1].Account)
+		assert.Equal(t, "5000000", ops[1].Amount.Value)
+		assert.Nil(t, ops[1].Metadata)
+	})
+}
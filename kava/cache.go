@@ -0,0 +1,189 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	// BlockCacheName is the cache name for blocks fetched by height
+	BlockCacheName = "block"
+	// BlockByHashCacheName is the cache name for blocks fetched by hash
+	BlockByHashCacheName = "block_by_hash"
+	// BlockResultsCacheName is the cache name for block results fetched by height
+	BlockResultsCacheName = "block_results"
+	// BlockResponseCacheName is the cache name for converted rosetta block responses
+	BlockResponseCacheName = "block_response"
+)
+
+// CacheStats contains the number of hits and misses of a cache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// lruCache is a fixed size least recently used cache that counts hits and misses
+type lruCache[K comparable, V any] struct {
+	cache  *lru.Cache[K, V]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newLRUCache[K comparable, V any](size int) (*lruCache[K, V], error) {
+	cache, err := lru.New[K, V](size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lruCache[K, V]{cache: cache}, nil
+}
+
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	value, ok := c.cache.Get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+
+	return value, ok
+}
+
+func (c *lruCache[K, V]) add(key K, value V) {
+	c.cache.Add(key, value)
+}
+
+func (c *lruCache[K, V]) stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// CachedRPCClient wraps a RPCClient and caches finalized blocks and block results.
+//
+// Only lookups for a specific height or hash are cached; requests for the latest
+// block are always passed through to the wrapped client.
+type CachedRPCClient struct {
+	RPCClient
+	blocks       *lruCache[int64, *ctypes.ResultBlock]
+	blocksByHash *lruCache[string, *ctypes.ResultBlock]
+	blockResults *lruCache[int64, *ctypes.ResultBlockResults]
+}
+
+var _ RPCClient = (*CachedRPCClient)(nil)
+
+// NewCachedRPCClient returns a CachedRPCClient holding up to size entries in each cache
+func NewCachedRPCClient(rpc RPCClient, size int) (*CachedRPCClient, error) {
+	blocks, err := newLRUCache[int64, *ctypes.ResultBlock](size)
+	if err != nil {
+		return nil, err
+	}
+
+	blocksByHash, err := newLRUCache[string, *ctypes.ResultBlock](size)
+	if err != nil {
+		return nil, err
+	}
+
+	blockResults, err := newLRUCache[int64, *ctypes.ResultBlockResults](size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachedRPCClient{
+		RPCClient:    rpc,
+		blocks:       blocks,
+		blocksByHash: blocksByHash,
+		blockResults: blockResults,
+	}, nil
+}
+
+// Block returns the block at a height, using the cache unless the latest block is requested
+func (c *CachedRPCClient) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	if height == nil {
+		return c.RPCClient.Block(ctx, height)
+	}
+
+	if block, ok := c.blocks.get(*height); ok {
+		return block, nil
+	}
+
+	block, err := c.RPCClient.Block(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	c.blocks.add(*height, block)
+
+	return block, nil
+}
+
+// BlockByHash returns the block with a hash, using the cache when possible
+func (c *CachedRPCClient) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBlock, error) {
+	key := strings.ToUpper(hex.EncodeToString(hash))
+
+	if block, ok := c.blocksByHash.get(key); ok {
+		return block, nil
+	}
+
+	block, err := c.RPCClient.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// an unknown hash returns an empty block instead of an error
+	if block.Block != nil {
+		c.blocksByHash.add(key, block)
+	}
+
+	return block, nil
+}
+
+// BlockResults returns the results of the block at a height, using the cache unless
+// the latest block results are requested
+func (c *CachedRPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	if height == nil {
+		return c.RPCClient.BlockResults(ctx, height)
+	}
+
+	if results, ok := c.blockResults.get(*height); ok {
+		return results, nil
+	}
+
+	results, err := c.RPCClient.BlockResults(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	c.blockResults.add(*height, results)
+
+	return results, nil
+}
+
+// CacheStats returns the hit and miss counts of each cache by name
+func (c *CachedRPCClient) CacheStats() map[string]CacheStats {
+	return map[string]CacheStats{
+		BlockCacheName:        c.blocks.stats(),
+		BlockByHashCacheName:  c.blocksByHash.stats(),
+		BlockResultsCacheName: c.blockResults.stats(),
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+	"github.com/kava-labs/rosetta-kava/kava/mocks"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockResultBlock(height int64) *ctypes.ResultBlock {
+	return &ctypes.ResultBlock{
+		BlockID: tmtypes.BlockID{Hash: []byte{byte(height)}},
+		Block: &tmtypes.Block{
+			Header: tmtypes.Header{Height: height},
+		},
+	}
+}
+
+func TestCachedRPCClient_Block(t *testing.T) {
+	ctx := context.Background()
+	rpc := &mocks.RPCClient{}
+
+	cached, err := kava.NewCachedRPCClient(rpc, 2)
+	require.NoError(t, err)
+
+	height := int64(100)
+	rpc.On("Block", ctx, &height).Return(mockResultBlock(height), nil).Once()
+
+	for i := 0; i < 3; i++ {
+		block, err := cached.Block(ctx, &height)
+		require.NoError(t, err)
+		assert.Equal(t, height, block.Block.Height)
+	}
+
+	// the latest block is never cached
+	rpc.On("Block", ctx, (*int64)(nil)).Return(mockResultBlock(101), nil).Twice()
+	for i := 0; i < 2; i++ {
+		_, err := cached.Block(ctx, nil)
+		require.NoError(t, err)
+	}
+
+	// errors are not cached
+	missing := int64(1000)
+	rpcErr := errors.New("height 1000 must be less than or equal to the current blockchain height 101")
+	rpc.On("Block", ctx, &missing).Return(nil, rpcErr).Once()
+	rpc.On("Block", ctx, &missing).Return(mockResultBlock(missing), nil).Once()
+
+	_, err = cached.Block(ctx, &missing)
+	assert.Equal(t, rpcErr, err)
+	block, err := cached.Block(ctx, &missing)
+	require.NoError(t, err)
+	assert.Equal(t, missing, block.Block.Height)
+
+	assert.Equal(t, kava.CacheStats{Hits: 2, Misses: 3}, cached.CacheStats()[kava.BlockCacheName])
+	rpc.AssertExpectations(t)
+}
+
+func TestCachedRPCClient_BlockByHash(t *testing.T) {
+	ctx := context.Background()
+	rpc := &mocks.RPCClient{}
+
+	cached, err := kava.NewCachedRPCClient(rpc, 2)
+	require.NoError(t, err)
+
+	resultBlock := mockResultBlock(100)
+	rpc.On("BlockByHash", ctx, []byte(resultBlock.BlockID.Hash)).Return(resultBlock, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		block, err := cached.BlockByHash(ctx, resultBlock.BlockID.Hash)
+		require.NoError(t, err)
+		assert.Equal(t, resultBlock, block)
+	}
+
+	// unknown hashes return an empty block which may be found later
+	unknownHash := []byte{0xff}
+	rpc.On("BlockByHash", ctx, unknownHash).Return(&ctypes.ResultBlock{}, nil).Twice()
+	for i := 0; i < 2; i++ {
+		block, err := cached.BlockByHash(ctx, unknownHash)
+		require.NoError(t, err)
+		assert.Nil(t, block.Block)
+	}
+
+	assert.Equal(t, kava.CacheStats{Hits: 1, Misses: 3}, cached.CacheStats()[kava.BlockByHashCacheName])
+	rpc.AssertExpectations(t)
+}
+
+func TestCachedRPCClient_BlockResults(t *testing.T) {
+	ctx := context.Background()
+	rpc := &mocks.RPCClient{}
+
+	cached, err := kava.NewCachedRPCClient(rpc, 1)
+	require.NoError(t, err)
+
+	height1 := int64(100)
+	height2 := int64(101)
+	rpc.On("BlockResults", ctx, &height1).Return(&ctypes.ResultBlockResults{Height: height1}, nil).Twice()
+	rpc.On("BlockResults", ctx, &height2).Return(&ctypes.ResultBlockResults{Height: height2}, nil).Once()
+	rpc.On("BlockResults", ctx, (*int64)(nil)).Return(&ctypes.ResultBlockResults{Height: height2}, nil).Once()
+
+	_, err = cached.BlockResults(ctx, &height1)
+	require.NoError(t, err)
+	_, err = cached.BlockResults(ctx, &height1)
+	require.NoError(t, err)
+
+	// the size limit evicts the least recently used results
+	_, err = cached.BlockResults(ctx, &height2)
+	require.NoError(t, err)
+	results, err := cached.BlockResults(ctx, &height1)
+	require.NoError(t, err)
+	assert.Equal(t, height1, results.Height)
+
+	_, err = cached.BlockResults(ctx, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, kava.CacheStats{Hits: 1, Misses: 3}, cached.CacheStats()[kava.BlockResultsCacheName])
+	rpc.AssertExpectations(t)
+}
+
+func TestNewCachedRPCClient_InvalidSize(t *testing.T) {
+	_, err := kava.NewCachedRPCClient(&mocks.RPCClient{}, 0)
+	assert.Error(t, err)
+}
+
+func TestClient_BlockResponseCache(t *testing.T) {
+	ctx := context.Background()
+	rpc := &mocks.RPCClient{}
+
+	client, err := kava.NewClient(rpc, nil, kava.WithBlockResponseCache(10))
+	require.NoError(t, err)
+
+	height := int64(100)
+	resultBlock := mockResultBlock(height)
+	rpc.On("Block", ctx, &height).Return(resultBlock, nil).Once()
+	rpc.On("BlockResults", ctx, &height).Return(&ctypes.ResultBlockResults{Height: height}, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		blockResponse, err := client.Block(ctx, &types.PartialBlockIdentifier{Index: &height})
+		require.NoError(t, err)
+		assert.Equal(t, height, blockResponse.Block.BlockIdentifier.Index)
+	}
+
+	// requests by hash are not served from the cache
+	hash := resultBlock.BlockID.Hash.String()
+	rpc.On("BlockByHash", ctx, []byte(resultBlock.BlockID.Hash)).Return(resultBlock, nil).Once()
+	_, err = client.Block(ctx, &types.PartialBlockIdentifier{Index: &height, Hash: &hash})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]kava.CacheStats{
+		kava.BlockResponseCacheName: {Hits: 1, Misses: 1},
+	}, client.CacheStats())
+	rpc.AssertExpectations(t)
+}
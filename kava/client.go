@@ -19,6 +19,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"regexp"
 	"strconv"
@@ -36,28 +37,113 @@ import (
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/common"
 	kava "github.com/kava-labs/kava/app"
 	"github.com/kava-labs/kava/app/params"
 )
 
 var noBlockResultsForHeight = regexp.MustCompile(`could not find results for height #(\d+)`)
 
+// defaultMempoolTxLimit is the maximum number of unconfirmed txs returned by the tendermint rpc, which
+// does not page or search the mempool by hash
+const defaultMempoolTxLimit = 100
+
+// parseErrorMetadataKey is the transaction metadata key flagging a transaction that could not be parsed
+const parseErrorMetadataKey = "parse_error"
+
 // Client implements services.Client interface for communicating with the kava chain
 type Client struct {
 	rpc            RPCClient
 	encodingConfig params.EncodingConfig
 	balanceFactory BalanceServiceFactory
+	registry       *CurrencyRegistry
+	lenientParsing bool
+	blockCacheSize int
+	mempoolTxLimit int
+	blockResponses *lruCache[int64, *types.BlockResponse]
+}
+
+// ClientOption configures optional behavior of a Client
+type ClientOption func(*Client)
+
+// WithCurrencyRegistry converts between denoms and currencies with the registry of the
+// client's network instead of a registry of the default Currencies.
+func WithCurrencyRegistry(registry *CurrencyRegistry) ClientOption {
+	return func(c *Client) {
+		c.registry = registry
+	}
+}
+
+// WithLenientParsing returns transactions that can not be parsed without operations
+// and flags them in their metadata instead of failing the block.
+func WithLenientParsing(lenient bool) ClientOption {
+	return func(c *Client) {
+		c.lenientParsing = lenient
+	}
+}
+
+// WithBlockResponseCache caches up to size converted block responses requested by
+// index.  A size of zero disables the cache.
+func WithBlockResponseCache(size int) ClientOption {
+	return func(c *Client) {
+		c.blockCacheSize = size
+	}
+}
+
+// WithMempoolTxLimit requests up to limit unconfirmed transactions for the mempool endpoints
+// instead of defaultMempoolTxLimit.  The node returns at most 100 transactions regardless.
+func WithMempoolTxLimit(limit int) ClientOption {
+	return func(c *Client) {
+		c.mempoolTxLimit = limit
+	}
 }
 
 // NewClient initialized a new Client with the provided rpc client
-func NewClient(rpc RPCClient, balanceServiceFactory BalanceServiceFactory) (*Client, error) {
+func NewClient(rpc RPCClient, balanceServiceFactory BalanceServiceFactory, opts ...ClientOption) (*Client, error) {
 	encodingConfig := kava.MakeEncodingConfig()
 
-	return &Client{
+	client := &Client{
 		rpc:            rpc,
 		encodingConfig: encodingConfig,
 		balanceFactory: balanceServiceFactory,
-	}, nil
+		registry:       NewCurrencyRegistry(Currencies),
+		mempoolTxLimit: defaultMempoolTxLimit,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if client.blockCacheSize > 0 {
+		blockResponses, err := newLRUCache[int64, *types.BlockResponse](client.blockCacheSize)
+		if err != nil {
+			return nil, err
+		}
+
+		client.blockResponses = blockResponses
+	}
+
+	return client, nil
+}
+
+// CacheStats returns the hit and miss counts of the block response cache and,
+// when the rpc client is cached, the rpc caches by name
+func (c *Client) CacheStats() map[string]CacheStats {
+	stats := make(map[string]CacheStats)
+
+	if cached, ok := c.rpc.(*CachedRPCClient); ok {
+		for name, cacheStats := range cached.CacheStats() {
+			stats[name] = cacheStats
+		}
+	}
+
+	if c.blockResponses != nil {
+		stats[BlockResponseCacheName] = c.blockResponses.stats()
+	}
+
+	return stats
 }
 
 // Status fetches latest status from a kava node and returns the results
@@ -121,6 +207,16 @@ func (c *Client) Status(ctx context.Context) (
 	return currentBlock, currentTime, genesisBlock, syncStatus, peers, nil
 }
 
+// SyncInfo returns the sync info reported by the node, without the additional requests made by Status
+func (c *Client) SyncInfo(ctx context.Context) (*ctypes.SyncInfo, error) {
+	resultStatus, err := c.rpc.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resultStatus.SyncInfo, nil
+}
+
 // Account returns the account for the provided address at the latest block height
 func (c *Client) Account(ctx context.Context, address sdk.AccAddress) (authtypes.AccountI, error) {
 	account, err := c.rpc.Account(ctx, address, 0)
@@ -131,6 +227,73 @@ func (c *Client) Account(ctx context.Context, address sdk.AccAddress) (authtypes
 	return account, nil
 }
 
+// Delegations returns the delegations for the provided address at the latest block height
+func (c *Client) Delegations(ctx context.Context, address sdk.AccAddress) (stakingtypes.DelegationResponses, error) {
+	return c.rpc.Delegations(ctx, address, 0)
+}
+
+// UnbondingDelegations returns the unbonding delegations for the provided address at the latest block height
+func (c *Client) UnbondingDelegations(ctx context.Context, address sdk.AccAddress) (stakingtypes.UnbondingDelegations, error) {
+	return c.rpc.UnbondingDelegations(ctx, address, 0)
+}
+
+// Validators returns the staking validators with the provided bond status at the latest block height,
+// or all validators when status is empty
+func (c *Client) Validators(ctx context.Context, status string) (stakingtypes.Validators, error) {
+	return c.rpc.StakingValidators(ctx, status, 0)
+}
+
+// DelegationRewards returns the outstanding staking rewards for the provided address at the latest block height
+func (c *Client) DelegationRewards(ctx context.Context, address sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	return c.rpc.DelegationRewards(ctx, address, 0)
+}
+
+// SimulationResult is the result of simulating a transaction at the latest block height
+type SimulationResult struct {
+	GasInfo sdk.GasInfo
+	Log     string
+	Events  sdk.StringEvents
+	// Operations are the operations the transaction would include if it were delivered
+	Operations []*types.Operation
+	// Err is set when the transaction fails, in which case no gas info or events are returned
+	Err *SimulationError
+}
+
+// SimulateTransaction simulates a transaction at the latest block height, returning the
+// operations the transaction would include if it were delivered.  Transactions that fail
+// simulation return a result with the abci error instead of an error.
+func (c *Client) SimulateTransaction(ctx context.Context, tx authsigning.Tx) (*SimulationResult, error) {
+	var result abci.ResponseDeliverTx
+	simulation := &SimulationResult{}
+
+	simResp, err := c.rpc.SimulateTx(ctx, tx)
+	if err != nil {
+		var simErr *SimulationError
+		if !errors.As(err, &simErr) {
+			return nil, err
+		}
+
+		simulation.Err = simErr
+		result = abci.ResponseDeliverTx{Codespace: simErr.Codespace, Code: simErr.Code, Log: simErr.Log}
+	} else {
+		simulation.GasInfo = simResp.GasInfo
+
+		if simResp.Result != nil {
+			simulation.Log = simResp.Result.Log
+			simulation.Events = stringifyEvents(simResp.Result.Events)
+			result = abci.ResponseDeliverTx{Log: simResp.Result.Log, Events: simResp.Result.Events}
+		}
+	}
+
+	operations, err := c.getOperationsForTransaction(tx, &result)
+	if err != nil {
+		return nil, err
+	}
+	simulation.Operations = operations
+
+	return simulation, nil
+}
+
 // EstimateGas returns a gas wanted estimate from a tx with a provided adjustment
 func (c *Client) EstimateGas(ctx context.Context, tx authsigning.Tx, adjustment float64) (uint64, error) {
 	simResp, err := c.rpc.SimulateTx(ctx, tx)
@@ -172,6 +335,16 @@ func (c *Client) Balance(
 
 	balances := c.getBalancesAndFilterByCurrency(coins, currencies)
 
+	// erc20 operations are only returned for the account itself, never a sub-account
+	if accountIdentifier.SubAccount == nil {
+		erc20Balances, err := c.getERC20Balances(ctx, addr, block.Block.Header.Height, currencies)
+		if err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, erc20Balances...)
+	}
+
 	return &types.AccountBalanceResponse{
 		BlockIdentifier: &types.BlockIdentifier{
 			Index: block.Block.Header.Height,
@@ -191,15 +364,22 @@ func (c *Client) getBalancesAndFilterByCurrency(
 	var currencyLookup map[string]*types.Currency
 
 	if currencies == nil {
-		currencyLookup = Currencies
+		// all registered currencies and any other currency held by the account
+		currencyLookup = c.registry.Currencies()
+
+		for _, coin := range coins {
+			if currency, ok := c.registry.Currency(coin.Denom); ok {
+				currencyLookup[coin.Denom] = currency
+			}
+		}
 	} else {
 		currencyLookup = make(map[string]*types.Currency)
 
 		for _, currency := range currencies {
-			denom, ok := Denoms[currency.Symbol]
+			denom, ok := c.registry.Denom(currency)
 
 			if ok {
-				currencyLookup[denom] = Currencies[denom]
+				currencyLookup[denom], _ = c.registry.Currency(denom)
 			}
 		}
 	}
@@ -218,11 +398,63 @@ func (c *Client) getBalancesAndFilterByCurrency(
 	return balances
 }
 
+// getERC20Balances returns the balances of the registered erc20 contracts held by an address
+// at a height, for all contracts or only those of the requested currencies.  Contracts whose
+// balance can not be read are logged and left out, so they do not fail the other balances.
+func (c *Client) getERC20Balances(
+	ctx context.Context,
+	addr sdk.AccAddress,
+	height int64,
+	currencies []*types.Currency,
+) ([]*types.Amount, error) {
+	contracts := c.registry.ERC20Currencies()
+
+	if currencies != nil {
+		requested := make(map[common.Address]*types.Currency)
+
+		for _, currency := range currencies {
+			if contract, ok := c.registry.ERC20Contract(currency); ok {
+				requested[contract] = contracts[contract]
+			}
+		}
+
+		contracts = requested
+	}
+
+	balances := []*types.Amount{}
+
+	for contract, currency := range contracts {
+		value, err := c.rpc.ERC20Balance(ctx, contract, common.BytesToAddress(addr), height)
+		if err != nil {
+			log.Printf("could not get %s balance of contract %s: %s", currency.Symbol, contract, err)
+			continue
+		}
+
+		balances = append(balances, &types.Amount{
+			Value:    value.String(),
+			Currency: currency,
+		})
+	}
+
+	return balances, nil
+}
+
 // Block returns rosetta block for an index or hash
 func (c *Client) Block(
 	ctx context.Context,
 	blockIdentifier *types.PartialBlockIdentifier,
 ) (*types.BlockResponse, error) {
+	// only blocks requested by index are cached, since a hash must be verified
+	// and the latest block changes
+	cacheable := c.blockResponses != nil && blockIdentifier != nil &&
+		blockIdentifier.Index != nil && blockIdentifier.Hash == nil
+
+	if cacheable {
+		if blockResponse, ok := c.blockResponses.get(*blockIdentifier.Index); ok {
+			return blockResponse, nil
+		}
+	}
+
 	block, deliverResults, err := c.getBlockResult(ctx, blockIdentifier)
 	if err != nil {
 		return nil, err
@@ -247,16 +479,26 @@ func (c *Client) Block(
 		}
 	}
 
-	transactions := c.getTransactionsForBlock(block, deliverResults)
+	stakes := c.vestingStakes(ctx, height, block.Block.Header.Time)
+	transactions, err := c.getTransactionsForBlock(stakes, block, deliverResults)
+	if err != nil {
+		return nil, err
+	}
 
-	return &types.BlockResponse{
+	blockResponse := &types.BlockResponse{
 		Block: &types.Block{
 			BlockIdentifier:       identifier,
 			ParentBlockIdentifier: parentIdentifier,
 			Timestamp:             block.Block.Header.Time.UnixNano() / int64(1e6),
 			Transactions:          transactions,
 		},
-	}, nil
+	}
+
+	if cacheable {
+		c.blockResponses.add(height, blockResponse)
+	}
+
+	return blockResponse, nil
 }
 
 // getBlockResult returns the specified block by Index or Hash. If the
@@ -310,82 +552,281 @@ func (c *Client) getBlockResult(ctx context.Context, blockIdentifier *types.Part
 }
 
 func (c *Client) getTransactionsForBlock(
+	stakes *VestingStakes,
 	resultBlock *ctypes.ResultBlock,
 	resultBlockResults *ctypes.ResultBlockResults,
-) []*types.Transaction {
+) ([]*types.Transaction, error) {
+	height := resultBlock.Block.Header.Height
+
 	// returns transactions -- this will be number of txs + begin/end block (if there)
-	eventOpStatus := SuccessStatus
 	transactions := []*types.Transaction{}
 
-	beginBlockOps := EventsToOperations(
-		stringifyEvents(resultBlockResults.BeginBlockEvents),
-		&eventOpStatus,
-		0,
-	)
-
-	if len(beginBlockOps) > 0 {
-		transactions = append(transactions, &types.Transaction{
-			TransactionIdentifier: &types.TransactionIdentifier{
-				Hash: BeginBlockTxHash(resultBlock.BlockID.Hash),
-			},
-			Operations: beginBlockOps,
+	beginBlockHash := BeginBlockTxHash(resultBlock.BlockID.Hash)
+	beginBlockTx, err := c.blockEventsTransaction(beginBlockHash, resultBlockResults.BeginBlockEvents)
+	if err != nil {
+		beginBlockTx, err = c.parseErrorTransaction(&TxParseError{
+			Height: height, TxIndex: BlockEventsTxIndex, TxHash: beginBlockHash, Err: err,
 		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err := c.splitVestingStakingOperations(stakes, beginBlockTx); err != nil {
+		return nil, err
+	}
+	if beginBlockTx != nil {
+		transactions = append(transactions, beginBlockTx)
 	}
 
 	// transaction loop
 	for i, rawTx := range resultBlock.Block.Data.Txs {
 		hash := strings.ToUpper(hex.EncodeToString(rawTx.Hash()))
 
-		tx, err := c.encodingConfig.TxConfig.TxDecoder()(rawTx)
+		transaction, err := c.decodeTransaction(hash, rawTx, resultBlockResults.TxsResults[i])
 		if err != nil {
-			panic(fmt.Sprintf(
-				"unable to unmarshal transaction at index %d of block %d: %s",
-				i, resultBlock.Block.Header.Height, err,
-			))
+			transaction, err = c.parseErrorTransaction(&TxParseError{
+				Height: height, TxIndex: i, TxHash: hash, Err: err,
+			})
+			if err != nil {
+				return nil, err
+			}
+		} else if err := c.splitVestingStakingOperations(stakes, transaction); err != nil {
+			return nil, err
 		}
 
-		sigTx, ok := tx.(authsigning.Tx)
-		if !ok {
-			panic(fmt.Sprintf(
-				"unable to cast transaction at index %d of block %d: %s",
-				i, resultBlock.Block.Header.Height, err,
-			))
+		transactions = append(transactions, transaction)
+	}
+
+	endBlockHash := EndBlockTxHash(resultBlock.BlockID.Hash)
+	endBlockTx, err := c.blockEventsTransaction(endBlockHash, resultBlockResults.EndBlockEvents)
+	if err != nil {
+		endBlockTx, err = c.parseErrorTransaction(&TxParseError{
+			Height: height, TxIndex: BlockEventsTxIndex, TxHash: endBlockHash, Err: err,
+		})
+		if err != nil {
+			return nil, err
 		}
+	} else if err := c.splitVestingStakingOperations(stakes, endBlockTx); err != nil {
+		return nil, err
+	}
+	if endBlockTx != nil {
+		transactions = append(transactions, endBlockTx)
+	}
 
-		operations := c.getOperationsForTransaction(sigTx, resultBlockResults.TxsResults[i])
-		metadata := c.getMetadataForTransaction(resultBlockResults.TxsResults[i])
+	return transactions, nil
+}
 
-		transactions = append(transactions, &types.Transaction{
-			TransactionIdentifier: &types.TransactionIdentifier{
-				Hash: hash,
-			},
-			Operations: operations,
-			Metadata:   metadata,
+// parseErrorTransaction returns the parse error, or when lenient parsing is enabled,
+// a transaction without operations that flags the error in its metadata
+func (c *Client) parseErrorTransaction(parseErr *TxParseError) (*types.Transaction, error) {
+	if !c.lenientParsing {
+		return nil, parseErr
+	}
+
+	return &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: parseErr.TxHash,
+		},
+		Operations: []*types.Operation{},
+		Metadata: map[string]interface{}{
+			parseErrorMetadataKey: parseErr.Err.Error(),
+		},
+	}, nil
+}
+
+// decodeTransaction decodes a raw transaction and returns it with operations for its result
+func (c *Client) decodeTransaction(
+	hash string,
+	rawTx tmtypes.Tx,
+	result *abci.ResponseDeliverTx,
+) (*types.Transaction, error) {
+	tx, err := c.encodingConfig.TxConfig.TxDecoder()(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal transaction: %w", err)
+	}
+
+	sigTx, ok := tx.(authsigning.Tx)
+	if !ok {
+		return nil, errors.New("unable to cast transaction")
+	}
+
+	return c.getTransaction(hash, sigTx, result)
+}
+
+// BlockTransaction returns a single rosetta transaction included in the provided block
+func (c *Client) BlockTransaction(
+	ctx context.Context,
+	blockIdentifier *types.BlockIdentifier,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*types.Transaction, error) {
+	resultHeader, err := c.rpc.Header(ctx, &blockIdentifier.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHash := resultHeader.Header.Hash()
+	if !strings.EqualFold(blockHash.String(), blockIdentifier.Hash) {
+		return nil, fmt.Errorf(
+			"requested hash %s does not match hash %s of block %d",
+			blockIdentifier.Hash, blockHash.String(), blockIdentifier.Index,
+		)
+	}
+
+	txHash := strings.ToUpper(transactionIdentifier.Hash)
+
+	switch txHash {
+	case BeginBlockTxHash(blockHash), EndBlockTxHash(blockHash):
+		results, err := c.rpc.BlockResults(ctx, &blockIdentifier.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		events := results.BeginBlockEvents
+		stakes := c.vestingStakes(ctx, blockIdentifier.Index, resultHeader.Header.Time)
+		if txHash == EndBlockTxHash(blockHash) {
+			events = results.EndBlockEvents
+			stakes = c.transactionVestingStakes(ctx, blockIdentifier.Index, resultHeader.Header.Time, len(results.TxsResults))
+		}
+
+		transaction, err := c.blockEventsTransaction(txHash, events)
+		if err != nil {
+			transaction, err = c.parseErrorTransaction(&TxParseError{
+				Height: blockIdentifier.Index, TxIndex: BlockEventsTxIndex, TxHash: txHash, Err: err,
+			})
+			if err != nil {
+				return nil, err
+			}
+		} else if err := c.splitVestingStakingOperations(stakes, transaction); err != nil {
+			return nil, err
+		}
+		if transaction == nil {
+			return nil, fmt.Errorf("transaction %s not found in block %d", txHash, blockIdentifier.Index)
+		}
+
+		return transaction, nil
+	}
+
+	hashBytes, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resultTx, err := c.rpc.Tx(ctx, hashBytes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if resultTx.Height != blockIdentifier.Index {
+		return nil, fmt.Errorf(
+			"transaction %s is included in block %d, not block %d",
+			txHash, resultTx.Height, blockIdentifier.Index,
+		)
+	}
+
+	transaction, err := c.decodeTransaction(txHash, resultTx.Tx, &resultTx.TxResult)
+	if err != nil {
+		return c.parseErrorTransaction(&TxParseError{
+			Height: resultTx.Height, TxIndex: int(resultTx.Index), TxHash: txHash, Err: err,
 		})
 	}
 
-	endBlockOps := EventsToOperations(
-		stringifyEvents(resultBlockResults.EndBlockEvents),
-		&eventOpStatus,
-		0,
-	)
+	stakes := c.transactionVestingStakes(ctx, resultTx.Height, resultHeader.Header.Time, int(resultTx.Index))
+	if err := c.splitVestingStakingOperations(stakes, transaction); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
 
-	if len(endBlockOps) > 0 {
-		transactions = append(transactions, &types.Transaction{
-			TransactionIdentifier: &types.TransactionIdentifier{
-				Hash: EndBlockTxHash(resultBlock.BlockID.Hash),
-			},
-			Operations: endBlockOps,
+// Transaction returns a rosetta transaction by hash and the identifier of the block including it
+func (c *Client) Transaction(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*types.BlockIdentifier, *types.Transaction, error) {
+	txHash := strings.ToUpper(transactionIdentifier.Hash)
+
+	hashBytes, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultTx, err := c.rpc.Tx(ctx, hashBytes, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resultHeader, err := c.rpc.Header(ctx, &resultTx.Height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockIdentifier := &types.BlockIdentifier{
+		Index: resultTx.Height,
+		Hash:  resultHeader.Header.Hash().String(),
+	}
+
+	transaction, err := c.decodeTransaction(txHash, resultTx.Tx, &resultTx.TxResult)
+	if err != nil {
+		transaction, err = c.parseErrorTransaction(&TxParseError{
+			Height: resultTx.Height, TxIndex: int(resultTx.Index), TxHash: txHash, Err: err,
 		})
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		stakes := c.transactionVestingStakes(ctx, resultTx.Height, resultHeader.Header.Time, int(resultTx.Index))
+		if err := c.splitVestingStakingOperations(stakes, transaction); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return blockIdentifier, transaction, nil
+}
+
+// blockEventsTransaction returns a transaction for begin or end block events, or
+// nil if the events do not contain any balance changing operations
+func (c *Client) blockEventsTransaction(hash string, events []abci.Event) (*types.Transaction, error) {
+	eventOpStatus := SuccessStatus
+
+	operations, err := EventsToOperations(c.registry, stringifyEvents(events), &eventOpStatus, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(operations) == 0 {
+		return nil, nil
+	}
+
+	return &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: hash,
+		},
+		Operations: operations,
+	}, nil
+}
+
+func (c *Client) getTransaction(
+	hash string,
+	tx authsigning.Tx,
+	result *abci.ResponseDeliverTx,
+) (*types.Transaction, error) {
+	operations, err := c.getOperationsForTransaction(tx, result)
+	if err != nil {
+		return nil, err
 	}
 
-	return transactions
+	return &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: hash,
+		},
+		Operations: operations,
+		Metadata:   c.getMetadataForTransaction(result),
+	}, nil
 }
 
 func (c *Client) getOperationsForTransaction(
 	tx authsigning.Tx,
 	result *abci.ResponseDeliverTx,
-) []*types.Operation {
+) ([]*types.Operation, error) {
 	opStatus := SuccessStatus
 	feeStatus := SuccessStatus
 
@@ -404,7 +845,12 @@ func (c *Client) getOperationsForTransaction(
 		case sdkerrors.ErrUnauthorized.ABCICode(), sdkerrors.ErrInsufficientFunds.ABCICode(), sdkerrors.ErrOutOfGas.ABCICode():
 			feeStatus = FailureStatus
 
-			if containsFee(tx, result) {
+			feePaid, err := containsFee(tx, result)
+			if err != nil {
+				return nil, err
+			}
+
+			if feePaid {
 				feeStatus = SuccessStatus
 			}
 		}
@@ -415,7 +861,7 @@ func (c *Client) getOperationsForTransaction(
 	if err != nil {
 		logs = sdk.ABCIMessageLogs{}
 	}
-	return TxToOperations(tx, events, logs, &feeStatus, &opStatus)
+	return TxToOperations(c.registry, tx, events, logs, &feeStatus, &opStatus)
 }
 
 func (c *Client) getMetadataForTransaction(
@@ -427,6 +873,10 @@ func (c *Client) getMetadataForTransaction(
 		metadata["log"] = result.Log
 	}
 
+	if hash, ok := ethereumTxHash(stringifyEvents(result.Events)); ok {
+		metadata[ethereumTxHashMetadataKey] = hash
+	}
+
 	return metadata
 }
 
@@ -440,6 +890,87 @@ func stringifyEvents(events []abci.Event) sdk.StringEvents {
 	return res
 }
 
+// Mempool returns the transaction identifiers of unconfirmed transactions in the node mempool,
+// limited to the first mempoolTxLimit transactions.  A truncated mempool is logged, since the
+// mempool response can not flag it.
+func (c *Client) Mempool(ctx context.Context) ([]*types.TransactionIdentifier, error) {
+	limit := c.mempoolTxLimit
+	resultUnconfirmedTxs, err := c.rpc.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if resultUnconfirmedTxs.Total > len(resultUnconfirmedTxs.Txs) {
+		log.Printf(
+			"mempool truncated, returning the first %d of %d unconfirmed transactions",
+			len(resultUnconfirmedTxs.Txs), resultUnconfirmedTxs.Total,
+		)
+	}
+
+	transactionIdentifiers := []*types.TransactionIdentifier{}
+	for _, rawTx := range resultUnconfirmedTxs.Txs {
+		transactionIdentifiers = append(transactionIdentifiers, &types.TransactionIdentifier{
+			Hash: strings.ToUpper(hex.EncodeToString(rawTx.Hash())),
+		})
+	}
+
+	return transactionIdentifiers, nil
+}
+
+// MempoolTransaction returns a rosetta transaction for an unconfirmed transaction in the node mempool.
+// Only the first mempoolTxLimit transactions are searched, and a transaction that is not found in a
+// larger mempool returns an error that the mempool was truncated.
+func (c *Client) MempoolTransaction(
+	ctx context.Context,
+	transactionIdentifier *types.TransactionIdentifier,
+) (*types.Transaction, error) {
+	limit := c.mempoolTxLimit
+	resultUnconfirmedTxs, err := c.rpc.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash := strings.ToUpper(transactionIdentifier.Hash)
+
+	for _, rawTx := range resultUnconfirmedTxs.Txs {
+		if strings.ToUpper(hex.EncodeToString(rawTx.Hash())) != txHash {
+			continue
+		}
+
+		tx, err := c.encodingConfig.TxConfig.TxDecoder()(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal transaction %s: %w", txHash, err)
+		}
+
+		sigTx, ok := tx.(authsigning.Tx)
+		if !ok {
+			return nil, fmt.Errorf("unable to cast transaction %s", txHash)
+		}
+
+		pendingStatus := PendingStatus
+		operations, err := TxToOperations(c.registry, sigTx, sdk.StringEvents{}, sdk.ABCIMessageLogs{}, &pendingStatus, &pendingStatus)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse transaction %s: %w", txHash, err)
+		}
+
+		return &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: txHash,
+			},
+			Operations: operations,
+		}, nil
+	}
+
+	if resultUnconfirmedTxs.Total > len(resultUnconfirmedTxs.Txs) {
+		return nil, fmt.Errorf(
+			"transaction %s not found in the first %d of %d mempool transactions, the mempool was truncated",
+			txHash, len(resultUnconfirmedTxs.Txs), resultUnconfirmedTxs.Total,
+		)
+	}
+
+	return nil, fmt.Errorf("transaction %s not found in mempool", txHash)
+}
+
 // PostTx broadcasts a transaction and returns an error if it does not get into mempool
 func (c *Client) PostTx(ctx context.Context, txBytes []byte) (*types.TransactionIdentifier, error) {
 	txRes, err := c.rpc.BroadcastTxSync(ctx, tmtypes.Tx(txBytes))
@@ -470,7 +1001,7 @@ func IsRetriableError(err error) bool {
 func containsFee(
 	tx authsigning.Tx,
 	result *abci.ResponseDeliverTx,
-) bool {
+) (bool, error) {
 	// Check transaction events for fee collector, returning true if found
 	for _, event := range stringifyEvents(result.Events) {
 		if event.Type == banktypes.EventTypeCoinReceived {
@@ -480,26 +1011,26 @@ func containsFee(
 				attributes[attribute.Key] = attribute.Value
 			}
 
-			amount, err := sdk.ParseCoinsNormalized(attributes[sdk.AttributeKeyAmount])
+			amount, err := parseEventCoins(banktypes.EventTypeCoinReceived, attributes)
 			if err != nil {
-				panic(fmt.Sprintf("could not parse coins: %s", attributes[sdk.AttributeKeyAmount]))
+				return false, err
 			}
 
 			// Skip fee check for ethereum transactions as fee status is not used
 			if txWithExtensions, ok := tx.(authante.HasExtensionOptionsTx); ok {
 				if opts := txWithExtensions.GetExtensionOptions(); len(opts) > 0 {
 					if opts[0].GetTypeUrl() == "/ethermint.evm.v1.ExtensionOptionsEthereumTx" {
-						return false
+						return false, nil
 					}
 				}
 			}
 
 			// Fee was paid
 			if attributes[banktypes.AttributeKeyReceiver] == feeCollectorAddress.String() && amount.IsEqual(tx.GetFee()) {
-				return true
+				return true, nil
 			}
 		}
 	}
 
-	return false
+	return false, nil
 }
@@ -19,6 +19,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
@@ -40,7 +41,10 @@ import (
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/common"
 	app "github.com/kava-labs/kava/app"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -237,6 +241,27 @@ func TestStatus(t *testing.T) {
 	})
 }
 
+func TestSyncInfo(t *testing.T) {
+	ctx := context.Background()
+	mockRPCClient, _, client := setupClient(t)
+
+	rpcErr := errors.New("unable to contact node")
+	mockRPCClient.On("Status", ctx).Return(nil, rpcErr).Once()
+
+	syncInfo, err := client.SyncInfo(ctx)
+	assert.Nil(t, syncInfo)
+	assert.Equal(t, rpcErr, err)
+
+	resultStatus := newResultStatus(t)
+	mockRPCClient.On("Status", ctx).Return(resultStatus, nil).Once()
+
+	syncInfo, err = client.SyncInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &resultStatus.SyncInfo, syncInfo)
+
+	mockRPCClient.AssertExpectations(t)
+}
+
 func TestBalance_InvalidAddress(t *testing.T) {
 	_, _, client := setupClient(t)
 
@@ -339,11 +364,13 @@ func TestBalance_NoFilters(t *testing.T) {
 		assert.Equal(t, block, accountResponse.BlockIdentifier)
 		assert.Greater(t, len(accountResponse.Balances), 0)
 
+		registry := kava.NewCurrencyRegistry(kava.Currencies)
 		for _, amount := range accountResponse.Balances {
-			denom := kava.Denoms[amount.Currency.Symbol]
-			require.NotEmpty(t, denom)
+			denom, ok := registry.Denom(amount.Currency)
+			require.True(t, ok)
 
-			assert.Equal(t, kava.Currencies[denom], amount.Currency)
+			currency, _ := registry.Currency(denom)
+			assert.Equal(t, currency, amount.Currency)
 			assert.Equal(t, coins.AmountOf(denom).String(), amount.Value)
 		}
 	})
@@ -440,14 +467,17 @@ func TestBalance_CurrencyFilter(t *testing.T) {
 	coins := generateDefaultCoins()
 	mockBalanceService.On("GetCoinsAndSequenceForSubAccount", ctx, (*types.SubAccountIdentifier)(nil)).Return(coins, uint64(0), nil)
 
-	t.Run("all supported coins are returned by default", func(t *testing.T) {
+	t.Run("registered and held coins are returned by default", func(t *testing.T) {
 		accountResponse, err := client.Balance(ctx, acc, nil, nil)
 		require.NoError(t, err)
-		assert.Equal(t, len(accountResponse.Balances), 4)
+		assert.Equal(t, len(accountResponse.Balances), 7)
 		assert.NotNil(t, getBalance(accountResponse.Balances, "KAVA"))
 		assert.NotNil(t, getBalance(accountResponse.Balances, "HARD"))
 		assert.NotNil(t, getBalance(accountResponse.Balances, "SWP"))
 		assert.NotNil(t, getBalance(accountResponse.Balances, "USDX"))
+		assert.NotNil(t, getBalance(accountResponse.Balances, "bnb"))
+		assert.NotNil(t, getBalance(accountResponse.Balances, "busd"))
+		assert.NotNil(t, getBalance(accountResponse.Balances, "btcb"))
 	})
 
 	t.Run("filter by single currency", func(t *testing.T) {
@@ -542,6 +572,81 @@ func TestBalance_DefaultZeroCurrency(t *testing.T) {
 	assert.Equal(t, partialTestAccount.GetSequence(), accountResponse.Metadata["account_sequence"])
 }
 
+func TestBalance_ERC20(t *testing.T) {
+	ctx := context.Background()
+	mockRPCClient := &mocks.RPCClient{}
+	mockBalanceFactory := &mocks.BalanceServiceFactory{}
+
+	contract := common.HexToAddress("0x919C1c267BC06a7039e03fcc2eF738525769109c")
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+	require.NoError(t, registry.RegisterERC20(contract.Hex(), &types.Currency{Symbol: "USDt", Decimals: 6}))
+	usdt, ok := registry.ERC20Currency(contract)
+	require.True(t, ok)
+
+	client, err := kava.NewClient(mockRPCClient, mockBalanceFactory.Execute, kava.WithCurrencyRegistry(registry))
+	require.NoError(t, err)
+
+	testAccount, _ := newTestAccount(t)
+	addr := mustAccAddrFromStr(t, testAccount.Address)
+	_, resultBlock := newBlockWithResult(t)
+	resultBlockResults := &ctypes.ResultBlockResults{Height: resultBlock.Block.Height}
+	mockRPCClient.On("BlockResults", ctx, (*int64)(nil)).Return(resultBlockResults, nil)
+	mockRPCClient.On("Block", ctx, &resultBlockResults.Height).Return(resultBlock, nil)
+
+	mockBalanceService := &mocks.AccountBalanceService{}
+	mockBalanceFactory.On("Execute", ctx, addr, &resultBlock.Block.Header).Return(mockBalanceService, nil)
+	mockBalanceService.On("GetCoinsAndSequenceForSubAccount", ctx, (*types.SubAccountIdentifier)(nil)).Return(generateDefaultCoins(), uint64(0), nil)
+	mockBalanceService.On("GetCoinsAndSequenceForSubAccount", ctx, &types.SubAccountIdentifier{Address: kava.AccVesting}).Return(sdk.NewCoins(), uint64(0), nil)
+
+	holder := common.BytesToAddress(addr)
+	acc := &types.AccountIdentifier{Address: testAccount.Address}
+
+	t.Run("erc20 balances are returned by default", func(t *testing.T) {
+		mockRPCClient.On("ERC20Balance", ctx, contract, holder, resultBlock.Block.Height).Return(big.NewInt(2500000), nil).Once()
+
+		accountResponse, err := client.Balance(ctx, acc, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, &types.Amount{Value: "2500000", Currency: usdt}, getBalance(accountResponse.Balances, "USDt"))
+		assert.NotNil(t, getBalance(accountResponse.Balances, "KAVA"))
+	})
+
+	t.Run("filter by erc20 currency", func(t *testing.T) {
+		mockRPCClient.On("ERC20Balance", ctx, contract, holder, resultBlock.Block.Height).Return(big.NewInt(0), nil).Once()
+
+		filter := []*types.Currency{{Symbol: "USDt", Decimals: 6}}
+		accountResponse, err := client.Balance(ctx, acc, nil, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []*types.Amount{{Value: "0", Currency: usdt}}, accountResponse.Balances)
+	})
+
+	t.Run("filter excluding erc20 currency", func(t *testing.T) {
+		accountResponse, err := client.Balance(ctx, acc, nil, []*types.Currency{kava.Currencies["ukava"]})
+		require.NoError(t, err)
+		assert.Len(t, accountResponse.Balances, 1)
+		assert.Nil(t, getBalance(accountResponse.Balances, "USDt"))
+	})
+
+	t.Run("sub-accounts have no erc20 balances", func(t *testing.T) {
+		vestingAcc := &types.AccountIdentifier{Address: testAccount.Address, SubAccount: &types.SubAccountIdentifier{Address: kava.AccVesting}}
+
+		accountResponse, err := client.Balance(ctx, vestingAcc, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, getBalance(accountResponse.Balances, "USDt"))
+	})
+
+	t.Run("erc20 balance error leaves out the contract", func(t *testing.T) {
+		callErr := errors.New("execution reverted")
+		mockRPCClient.On("ERC20Balance", ctx, contract, holder, resultBlock.Block.Height).Return(nil, callErr).Once()
+
+		accountResponse, err := client.Balance(ctx, acc, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, getBalance(accountResponse.Balances, "USDt"))
+		assert.NotNil(t, getBalance(accountResponse.Balances, "KAVA"))
+	})
+
+	mockRPCClient.AssertExpectations(t)
+}
+
 func TestBlock_Info_NoTransactions(t *testing.T) {
 	ctx := context.Background()
 	mockRPCClient, _, client := setupClient(t)
@@ -887,9 +992,34 @@ func TestBlock_Transactions(t *testing.T) {
 	mockRPCClient.On("Block", ctx, &blockIdentifier.Index).Return(mockResultBlock, nil).Once()
 	mockRPCClient.On("BlockResults", ctx, &blockIdentifier.Index).Return(mockResultBlockResults, nil).Once()
 
-	assert.Panics(t, func() {
-		_, _ = client.Block(ctx, &types.PartialBlockIdentifier{Index: &blockIdentifier.Index})
-	})
+	blockResponse, err = client.Block(ctx, &types.PartialBlockIdentifier{Index: &blockIdentifier.Index})
+	assert.Nil(t, blockResponse)
+
+	var parseErr *kava.TxParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, blockIdentifier.Index, parseErr.Height)
+	assert.Equal(t, 0, parseErr.TxIndex)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(badTx.Hash())), parseErr.TxHash)
+
+	// lenient parsing returns the transaction without operations and flags the error
+	lenientClient, err := kava.NewClient(mockRPCClient, nil, kava.WithLenientParsing(true))
+	require.NoError(t, err)
+
+	mockRPCClient.On("Block", ctx, &blockIdentifier.Index).Return(mockResultBlock, nil).Once()
+	mockRPCClient.On("BlockResults", ctx, &blockIdentifier.Index).Return(mockResultBlockResults, nil).Once()
+
+	blockResponse, err = lenientClient.Block(ctx, &types.PartialBlockIdentifier{Index: &blockIdentifier.Index})
+	require.NoError(t, err)
+
+	var badTransaction *types.Transaction
+	for _, tx := range blockResponse.Block.Transactions {
+		if tx.TransactionIdentifier.Hash == parseErr.TxHash {
+			badTransaction = tx
+		}
+	}
+	require.NotNil(t, badTransaction)
+	assert.Empty(t, badTransaction.Operations)
+	assert.Contains(t, badTransaction.Metadata["parse_error"], "unable to unmarshal transaction")
 }
 
 func TestBlock_TxTimeoutHeight(t *testing.T) {
@@ -1056,6 +1186,57 @@ func TestAccount(t *testing.T) {
 	assert.Equal(t, expectedAccount, account)
 }
 
+func TestDelegations(t *testing.T) {
+	ctx := context.Background()
+	mockRPCClient, _, client := setupClient(t)
+	addr, err := sdk.AccAddressFromBech32("kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea")
+	require.NoError(t, err)
+
+	rpcErr := errors.New("error retrieving delegations")
+	mockRPCClient.On("Delegations", ctx, addr, int64(0)).Return(nil, rpcErr).Once()
+
+	delegations, err := client.Delegations(ctx, addr)
+	assert.Nil(t, delegations)
+	assert.EqualError(t, err, rpcErr.Error())
+
+	expectedDelegations := stakingtypes.DelegationResponses{
+		{Delegation: stakingtypes.Delegation{DelegatorAddress: addr.String()}},
+	}
+	mockRPCClient.On("Delegations", ctx, addr, int64(0)).Return(expectedDelegations, nil).Once()
+
+	delegations, err = client.Delegations(ctx, addr)
+	require.NoError(t, err)
+	assert.Equal(t, expectedDelegations, delegations)
+}
+
+func TestStakingQueries(t *testing.T) {
+	ctx := context.Background()
+	mockRPCClient, _, client := setupClient(t)
+	addr, err := sdk.AccAddressFromBech32("kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea")
+	require.NoError(t, err)
+
+	expectedUnbondingDelegations := stakingtypes.UnbondingDelegations{{DelegatorAddress: addr.String()}}
+	mockRPCClient.On("UnbondingDelegations", ctx, addr, int64(0)).Return(expectedUnbondingDelegations, nil).Once()
+
+	unbondingDelegations, err := client.UnbondingDelegations(ctx, addr)
+	require.NoError(t, err)
+	assert.Equal(t, expectedUnbondingDelegations, unbondingDelegations)
+
+	expectedValidators := stakingtypes.Validators{{OperatorAddress: "kavavaloper1ppj7c8tqt2e3rzqtmztsmd6ea6u3nz6qggcp5e"}}
+	mockRPCClient.On("StakingValidators", ctx, "BOND_STATUS_BONDED", int64(0)).Return(expectedValidators, nil).Once()
+
+	validators, err := client.Validators(ctx, "BOND_STATUS_BONDED")
+	require.NoError(t, err)
+	assert.Equal(t, expectedValidators, validators)
+
+	rpcErr := errors.New("error retrieving rewards")
+	mockRPCClient.On("DelegationRewards", ctx, addr, int64(0)).Return(nil, rpcErr).Once()
+
+	rewards, err := client.DelegationRewards(ctx, addr)
+	assert.Nil(t, rewards)
+	assert.EqualError(t, err, rpcErr.Error())
+}
+
 // TestBlock_HashPriority asserts that a block is looked up by hash if both
 // the index and hash are provided.  In addition, we assert that an error
 // is thrown if the returned index does not match the queried index.
@@ -1126,6 +1307,450 @@ func TestBlock_HashPriority(t *testing.T) {
 	mockRPCClient.AssertExpectations(t)
 }
 
+func TestBlockTransaction(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+
+	txBuilder := encodingConfig.TxConfig.NewTxBuilder()
+	err := txBuilder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: sdk.AccAddress("test from address").String(),
+		ToAddress:   sdk.AccAddress("test to address").String(),
+		Amount:      sdk.Coins{sdk.NewCoin("ukava", sdkmath.NewInt(100))},
+	})
+	require.NoError(t, err)
+	txBuilder.SetGasLimit(100000)
+	txBuilder.SetFeeAmount(sdk.Coins{sdk.Coin{Denom: "ukava", Amount: sdkmath.NewInt(5000)}})
+	txBuilder.SetMemo("mock transaction")
+
+	var rawMockTx tmtypes.Tx
+	rawMockTx, err = encodingConfig.TxConfig.TxEncoder()(txBuilder.GetTx())
+	require.NoError(t, err)
+	txHash := strings.ToUpper(hex.EncodeToString(rawMockTx.Hash()))
+
+	header := tmtypes.Header{
+		Height:         100,
+		Time:           time.Now(),
+		ValidatorsHash: []byte("validators hash"),
+	}
+	blockIdentifier := &types.BlockIdentifier{
+		Index: header.Height,
+		Hash:  header.Hash().String(),
+	}
+
+	transferEvent := abci.Event{
+		Type: banktypes.EventTypeTransfer,
+		Attributes: []abci.EventAttribute{
+			{Key: banktypes.AttributeKeyRecipient, Value: sdk.AccAddress("test to address").String()},
+			{Key: banktypes.AttributeKeySender, Value: sdk.AccAddress("test from address").String()},
+			{Key: sdk.AttributeKeyAmount, Value: "100ukava"},
+		},
+	}
+	mockResultBlockResults := &ctypes.ResultBlockResults{
+		Height:           header.Height,
+		BeginBlockEvents: []abci.Event{transferEvent},
+		EndBlockEvents:   []abci.Event{},
+	}
+
+	t.Run("rpc error when getting block header", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		rpcErr := errors.New("header error")
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(nil, rpcErr).Once()
+
+		transaction, err := client.BlockTransaction(ctx, blockIdentifier, &types.TransactionIdentifier{Hash: txHash})
+		assert.Nil(t, transaction)
+		assert.Equal(t, rpcErr, err)
+	})
+
+	t.Run("block hash does not match block index", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+
+		invalidBlockIdentifier := &types.BlockIdentifier{
+			Index: blockIdentifier.Index,
+			Hash:  latestBlockHashStr,
+		}
+		transaction, err := client.BlockTransaction(ctx, invalidBlockIdentifier, &types.TransactionIdentifier{Hash: txHash})
+		assert.Nil(t, transaction)
+		assert.EqualError(t, err, fmt.Sprintf(
+			"requested hash %s does not match hash %s of block %d",
+			latestBlockHashStr, blockIdentifier.Hash, blockIdentifier.Index,
+		))
+	})
+
+	t.Run("transaction is included in another block", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+		mockRPCClient.On("Tx", ctx, []byte(rawMockTx.Hash()), false).Return(&ctypes.ResultTx{
+			Height: blockIdentifier.Index + 1,
+			Tx:     rawMockTx,
+		}, nil).Once()
+
+		transaction, err := client.BlockTransaction(ctx, blockIdentifier, &types.TransactionIdentifier{Hash: txHash})
+		assert.Nil(t, transaction)
+		assert.EqualError(t, err, fmt.Sprintf(
+			"transaction %s is included in block %d, not block %d",
+			txHash, blockIdentifier.Index+1, blockIdentifier.Index,
+		))
+	})
+
+	t.Run("transaction is returned by hash", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		deliverTx := abci.ResponseDeliverTx{
+			Code: 1,
+			Log:  "some error message",
+		}
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+		mockRPCClient.On("Tx", ctx, []byte(rawMockTx.Hash()), false).Return(&ctypes.ResultTx{
+			Height:   blockIdentifier.Index,
+			Tx:       rawMockTx,
+			TxResult: deliverTx,
+		}, nil).Once()
+
+		transaction, err := client.BlockTransaction(
+			ctx,
+			blockIdentifier,
+			&types.TransactionIdentifier{Hash: strings.ToLower(txHash)},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, txHash, transaction.TransactionIdentifier.Hash)
+		assert.Equal(t, deliverTx.Log, transaction.Metadata["log"])
+
+		// 2 fee ops and 2 transfer ops, the transfer failed
+		require.Equal(t, 4, len(transaction.Operations))
+		for _, operation := range transaction.Operations {
+			if operation.Type == kava.FeeOpType {
+				assert.Equal(t, kava.SuccessStatus, *operation.Status)
+			} else {
+				assert.Equal(t, kava.FailureStatus, *operation.Status)
+			}
+		}
+	})
+
+	t.Run("begin block transaction is returned", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+		mockRPCClient.On("BlockResults", ctx, &blockIdentifier.Index).Return(mockResultBlockResults, nil).Once()
+
+		beginBlockHash := kava.BeginBlockTxHash(header.Hash())
+		transaction, err := client.BlockTransaction(ctx, blockIdentifier, &types.TransactionIdentifier{Hash: beginBlockHash})
+		require.NoError(t, err)
+		assert.Equal(t, beginBlockHash, transaction.TransactionIdentifier.Hash)
+		require.Equal(t, 2, len(transaction.Operations))
+		assert.Equal(t, kava.TransferOpType, transaction.Operations[0].Type)
+		assert.Equal(t, "-100", transaction.Operations[0].Amount.Value)
+		assert.Equal(t, "100", transaction.Operations[1].Amount.Value)
+	})
+
+	t.Run("staking operations of a vesting delegator are split after earlier transactions", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		delegator := sdk.AccAddress("test from address")
+		stakingEvent := func(eventType string) abci.Event {
+			return abci.Event{
+				Type: eventType,
+				Attributes: []abci.EventAttribute{
+					{Key: stakingtypes.AttributeKeyValidator, Value: "kavavaloper1xy7hrjy9r0algz9w3gzm8u6mrpq97kwta747gj"},
+					{Key: stakingtypes.AttributeKeyDelegator, Value: delegator.String()},
+					{Key: sdk.AttributeKeyAmount, Value: "500000ukava"},
+				},
+			}
+		}
+		stakingResults := &ctypes.ResultBlockResults{
+			Height: header.Height,
+			TxsResults: []*abci.ResponseDeliverTx{
+				{Events: []abci.Event{stakingEvent(stakingtypes.EventTypeUnbond)}},
+			},
+			EndBlockEvents: []abci.Event{stakingEvent(stakingtypes.EventTypeCompleteUnbonding)},
+		}
+
+		vestingAccount := &vestingtypes.ContinuousVestingAccount{
+			BaseVestingAccount: &vestingtypes.BaseVestingAccount{
+				BaseAccount:      &authtypes.BaseAccount{Address: delegator.String()},
+				OriginalVesting:  sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(1000000))),
+				DelegatedFree:    sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(250000))),
+				DelegatedVesting: sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(1000000))),
+				EndTime:          header.Time.Add(time.Hour).Unix(),
+			},
+			StartTime: header.Time.Unix(),
+		}
+		previousHeight := header.Height - 1
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+		mockRPCClient.On("BlockResults", ctx, &blockIdentifier.Index).Return(stakingResults, nil).Twice()
+		mockRPCClient.On("Account", ctx, delegator, previousHeight).Return(vestingAccount, nil).Once()
+		mockRPCClient.On("Delegations", ctx, delegator, previousHeight).Return(stakingtypes.DelegationResponses{
+			{Balance: sdk.NewCoin("ukava", sdkmath.NewInt(1250000))},
+		}, nil).Once()
+		mockRPCClient.On("UnbondingDelegations", ctx, delegator, previousHeight).Return(stakingtypes.UnbondingDelegations{}, nil).Once()
+
+		endBlockHash := kava.EndBlockTxHash(header.Hash())
+		transaction, err := client.BlockTransaction(ctx, blockIdentifier, &types.TransactionIdentifier{Hash: endBlockHash})
+		require.NoError(t, err)
+		mockRPCClient.AssertExpectations(t)
+
+		// half of the unbonding coins are free, since the unbonded coins are delegated free first
+		require.Equal(t, 2, len(transaction.Operations))
+		assert.Equal(t, kava.CompleteUnbondingOpType, transaction.Operations[0].Type)
+		assert.Equal(t, kava.AccLiquidUnbonding, transaction.Operations[0].Account.SubAccount.Address)
+		assert.Equal(t, "-250000", transaction.Operations[0].Amount.Value)
+		assert.Equal(t, kava.AccVestingUnbonding, transaction.Operations[1].Account.SubAccount.Address)
+		assert.Equal(t, "-250000", transaction.Operations[1].Amount.Value)
+		assert.Equal(t, int64(1), transaction.Operations[1].OperationIdentifier.Index)
+	})
+
+	t.Run("staking operations are not split when the delegator can not be loaded", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		delegator := sdk.AccAddress("test from address")
+		stakingResults := &ctypes.ResultBlockResults{
+			Height: header.Height,
+			EndBlockEvents: []abci.Event{{
+				Type: stakingtypes.EventTypeCompleteUnbonding,
+				Attributes: []abci.EventAttribute{
+					{Key: stakingtypes.AttributeKeyValidator, Value: "kavavaloper1xy7hrjy9r0algz9w3gzm8u6mrpq97kwta747gj"},
+					{Key: stakingtypes.AttributeKeyDelegator, Value: delegator.String()},
+					{Key: sdk.AttributeKeyAmount, Value: "500000ukava"},
+				},
+			}},
+		}
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+		mockRPCClient.On("BlockResults", ctx, &blockIdentifier.Index).Return(stakingResults, nil).Once()
+		mockRPCClient.On("Account", ctx, delegator, header.Height-1).Return(nil, errors.New("height is not available")).Once()
+
+		endBlockHash := kava.EndBlockTxHash(header.Hash())
+		transaction, err := client.BlockTransaction(ctx, blockIdentifier, &types.TransactionIdentifier{Hash: endBlockHash})
+		require.NoError(t, err)
+		mockRPCClient.AssertExpectations(t)
+
+		require.Equal(t, 1, len(transaction.Operations))
+		assert.Equal(t, kava.CompleteUnbondingOpType, transaction.Operations[0].Type)
+		assert.Equal(t, kava.AccLiquidUnbonding, transaction.Operations[0].Account.SubAccount.Address)
+		assert.Equal(t, "-500000", transaction.Operations[0].Amount.Value)
+	})
+
+	t.Run("end block transaction without operations is not found", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("Header", ctx, &blockIdentifier.Index).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+		mockRPCClient.On("BlockResults", ctx, &blockIdentifier.Index).Return(mockResultBlockResults, nil).Once()
+
+		endBlockHash := kava.EndBlockTxHash(header.Hash())
+		transaction, err := client.BlockTransaction(ctx, blockIdentifier, &types.TransactionIdentifier{Hash: endBlockHash})
+		assert.Nil(t, transaction)
+		assert.EqualError(t, err, fmt.Sprintf("transaction %s not found in block %d", endBlockHash, blockIdentifier.Index))
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+
+	txBuilder := encodingConfig.TxConfig.NewTxBuilder()
+	err := txBuilder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: sdk.AccAddress("test from address").String(),
+		ToAddress:   sdk.AccAddress("test to address").String(),
+		Amount:      sdk.Coins{sdk.NewCoin("ukava", sdkmath.NewInt(100))},
+	})
+	require.NoError(t, err)
+	txBuilder.SetGasLimit(100000)
+	txBuilder.SetFeeAmount(sdk.Coins{sdk.Coin{Denom: "ukava", Amount: sdkmath.NewInt(5000)}})
+
+	var rawMockTx tmtypes.Tx
+	rawMockTx, err = encodingConfig.TxConfig.TxEncoder()(txBuilder.GetTx())
+	require.NoError(t, err)
+	txHash := strings.ToUpper(hex.EncodeToString(rawMockTx.Hash()))
+
+	header := tmtypes.Header{
+		Height:         100,
+		Time:           time.Now(),
+		ValidatorsHash: []byte("validators hash"),
+	}
+
+	t.Run("rpc error when getting transaction", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		rpcErr := errors.New("tx not found")
+		mockRPCClient.On("Tx", ctx, []byte(rawMockTx.Hash()), false).Return(nil, rpcErr).Once()
+
+		blockIdentifier, transaction, err := client.Transaction(ctx, &types.TransactionIdentifier{Hash: txHash})
+		assert.Nil(t, blockIdentifier)
+		assert.Nil(t, transaction)
+		assert.Equal(t, rpcErr, err)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		ctx := context.Background()
+		_, _, client := setupClient(t)
+
+		_, _, err := client.Transaction(ctx, &types.TransactionIdentifier{Hash: "not hex"})
+		assert.Error(t, err)
+	})
+
+	t.Run("transaction is returned with its block", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("Tx", ctx, []byte(rawMockTx.Hash()), false).Return(&ctypes.ResultTx{
+			Height: header.Height,
+			Tx:     rawMockTx,
+		}, nil).Once()
+		mockRPCClient.On("Header", ctx, &header.Height).Return(&ctypes.ResultHeader{Header: &header}, nil).Once()
+
+		blockIdentifier, transaction, err := client.Transaction(
+			ctx,
+			&types.TransactionIdentifier{Hash: strings.ToLower(txHash)},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, &types.BlockIdentifier{Index: header.Height, Hash: header.Hash().String()}, blockIdentifier)
+		assert.Equal(t, txHash, transaction.TransactionIdentifier.Hash)
+		// 2 fee ops, the transfer ops of successful transactions are parsed from events
+		assert.Equal(t, 2, len(transaction.Operations))
+	})
+}
+
+func TestMempool(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+
+	txBuilder := encodingConfig.TxConfig.NewTxBuilder()
+	err := txBuilder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: sdk.AccAddress("test from address").String(),
+		ToAddress:   sdk.AccAddress("test to address").String(),
+		Amount:      sdk.Coins{sdk.NewCoin("ukava", sdkmath.NewInt(100))},
+	})
+	require.NoError(t, err)
+	txBuilder.SetGasLimit(100000)
+	txBuilder.SetFeeAmount(sdk.Coins{sdk.Coin{Denom: "ukava", Amount: sdkmath.NewInt(5000)}})
+
+	var rawMockTx tmtypes.Tx
+	rawMockTx, err = encodingConfig.TxConfig.TxEncoder()(txBuilder.GetTx())
+	require.NoError(t, err)
+	txHash := strings.ToUpper(hex.EncodeToString(rawMockTx.Hash()))
+
+	limit := 100
+	mockUnconfirmedTxs := &ctypes.ResultUnconfirmedTxs{
+		Count: 2,
+		Total: 2,
+		Txs:   []tmtypes.Tx{tmtypes.Tx("invalid tx"), rawMockTx},
+	}
+
+	t.Run("rpc error when getting unconfirmed txs", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		rpcErr := errors.New("unconfirmed txs error")
+		mockRPCClient.On("UnconfirmedTxs", ctx, &limit).Return(nil, rpcErr).Twice()
+
+		transactionIdentifiers, err := client.Mempool(ctx)
+		assert.Nil(t, transactionIdentifiers)
+		assert.Equal(t, rpcErr, err)
+
+		transaction, err := client.MempoolTransaction(ctx, &types.TransactionIdentifier{Hash: txHash})
+		assert.Nil(t, transaction)
+		assert.Equal(t, rpcErr, err)
+	})
+
+	t.Run("returns unconfirmed tx hashes", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("UnconfirmedTxs", ctx, &limit).Return(mockUnconfirmedTxs, nil).Once()
+
+		transactionIdentifiers, err := client.Mempool(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []*types.TransactionIdentifier{
+			{Hash: strings.ToUpper(hex.EncodeToString(tmtypes.Tx("invalid tx").Hash()))},
+			{Hash: txHash},
+		}, transactionIdentifiers)
+	})
+
+	t.Run("returns pending operations for an unconfirmed tx", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("UnconfirmedTxs", ctx, &limit).Return(mockUnconfirmedTxs, nil).Once()
+
+		transaction, err := client.MempoolTransaction(ctx, &types.TransactionIdentifier{Hash: strings.ToLower(txHash)})
+		require.NoError(t, err)
+		assert.Equal(t, txHash, transaction.TransactionIdentifier.Hash)
+
+		// 2 fee ops and 2 transfer ops
+		require.Equal(t, 4, len(transaction.Operations))
+		for _, operation := range transaction.Operations {
+			assert.Equal(t, kava.PendingStatus, *operation.Status)
+		}
+	})
+
+	t.Run("unconfirmed tx is not in mempool", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("UnconfirmedTxs", ctx, &limit).Return(mockUnconfirmedTxs, nil).Once()
+
+		missingHash := "6E4B4A1B4D5E0BB9BB4E5DCA0D3A46A20A5BE3EB0E1A18F4F3B9F4C3A0E3F3A6"
+		transaction, err := client.MempoolTransaction(ctx, &types.TransactionIdentifier{Hash: missingHash})
+		assert.Nil(t, transaction)
+		assert.EqualError(t, err, fmt.Sprintf("transaction %s not found in mempool", missingHash))
+	})
+
+	t.Run("unconfirmed tx is not in truncated mempool", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		truncatedUnconfirmedTxs := &ctypes.ResultUnconfirmedTxs{
+			Count: 2,
+			Total: 250,
+			Txs:   mockUnconfirmedTxs.Txs,
+		}
+		mockRPCClient.On("UnconfirmedTxs", ctx, &limit).Return(truncatedUnconfirmedTxs, nil).Once()
+
+		missingHash := "6E4B4A1B4D5E0BB9BB4E5DCA0D3A46A20A5BE3EB0E1A18F4F3B9F4C3A0E3F3A6"
+		transaction, err := client.MempoolTransaction(ctx, &types.TransactionIdentifier{Hash: missingHash})
+		assert.Nil(t, transaction)
+		assert.EqualError(t, err, fmt.Sprintf("transaction %s not found in the first 2 of 250 mempool transactions, the mempool was truncated", missingHash))
+	})
+
+	t.Run("unconfirmed tx can not be decoded", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		mockRPCClient.On("UnconfirmedTxs", ctx, &limit).Return(mockUnconfirmedTxs, nil).Once()
+
+		invalidHash := strings.ToUpper(hex.EncodeToString(tmtypes.Tx("invalid tx").Hash()))
+		transaction, err := client.MempoolTransaction(ctx, &types.TransactionIdentifier{Hash: invalidHash})
+		assert.Nil(t, transaction)
+		assert.ErrorContains(t, err, fmt.Sprintf("unable to unmarshal transaction %s", invalidHash))
+	})
+
+	t.Run("requests the configured mempool tx limit", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient := &mocks.RPCClient{}
+		client, err := kava.NewClient(mockRPCClient, (&mocks.BalanceServiceFactory{}).Execute, kava.WithMempoolTxLimit(25))
+		require.NoError(t, err)
+
+		configuredLimit := 25
+		mockRPCClient.On("UnconfirmedTxs", ctx, &configuredLimit).Return(mockUnconfirmedTxs, nil).Once()
+
+		transactionIdentifiers, err := client.Mempool(ctx)
+		require.NoError(t, err)
+		assert.Len(t, transactionIdentifiers, 2)
+		mockRPCClient.AssertExpectations(t)
+	})
+}
+
 func TestEstimateGas(t *testing.T) {
 	ctx := context.Background()
 	mockRPCClient, _, client := setupClient(t)
@@ -1178,6 +1803,98 @@ func TestEstimateGas(t *testing.T) {
 	assert.Equal(t, uint64(220000), gas)
 }
 
+func TestSimulateTransaction(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	from := sdk.AccAddress("test from address")
+	to := sdk.AccAddress("test to address")
+
+	txBuilder := encodingConfig.TxConfig.NewTxBuilder()
+	err := txBuilder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: from.String(),
+		ToAddress:   to.String(),
+		Amount:      sdk.Coins{sdk.NewCoin("ukava", sdkmath.NewInt(100))},
+	})
+	require.NoError(t, err)
+	txBuilder.SetGasLimit(100000)
+	txBuilder.SetFeeAmount(sdk.Coins{sdk.NewCoin("ukava", sdkmath.NewInt(5000))})
+	tx := txBuilder.GetTx()
+
+	t.Run("rpc error", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		rpcErr := errors.New("connection refused")
+		mockRPCClient.On("SimulateTx", ctx, tx).Return(nil, rpcErr).Once()
+
+		simulation, err := client.SimulateTransaction(ctx, tx)
+		assert.Nil(t, simulation)
+		assert.Equal(t, rpcErr, err)
+	})
+
+	t.Run("successful simulation", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		transferEvent := abci.Event{
+			Type: banktypes.EventTypeTransfer,
+			Attributes: []abci.EventAttribute{
+				{Key: banktypes.AttributeKeyRecipient, Value: to.String()},
+				{Key: banktypes.AttributeKeySender, Value: from.String()},
+				{Key: sdk.AttributeKeyAmount, Value: "100ukava"},
+			},
+		}
+		logs := sdk.ABCIMessageLogs{sdk.NewABCIMessageLog(0, "", sdk.Events{sdk.Event(transferEvent)})}
+
+		mockRPCClient.On("SimulateTx", ctx, tx).Return(&sdk.SimulationResponse{
+			GasInfo: sdk.GasInfo{GasWanted: 100000, GasUsed: 81234},
+			Result:  &sdk.Result{Log: logs.String(), Events: []abci.Event{transferEvent}},
+		}, nil).Once()
+
+		simulation, err := client.SimulateTransaction(ctx, tx)
+		require.NoError(t, err)
+		assert.Nil(t, simulation.Err)
+		assert.Equal(t, sdk.GasInfo{GasWanted: 100000, GasUsed: 81234}, simulation.GasInfo)
+		assert.Equal(t, logs.String(), simulation.Log)
+		assert.Equal(t, stringifyTestEvents([]abci.Event{transferEvent}), simulation.Events)
+
+		// 2 fee ops and 2 transfer ops
+		require.Equal(t, 4, len(simulation.Operations))
+		for _, operation := range simulation.Operations {
+			assert.Equal(t, kava.SuccessStatus, *operation.Status)
+		}
+		assert.Equal(t, kava.TransferOpType, simulation.Operations[2].Type)
+		assert.Equal(t, "-100", simulation.Operations[2].Amount.Value)
+	})
+
+	t.Run("failed simulation", func(t *testing.T) {
+		ctx := context.Background()
+		mockRPCClient, _, client := setupClient(t)
+
+		simErr := &kava.SimulationError{Codespace: "sdk", Code: 5, Log: "insufficient funds"}
+		mockRPCClient.On("SimulateTx", ctx, tx).Return(nil, simErr).Once()
+
+		simulation, err := client.SimulateTransaction(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, simErr, simulation.Err)
+		assert.Equal(t, sdk.GasInfo{}, simulation.GasInfo)
+
+		// the fee is not paid and the transfer fails
+		require.Equal(t, 4, len(simulation.Operations))
+		for _, operation := range simulation.Operations {
+			assert.Equal(t, kava.FailureStatus, *operation.Status)
+		}
+	})
+}
+
+func stringifyTestEvents(events []abci.Event) sdk.StringEvents {
+	stringEvents := sdk.StringEvents{}
+	for _, event := range events {
+		stringEvents = append(stringEvents, sdk.StringifyEvent(event))
+	}
+
+	return stringEvents
+}
+
 func TestPostTx(t *testing.T) {
 	ctx := context.Background()
 	mockRPCClient, _, client := setupClient(t)
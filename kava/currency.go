@@ -0,0 +1,369 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// ibcTraceMetadataKey is the currency metadata key for the full denom path of an ibc voucher
+	ibcTraceMetadataKey = "ibc_trace"
+	// baseDenomMetadataKey is the currency metadata key for the chain denom of a currency
+	baseDenomMetadataKey = "base_denom"
+	// contractAddressMetadataKey is the currency metadata key for the contract address of an erc20 token
+	contractAddressMetadataKey = "contract_address"
+)
+
+// CurrencyRegistry resolves kava denoms to rosetta currencies and back.
+//
+// Denoms without a registered currency resolve to a currency using the denom
+// as the symbol with zero decimals, so every coin can be tracked.
+//
+// Erc20 contracts are registered separately, sharing the symbols of denoms, and
+// only registered contracts resolve to a currency.
+type CurrencyRegistry struct {
+	mu         sync.RWMutex
+	currencies map[string]*types.Currency
+	denoms     map[string]string
+	contracts  map[common.Address]*types.Currency
+	// contractSymbols maps the symbol of each registered contract to its address
+	contractSymbols map[string]common.Address
+}
+
+// NewCurrencyRegistry returns a registry containing the provided denom to currency mapping
+func NewCurrencyRegistry(currencies map[string]*types.Currency) *CurrencyRegistry {
+	r := &CurrencyRegistry{
+		currencies:      make(map[string]*types.Currency),
+		denoms:          make(map[string]string),
+		contracts:       make(map[common.Address]*types.Currency),
+		contractSymbols: make(map[string]common.Address),
+	}
+
+	for denom, currency := range currencies {
+		if err := r.Register(denom, currency); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
+
+// Register adds or replaces the currency for a denom.  Symbols must be unique across denoms.
+func (r *CurrencyRegistry) Register(denom string, currency *types.Currency) error {
+	if err := sdk.ValidateDenom(denom); err != nil {
+		return err
+	}
+
+	if currency == nil || currency.Symbol == "" || currency.Decimals < 0 {
+		return fmt.Errorf("invalid currency for denom %s", denom)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.denoms[currency.Symbol]; ok && existing != denom {
+		return fmt.Errorf("symbol %s for denom %s is already used by denom %s", currency.Symbol, denom, existing)
+	}
+
+	// an unregistered denom may not be used as the symbol of another denom
+	if _, ok := r.currencies[currency.Symbol]; ok && currency.Symbol != denom {
+		return fmt.Errorf("symbol %s for denom %s is a registered denom", currency.Symbol, denom)
+	}
+
+	if contract, ok := r.contractSymbols[currency.Symbol]; ok {
+		return fmt.Errorf("symbol %s for denom %s is already used by contract %s", currency.Symbol, denom, contract)
+	}
+
+	if previous, ok := r.currencies[denom]; ok {
+		delete(r.denoms, previous.Symbol)
+	}
+
+	r.currencies[denom] = currency
+	r.denoms[currency.Symbol] = denom
+
+	return nil
+}
+
+// Currency returns the currency for a denom, and false if the denom is not valid
+func (r *CurrencyRegistry) Currency(denom string) (*types.Currency, bool) {
+	r.mu.RLock()
+	currency, ok := r.currencies[denom]
+	r.mu.RUnlock()
+
+	if ok {
+		return currency, true
+	}
+
+	if sdk.ValidateDenom(denom) != nil {
+		return nil, false
+	}
+
+	return &types.Currency{Symbol: denom, Decimals: 0}, true
+}
+
+// Denom returns the denom for a currency, and false if the currency is not supported
+func (r *CurrencyRegistry) Denom(currency *types.Currency) (string, bool) {
+	if currency == nil {
+		return "", false
+	}
+
+	r.mu.RLock()
+	denom, ok := r.denoms[currency.Symbol]
+	_, isContract := r.contractSymbols[currency.Symbol]
+	r.mu.RUnlock()
+
+	if isContract {
+		return "", false
+	}
+
+	if !ok {
+		denom = currency.Symbol
+	}
+
+	expected, ok := r.Currency(denom)
+	if !ok || expected.Symbol != currency.Symbol || expected.Decimals != currency.Decimals {
+		return "", false
+	}
+
+	return denom, true
+}
+
+// Currencies returns a copy of all registered currencies by denom
+func (r *CurrencyRegistry) Currencies() map[string]*types.Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currencies := make(map[string]*types.Currency, len(r.currencies))
+	for denom, currency := range r.currencies {
+		currencies[denom] = currency
+	}
+
+	return currencies
+}
+
+// RegisterERC20 adds or replaces the currency of an erc20 contract.  The contract address is added to the
+// currency metadata and symbols must be unique across denoms and contracts.
+func (r *CurrencyRegistry) RegisterERC20(contract string, currency *types.Currency) error {
+	if !common.IsHexAddress(contract) {
+		return fmt.Errorf("invalid contract address %s", contract)
+	}
+
+	if currency == nil || currency.Symbol == "" || currency.Decimals < 0 {
+		return fmt.Errorf("invalid currency for contract %s", contract)
+	}
+
+	address := common.HexToAddress(contract)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.contractSymbols[currency.Symbol]; ok && existing != address {
+		return fmt.Errorf("symbol %s for contract %s is already used by contract %s", currency.Symbol, address, existing)
+	}
+
+	if denom, ok := r.denoms[currency.Symbol]; ok {
+		return fmt.Errorf("symbol %s for contract %s is already used by denom %s", currency.Symbol, address, denom)
+	}
+
+	if _, ok := r.currencies[currency.Symbol]; ok {
+		return fmt.Errorf("symbol %s for contract %s is a registered denom", currency.Symbol, address)
+	}
+
+	if previous, ok := r.contracts[address]; ok {
+		delete(r.contractSymbols, previous.Symbol)
+	}
+
+	metadata := make(map[string]interface{}, len(currency.Metadata)+1)
+	for key, value := range currency.Metadata {
+		metadata[key] = value
+	}
+	metadata[contractAddressMetadataKey] = address.Hex()
+
+	r.contracts[address] = &types.Currency{
+		Symbol:   currency.Symbol,
+		Decimals: currency.Decimals,
+		Metadata: metadata,
+	}
+	r.contractSymbols[currency.Symbol] = address
+
+	return nil
+}
+
+// ERC20Currency returns the currency of an erc20 contract, and false if the contract is not registered
+func (r *CurrencyRegistry) ERC20Currency(contract common.Address) (*types.Currency, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currency, ok := r.contracts[contract]
+	return currency, ok
+}
+
+// ERC20Currencies returns a copy of all registered erc20 currencies by contract
+func (r *CurrencyRegistry) ERC20Currencies() map[common.Address]*types.Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currencies := make(map[common.Address]*types.Currency, len(r.contracts))
+	for contract, currency := range r.contracts {
+		currencies[contract] = currency
+	}
+
+	return currencies
+}
+
+// ERC20Contract returns the contract of a registered erc20 currency, and false if the currency
+// is not the currency of a registered contract
+func (r *CurrencyRegistry) ERC20Contract(currency *types.Currency) (common.Address, bool) {
+	if currency == nil {
+		return common.Address{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contract, ok := r.contractSymbols[currency.Symbol]
+	if !ok || r.contracts[contract].Decimals != currency.Decimals {
+		return common.Address{}, false
+	}
+
+	return contract, true
+}
+
+// RegisterDenomsMetadata registers a currency for each bank denom metadata and ibc denom trace
+// whose denom is not registered yet.  Metadata without a usable symbol, or with a symbol that is
+// already in use, is skipped and resolves to the default currency for the denom.
+func (r *CurrencyRegistry) RegisterDenomsMetadata(metadatas []banktypes.Metadata, traces ibctransfertypes.Traces) {
+	ibcTraces := make(map[string]string)
+	for _, trace := range traces {
+		ibcTraces[trace.IBCDenom()] = trace.GetFullDenomPath()
+	}
+
+	for _, metadata := range metadatas {
+		if r.isRegistered(metadata.Base) {
+			continue
+		}
+
+		currency, ok := metadataToCurrency(metadata)
+		if !ok {
+			continue
+		}
+
+		if trace, ok := ibcTraces[metadata.Base]; ok {
+			currency.Metadata[ibcTraceMetadataKey] = trace
+		}
+
+		// conflicting symbols are skipped rather than replacing another denom
+		_ = r.Register(metadata.Base, currency)
+	}
+
+	for denom, trace := range ibcTraces {
+		if r.isRegistered(denom) {
+			continue
+		}
+
+		_ = r.Register(denom, &types.Currency{
+			Symbol:   denom,
+			Decimals: 0,
+			Metadata: map[string]interface{}{
+				ibcTraceMetadataKey: trace,
+			},
+		})
+	}
+}
+
+func (r *CurrencyRegistry) isRegistered(denom string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.currencies[denom]
+	return ok
+}
+
+// metadataToCurrency returns a currency using the decimals of the display denom unit
+func metadataToCurrency(metadata banktypes.Metadata) (*types.Currency, bool) {
+	symbol := metadata.Symbol
+	if symbol == "" {
+		symbol = strings.ToUpper(metadata.Display)
+	}
+
+	if symbol == "" {
+		return nil, false
+	}
+
+	for _, unit := range metadata.DenomUnits {
+		if unit.Denom != metadata.Display {
+			continue
+		}
+
+		return &types.Currency{
+			Symbol:   symbol,
+			Decimals: int32(unit.Exponent),
+			Metadata: map[string]interface{}{
+				baseDenomMetadataKey: metadata.Base,
+			},
+		}, true
+	}
+
+	return nil, false
+}
+
+// LoadDenomsMetadata registers currencies for the denom metadata and ibc denom traces of the chain
+func LoadDenomsMetadata(ctx context.Context, rpc RPCClient, registry *CurrencyRegistry) error {
+	metadatas, err := rpc.DenomsMetadata(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("%w: could not fetch denoms metadata", err)
+	}
+
+	traces, err := rpc.DenomTraces(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("%w: could not fetch ibc denom traces", err)
+	}
+
+	registry.RegisterDenomsMetadata(metadatas, traces)
+
+	return nil
+}
+
+// RegisterCurrencies registers each currency by denom, replacing any existing currency
+func RegisterCurrencies(registry *CurrencyRegistry, currencies map[string]*types.Currency) error {
+	for denom, currency := range currencies {
+		if err := registry.Register(denom, currency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterERC20Contracts registers the currency of each erc20 contract, replacing any existing currency
+func RegisterERC20Contracts(registry *CurrencyRegistry, contracts map[string]*types.Currency) error {
+	for contract, currency := range contracts {
+		if err := registry.RegisterERC20(contract, currency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+	"github.com/kava-labs/rosetta-kava/kava/mocks"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyRegistry_Defaults(t *testing.T) {
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	for denom, expected := range kava.Currencies {
+		currency, ok := registry.Currency(denom)
+		require.True(t, ok)
+		assert.Equal(t, expected, currency)
+
+		resolvedDenom, ok := registry.Denom(expected)
+		require.True(t, ok)
+		assert.Equal(t, denom, resolvedDenom)
+	}
+
+	assert.Equal(t, kava.Currencies, registry.Currencies())
+}
+
+func TestCurrencyRegistry_UnregisteredDenoms(t *testing.T) {
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	for _, denom := range []string{"bnb", "bkava-kavavaloper1ypjp0m04pyp73hwgtc0dgkx0e9rrydecm054da", "erc20/multichain/usdc"} {
+		currency, ok := registry.Currency(denom)
+		require.True(t, ok)
+		assert.Equal(t, &types.Currency{Symbol: denom, Decimals: 0}, currency)
+
+		resolvedDenom, ok := registry.Denom(currency)
+		require.True(t, ok)
+		assert.Equal(t, denom, resolvedDenom)
+	}
+
+	_, ok := registry.Currency("not a denom!")
+	assert.False(t, ok)
+
+	// an unregistered denom must use zero decimals
+	_, ok = registry.Denom(&types.Currency{Symbol: "bnb", Decimals: 8})
+	assert.False(t, ok)
+
+	// registered symbols must match decimals
+	_, ok = registry.Denom(&types.Currency{Symbol: "KAVA", Decimals: 7})
+	assert.False(t, ok)
+
+	// registered denoms can not be used as a symbol
+	_, ok = registry.Denom(&types.Currency{Symbol: "ukava", Decimals: 0})
+	assert.False(t, ok)
+}
+
+func TestCurrencyRegistry_Register(t *testing.T) {
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	err := registry.Register("erc20/tether/usdt", &types.Currency{Symbol: "USDT", Decimals: 6})
+	require.NoError(t, err)
+
+	denom, ok := registry.Denom(&types.Currency{Symbol: "USDT", Decimals: 6})
+	require.True(t, ok)
+	assert.Equal(t, "erc20/tether/usdt", denom)
+
+	err = registry.Register("erc20/other/usdt", &types.Currency{Symbol: "USDT", Decimals: 6})
+	assert.EqualError(t, err, "symbol USDT for denom erc20/other/usdt is already used by denom erc20/tether/usdt")
+
+	err = registry.Register("bnb", &types.Currency{Symbol: "hard", Decimals: 6})
+	assert.EqualError(t, err, "symbol hard for denom bnb is a registered denom")
+
+	err = registry.Register("bnb", &types.Currency{Symbol: "", Decimals: 6})
+	assert.EqualError(t, err, "invalid currency for denom bnb")
+
+	// replacing a currency releases the previous symbol
+	err = registry.Register("erc20/tether/usdt", &types.Currency{Symbol: "USDT.E", Decimals: 6})
+	require.NoError(t, err)
+
+	_, ok = registry.Denom(&types.Currency{Symbol: "USDT", Decimals: 6})
+	assert.False(t, ok)
+}
+
+func TestCurrencyRegistry_RegisterERC20(t *testing.T) {
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	contract := "0x919c1c267bc06a7039e03fcc2ef738525769109c"
+	err := registry.RegisterERC20(contract, &types.Currency{Symbol: "USDt", Decimals: 6})
+	require.NoError(t, err)
+
+	currency, ok := registry.ERC20Currency(common.HexToAddress(contract))
+	require.True(t, ok)
+	assert.Equal(t, &types.Currency{
+		Symbol:   "USDt",
+		Decimals: 6,
+		Metadata: map[string]interface{}{"contract_address": "0x919C1c267BC06a7039e03fcc2eF738525769109c"},
+	}, currency)
+
+	// erc20 currencies do not resolve to a denom
+	_, ok = registry.Denom(currency)
+	assert.False(t, ok)
+
+	assert.Equal(t, map[common.Address]*types.Currency{common.HexToAddress(contract): currency}, registry.ERC20Currencies())
+
+	_, ok = registry.ERC20Currency(common.HexToAddress("0xfA9343C3897324496A05fC75abeD6bAC29f8A40f"))
+	assert.False(t, ok)
+
+	err = registry.RegisterERC20("0xfA9343C3897324496A05fC75abeD6bAC29f8A40f", &types.Currency{Symbol: "USDt", Decimals: 6})
+	assert.EqualError(t, err, "symbol USDt for contract 0xfA9343C3897324496A05fC75abeD6bAC29f8A40f is already used by contract 0x919C1c267BC06a7039e03fcc2eF738525769109c")
+
+	err = registry.RegisterERC20("0xfA9343C3897324496A05fC75abeD6bAC29f8A40f", &types.Currency{Symbol: "KAVA", Decimals: 18})
+	assert.EqualError(t, err, "symbol KAVA for contract 0xfA9343C3897324496A05fC75abeD6bAC29f8A40f is already used by denom ukava")
+
+	err = registry.RegisterERC20("erc20/tether/usdt", &types.Currency{Symbol: "USDT", Decimals: 6})
+	assert.EqualError(t, err, "invalid contract address erc20/tether/usdt")
+
+	err = registry.Register("erc20/tether/usdt", &types.Currency{Symbol: "USDt", Decimals: 6})
+	assert.EqualError(t, err, "symbol USDt for denom erc20/tether/usdt is already used by contract 0x919C1c267BC06a7039e03fcc2eF738525769109c")
+}
+
+func TestCurrencyRegistry_RegisterDenomsMetadata(t *testing.T) {
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	atomTrace := ibctransfertypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}
+	osmoTrace := ibctransfertypes.DenomTrace{Path: "transfer/channel-1", BaseDenom: "uosmo"}
+
+	metadatas := []banktypes.Metadata{
+		{
+			Base:    atomTrace.IBCDenom(),
+			Display: "atom",
+			DenomUnits: []*banktypes.DenomUnit{
+				{Denom: atomTrace.IBCDenom(), Exponent: 0},
+				{Denom: "atom", Exponent: 6},
+			},
+		},
+		{
+			// default currencies are not replaced
+			Base:    "ukava",
+			Display: "kava",
+			Symbol:  "KAVA2",
+			DenomUnits: []*banktypes.DenomUnit{
+				{Denom: "ukava", Exponent: 0},
+				{Denom: "kava", Exponent: 8},
+			},
+		},
+		{
+			// conflicting symbols are skipped
+			Base:    "uhard2",
+			Display: "hard",
+			DenomUnits: []*banktypes.DenomUnit{
+				{Denom: "uhard2", Exponent: 0},
+				{Denom: "hard", Exponent: 6},
+			},
+		},
+		{
+			// metadata without a display unit is skipped
+			Base:    "usomething",
+			Display: "something",
+		},
+	}
+
+	registry.RegisterDenomsMetadata(metadatas, ibctransfertypes.Traces{atomTrace, osmoTrace})
+
+	currency, ok := registry.Currency(atomTrace.IBCDenom())
+	require.True(t, ok)
+	assert.Equal(t, &types.Currency{
+		Symbol:   "ATOM",
+		Decimals: 6,
+		Metadata: map[string]interface{}{
+			"base_denom": atomTrace.IBCDenom(),
+			"ibc_trace":  "transfer/channel-0/uatom",
+		},
+	}, currency)
+
+	currency, ok = registry.Currency(osmoTrace.IBCDenom())
+	require.True(t, ok)
+	assert.Equal(t, &types.Currency{
+		Symbol:   osmoTrace.IBCDenom(),
+		Decimals: 0,
+		Metadata: map[string]interface{}{
+			"ibc_trace": "transfer/channel-1/uosmo",
+		},
+	}, currency)
+
+	currency, ok = registry.Currency("ukava")
+	require.True(t, ok)
+	assert.Equal(t, kava.Currencies["ukava"], currency)
+
+	currency, ok = registry.Currency("uhard2")
+	require.True(t, ok)
+	assert.Equal(t, &types.Currency{Symbol: "uhard2", Decimals: 0}, currency)
+
+	currency, ok = registry.Currency("usomething")
+	require.True(t, ok)
+	assert.Equal(t, &types.Currency{Symbol: "usomething", Decimals: 0}, currency)
+}
+
+func TestLoadDenomsMetadata(t *testing.T) {
+	ctx := context.Background()
+	trace := ibctransfertypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"}
+
+	rpc := &mocks.RPCClient{}
+	rpc.On("DenomsMetadata", ctx, int64(0)).Return([]banktypes.Metadata{}, nil).Once()
+	rpc.On("DenomTraces", ctx, int64(0)).Return(ibctransfertypes.Traces{trace}, nil).Once()
+
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+	err := kava.LoadDenomsMetadata(ctx, rpc, registry)
+	require.NoError(t, err)
+
+	_, ok := registry.Currencies()[trace.IBCDenom()]
+	assert.True(t, ok)
+
+	rpcErr := errors.New("connection refused")
+	rpc.On("DenomsMetadata", ctx, int64(0)).Return(nil, rpcErr).Once()
+
+	err = kava.LoadDenomsMetadata(ctx, rpc, registry)
+	assert.ErrorIs(t, err, rpcErr)
+}
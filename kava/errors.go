@@ -0,0 +1,42 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import "fmt"
+
+// BlockEventsTxIndex is the TxIndex of a TxParseError for begin or end block events
+const BlockEventsTxIndex = -1
+
+// TxParseError is returned when a transaction in a block can not be decoded
+// or converted to rosetta operations
+type TxParseError struct {
+	Height  int64
+	TxIndex int
+	TxHash  string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *TxParseError) Error() string {
+	return fmt.Sprintf(
+		"unable to parse transaction %s at index %d of block %d: %s",
+		e.TxHash, e.TxIndex, e.Height, e.Err,
+	)
+}
+
+// Unwrap returns the underlying parse error
+func (e *TxParseError) Unwrap() error {
+	return e.Err
+}
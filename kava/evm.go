@@ -0,0 +1,149 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	evmtypes "github.com/evmos/ethermint/x/evm/types"
+)
+
+// ethereumTxHashMetadataKey is the transaction metadata key for the hash of an ethereum transaction
+const ethereumTxHashMetadataKey = "ethereum_tx_hash"
+
+// erc20TransferTopic is the topic of the erc20 Transfer(address,address,uint256) event
+var erc20TransferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// ethereumTxHash returns the ethereum transaction hash of the ethereum_tx event, and false
+// if the events do not include an ethereum transaction
+func ethereumTxHash(events sdk.StringEvents) (string, bool) {
+	for _, event := range events {
+		if event.Type != evmtypes.EventTypeEthereumTx {
+			continue
+		}
+
+		for _, attribute := range event.Attributes {
+			if attribute.Key == evmtypes.AttributeKeyEthereumTxHash && attribute.Value != "" {
+				return attribute.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// erc20EventsToOperations returns rosetta operations for the Transfer logs of registered erc20
+// contracts.  Transfers from the zero address are mints and transfers to it are burns.
+func erc20EventsToOperations(registry *CurrencyRegistry, events sdk.StringEvents, status *string, index int64) ([]*types.Operation, error) {
+	operations := []*types.Operation{}
+
+	for _, event := range events {
+		if event.Type != evmtypes.EventTypeTxLog {
+			continue
+		}
+
+		for _, attribute := range event.Attributes {
+			if attribute.Key != evmtypes.AttributeKeyTxLog {
+				continue
+			}
+
+			var log evmtypes.Log
+			if err := json.Unmarshal([]byte(attribute.Value), &log); err != nil {
+				return nil, fmt.Errorf("could not parse log %q in %s event: %w", attribute.Value, evmtypes.EventTypeTxLog, err)
+			}
+
+			logOps := erc20LogToOperations(registry, &log, status, index)
+			operations = appendOperationsAndUpdateIndex(operations, logOps, &index)
+		}
+	}
+
+	return operations, nil
+}
+
+// erc20LogToOperations returns the operations of a log, skipping logs that are not an erc20 Transfer
+// of a registered contract.  Erc721 Transfer logs have a fourth topic and are skipped.
+func erc20LogToOperations(registry *CurrencyRegistry, log *evmtypes.Log, status *string, index int64) []*types.Operation {
+	if len(log.Topics) != 3 || common.HexToHash(log.Topics[0]) != erc20TransferTopic || len(log.Data) != common.HashLength {
+		return []*types.Operation{}
+	}
+
+	if !common.IsHexAddress(log.Address) {
+		return []*types.Operation{}
+	}
+
+	currency, ok := registry.ERC20Currency(common.HexToAddress(log.Address))
+	if !ok {
+		return []*types.Operation{}
+	}
+
+	value := new(big.Int).SetBytes(log.Data)
+	if value.Sign() == 0 {
+		return []*types.Operation{}
+	}
+
+	from := common.HexToAddress(log.Topics[1])
+	to := common.HexToAddress(log.Topics[2])
+
+	switch {
+	case from == (common.Address{}) && to == (common.Address{}):
+		return []*types.Operation{}
+	case from == (common.Address{}):
+		return []*types.Operation{
+			erc20Operation(MintOpType, ethAccountID(to), value.String(), currency, status, index),
+		}
+	case to == (common.Address{}):
+		return []*types.Operation{
+			erc20Operation(BurnOpType, ethAccountID(from), "-"+value.String(), currency, status, index),
+		}
+	}
+
+	recipient := erc20Operation(TransferOpType, ethAccountID(to), value.String(), currency, status, index+1)
+	recipient.RelatedOperations = []*types.OperationIdentifier{newOpID(index)}
+
+	return []*types.Operation{
+		erc20Operation(TransferOpType, ethAccountID(from), "-"+value.String(), currency, status, index),
+		recipient,
+	}
+}
+
+func erc20Operation(
+	opType string,
+	account *types.AccountIdentifier,
+	value string,
+	currency *types.Currency,
+	status *string,
+	index int64,
+) *types.Operation {
+	return &types.Operation{
+		OperationIdentifier: newOpID(index),
+		Type:                opType,
+		Status:              status,
+		Account:             account,
+		Amount: &types.Amount{
+			Value:    value,
+			Currency: currency,
+		},
+	}
+}
+
+// ethAccountID returns the account identifier of the kava address of a hex address
+func ethAccountID(address common.Address) *types.AccountIdentifier {
+	return newAccountID(sdk.AccAddress(address.Bytes()).String())
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	evmtypes "github.com/evmos/ethermint/x/evm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUSDtContract = "0x919C1c267BC06a7039e03fcc2eF738525769109c"
+
+func newTestERC20Registry(t *testing.T) *CurrencyRegistry {
+	registry := NewCurrencyRegistry(Currencies)
+	require.NoError(t, registry.RegisterERC20(testUSDtContract, &types.Currency{Symbol: "USDt", Decimals: 6}))
+
+	return registry
+}
+
+func erc20TransferLogEvent(t *testing.T, contract string, from, to common.Address, value int64) sdk.StringEvent {
+	log := evmtypes.Log{
+		Address: contract,
+		Topics: []string{
+			erc20TransferTopic.Hex(),
+			common.BytesToHash(from.Bytes()).Hex(),
+			common.BytesToHash(to.Bytes()).Hex(),
+		},
+		Data: common.BigToHash(big.NewInt(value)).Bytes(),
+	}
+
+	bz, err := json.Marshal(&log)
+	require.NoError(t, err)
+
+	return sdk.StringEvent{
+		Type:       evmtypes.EventTypeTxLog,
+		Attributes: []sdk.Attribute{{Key: evmtypes.AttributeKeyTxLog, Value: string(bz)}},
+	}
+}
+
+func TestERC20EventsToOperations(t *testing.T) {
+	registry := newTestERC20Registry(t)
+
+	sender := common.HexToAddress("0x7Bbf300890857b8c241b219C6a489431669b3aFA")
+	recipient := common.HexToAddress("0x2d5c2d1b9e9d2f4d8e6f7ea7ae7c0e4f0aa2a9a3")
+	usdt, ok := registry.ERC20Currency(common.HexToAddress(testUSDtContract))
+	require.True(t, ok)
+	assert.Equal(t, &types.Currency{
+		Symbol:   "USDt",
+		Decimals: 6,
+		Metadata: map[string]interface{}{contractAddressMetadataKey: testUSDtContract},
+	}, usdt)
+
+	success := SuccessStatus
+	senderID := newAccountID(sdk.AccAddress(sender.Bytes()).String())
+	recipientID := newAccountID(sdk.AccAddress(recipient.Bytes()).String())
+
+	events := sdk.StringEvents{
+		{Type: evmtypes.EventTypeEthereumTx, Attributes: []sdk.Attribute{{Key: evmtypes.AttributeKeyEthereumTxHash, Value: "0xabc"}}},
+		erc20TransferLogEvent(t, testUSDtContract, sender, recipient, 1500000),
+		// contracts that are not registered are skipped
+		erc20TransferLogEvent(t, "0xfA9343C3897324496A05fC75abeD6bAC29f8A40f", sender, recipient, 100),
+		// zero value transfers are skipped
+		erc20TransferLogEvent(t, testUSDtContract, sender, recipient, 0),
+		erc20TransferLogEvent(t, testUSDtContract, common.Address{}, recipient, 20),
+		erc20TransferLogEvent(t, testUSDtContract, sender, common.Address{}, 30),
+	}
+
+	ops, err := erc20EventsToOperations(registry, events, &success, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*types.Operation{
+		{
+			OperationIdentifier: newOpID(2),
+			Type:                TransferOpType,
+			Status:              &success,
+			Account:             senderID,
+			Amount:              &types.Amount{Value: "-1500000", Currency: usdt},
+		},
+		{
+			OperationIdentifier: newOpID(3),
+			RelatedOperations:   []*types.OperationIdentifier{newOpID(2)},
+			Type:                TransferOpType,
+			Status:              &success,
+			Account:             recipientID,
+			Amount:              &types.Amount{Value: "1500000", Currency: usdt},
+		},
+		{
+			OperationIdentifier: newOpID(4),
+			Type:                MintOpType,
+			Status:              &success,
+			Account:             recipientID,
+			Amount:              &types.Amount{Value: "20", Currency: usdt},
+		},
+		{
+			OperationIdentifier: newOpID(5),
+			Type:                BurnOpType,
+			Status:              &success,
+			Account:             senderID,
+			Amount:              &types.Amount{Value: "-30", Currency: usdt},
+		},
+	}, ops)
+
+	hash, ok := ethereumTxHash(events)
+	require.True(t, ok)
+	assert.Equal(t, "0xabc", hash)
+
+	_, ok = ethereumTxHash(events[1:])
+	assert.False(t, ok)
+
+	t.Run("erc721 transfers are skipped", func(t *testing.T) {
+		event := erc20TransferLogEvent(t, testUSDtContract, sender, recipient, 1)
+
+		var log evmtypes.Log
+		require.NoError(t, json.Unmarshal([]byte(event.Attributes[0].Value), &log))
+		log.Topics = append(log.Topics, common.BigToHash(big.NewInt(1)).Hex())
+		log.Data = nil
+
+		assert.Empty(t, erc20LogToOperations(registry, &log, &success, 0))
+	})
+
+	t.Run("invalid log", func(t *testing.T) {
+		_, err := erc20EventsToOperations(registry, sdk.StringEvents{
+			{Type: evmtypes.EventTypeTxLog, Attributes: []sdk.Attribute{{Key: evmtypes.AttributeKeyTxLog, Value: "{"}}},
+		}, &success, 0)
+		assert.ErrorContains(t, err, "could not parse log \"{\" in tx_log event")
+	})
+}
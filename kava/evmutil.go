@@ -0,0 +1,213 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	evmutiltypes "github.com/kava-labs/kava/x/evmutil/types"
+)
+
+const (
+	// evmAddressMetadataKey is the operation metadata key for the evm side of a conversion
+	evmAddressMetadataKey = "evm_address"
+	// erc20AddressMetadataKey is the operation metadata key for the erc20 contract of a conversion
+	erc20AddressMetadataKey = "erc20_address"
+)
+
+var (
+	// bep3ConversionDenoms are the bep3 denoms with 8 decimals that evmutil converts
+	// to erc20 tokens with 18 decimals
+	bep3ConversionDenoms = map[string]bool{"bnb": true, "btcb": true, "busd": true, "xrpb": true}
+	bep3ConversionFactor = big.NewInt(10_000_000_000)
+)
+
+// conversion is an evmutil conversion between a bank account and an evm address
+type conversion struct {
+	opType     string
+	account    string
+	evmAddress string
+	contract   string
+	// coin is the bank side of the conversion, and nil when it is not known
+	coin *sdk.Coin
+	// erc20Amount is the evm side of the conversion, and nil when it is not known
+	erc20Amount *big.Int
+	// debit is true when the bank account is debited and the evm address credited
+	debit bool
+}
+
+func msgConvertCoinToERC20ToOperations(
+	registry *CurrencyRegistry,
+	msg *evmutiltypes.MsgConvertCoinToERC20,
+	log sdk.ABCIMessageLog,
+	status *string,
+	index int64,
+) []*types.Operation {
+	c := conversion{
+		opType:     ConvertCoinToERC20OpType,
+		account:    msg.Initiator,
+		evmAddress: msg.Receiver,
+		debit:      true,
+	}
+
+	if msg.Amount != nil {
+		c.coin = msg.Amount
+		c.erc20Amount = erc20ConversionAmount(*msg.Amount)
+	}
+
+	// the contract is only known from the event of a successful conversion
+	if attributes, ok := conversionEventAttributes(log, evmutiltypes.EventTypeConvertCoinToERC20); ok {
+		c.contract = attributes[evmutiltypes.AttributeKeyERC20Address]
+	}
+
+	return c.operations(registry, status, index)
+}
+
+func msgConvertERC20ToCoinToOperations(
+	registry *CurrencyRegistry,
+	msg *evmutiltypes.MsgConvertERC20ToCoin,
+	log sdk.ABCIMessageLog,
+	status *string,
+	index int64,
+) ([]*types.Operation, error) {
+	c := conversion{
+		opType:      ConvertERC20ToCoinOpType,
+		account:     msg.Receiver,
+		evmAddress:  msg.Initiator,
+		contract:    msg.KavaERC20Address,
+		erc20Amount: msg.Amount.BigInt(),
+	}
+
+	// the denom and minted amount are only known from the event of a successful conversion
+	if attributes, ok := conversionEventAttributes(log, evmutiltypes.EventTypeConvertERC20ToCoin); ok {
+		coin, err := sdk.ParseCoinNormalized(attributes[evmutiltypes.AttributeKeyAmount])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not parse coin %q in %s event: %w",
+				attributes[evmutiltypes.AttributeKeyAmount], evmutiltypes.EventTypeConvertERC20ToCoin, err,
+			)
+		}
+
+		c.coin = &coin
+		c.erc20Amount = erc20ConversionAmount(coin)
+	}
+
+	return c.operations(registry, status, index), nil
+}
+
+// operations returns an operation for the bank side of the conversion, followed by an
+// operation for the evm side on the kava address of the evm address.  The evm operation
+// only has an amount when the contract is a registered erc20 contract.
+func (c conversion) operations(registry *CurrencyRegistry, status *string, index int64) []*types.Operation {
+	ops := []*types.Operation{}
+
+	if c.coin != nil {
+		if currency, ok := registry.Currency(c.coin.Denom); ok {
+			value := c.coin.Amount.String()
+			if c.debit {
+				value = "-" + value
+			}
+
+			ops = append(ops, &types.Operation{
+				OperationIdentifier: newOpID(index),
+				Type:                c.opType,
+				Status:              status,
+				Account:             newAccountID(c.account),
+				Amount: &types.Amount{
+					Value:    value,
+					Currency: currency,
+				},
+				Metadata: c.metadata(),
+			})
+		}
+	}
+
+	if !common.IsHexAddress(c.evmAddress) {
+		return ops
+	}
+
+	evmOp := &types.Operation{
+		OperationIdentifier: newOpID(index + int64(len(ops))),
+		Type:                c.opType,
+		Status:              status,
+		Account:             ethAccountID(common.HexToAddress(c.evmAddress)),
+		Metadata:            c.metadata(),
+	}
+
+	if len(ops) > 0 {
+		evmOp.RelatedOperations = []*types.OperationIdentifier{newOpID(index)}
+	}
+
+	if c.erc20Amount != nil && common.IsHexAddress(c.contract) {
+		if currency, ok := registry.ERC20Currency(common.HexToAddress(c.contract)); ok {
+			value := c.erc20Amount.String()
+			if !c.debit {
+				value = "-" + value
+			}
+
+			evmOp.Amount = &types.Amount{
+				Value:    value,
+				Currency: currency,
+			}
+		}
+	}
+
+	return append(ops, evmOp)
+}
+
+func (c conversion) metadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		evmAddressMetadataKey: c.evmAddress,
+	}
+
+	if c.contract != "" {
+		metadata[erc20AddressMetadataKey] = c.contract
+	}
+
+	return metadata
+}
+
+// conversionEventAttributes returns the attributes of the conversion event of a message log
+func conversionEventAttributes(log sdk.ABCIMessageLog, eventType string) (map[string]string, bool) {
+	for _, event := range log.Events {
+		if event.Type != eventType {
+			continue
+		}
+
+		attributes := make(map[string]string)
+		for _, attribute := range event.Attributes {
+			attributes[attribute.Key] = attribute.Value
+		}
+
+		return attributes, true
+	}
+
+	return nil, false
+}
+
+// erc20ConversionAmount returns the erc20 amount of a converted coin, using 18 decimals
+// for the erc20 tokens of bep3 denoms
+func erc20ConversionAmount(coin sdk.Coin) *big.Int {
+	amount := coin.Amount.BigInt()
+	if bep3ConversionDenoms[coin.Denom] {
+		amount.Mul(amount, bep3ConversionFactor)
+	}
+
+	return amount
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/ethereum/go-ethereum/common"
+	evmutiltypes "github.com/kava-labs/kava/x/evmutil/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgToOperations_Conversions(t *testing.T) {
+	registry := newTestERC20Registry(t)
+
+	account := testAddresses[0]
+	evmAddress := common.BytesToAddress(getAccAddr(t, account))
+	moduleAddress := authtypes.NewModuleAddress(evmutiltypes.ModuleName).String()
+	usdt, ok := registry.ERC20Currency(common.HexToAddress(testUSDtContract))
+	require.True(t, ok)
+
+	success := SuccessStatus
+	failure := FailureStatus
+
+	t.Run("coin to erc20", func(t *testing.T) {
+		coin := sdk.NewCoin("erc20/tether/usdt", sdkmath.NewInt(1500000))
+		msg := evmutiltypes.NewMsgConvertCoinToERC20(account, evmAddress.Hex(), coin)
+		log := sdk.ABCIMessageLog{
+			Events: sdk.StringEvents{
+				{Type: banktypes.EventTypeTransfer, Attributes: []sdk.Attribute{
+					{Key: banktypes.AttributeKeyRecipient, Value: moduleAddress},
+					{Key: banktypes.AttributeKeySender, Value: account},
+					{Key: sdk.AttributeKeyAmount, Value: coin.String()},
+				}},
+				{Type: banktypes.EventTypeCoinBurn, Attributes: []sdk.Attribute{
+					{Key: banktypes.AttributeKeyBurner, Value: moduleAddress},
+					{Key: sdk.AttributeKeyAmount, Value: coin.String()},
+				}},
+				{Type: evmutiltypes.EventTypeConvertCoinToERC20, Attributes: []sdk.Attribute{
+					{Key: evmutiltypes.AttributeKeyInitiator, Value: account},
+					{Key: evmutiltypes.AttributeKeyReceiver, Value: evmAddress.Hex()},
+					{Key: evmutiltypes.AttributeKeyERC20Address, Value: testUSDtContract},
+					{Key: evmutiltypes.AttributeKeyAmount, Value: coin.String()},
+				}},
+			},
+		}
+		metadata := map[string]interface{}{
+			evmAddressMetadataKey:   evmAddress.Hex(),
+			erc20AddressMetadataKey: testUSDtContract,
+		}
+
+		ops, err := MsgToOperations(registry, &msg, log, &success, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []*types.Operation{
+			{
+				OperationIdentifier: newOpID(3),
+				Type:                ConvertCoinToERC20OpType,
+				Status:              &success,
+				Account:             newAccountID(account),
+				Amount:              &types.Amount{Value: "-1500000", Currency: &types.Currency{Symbol: "erc20/tether/usdt"}},
+				Metadata:            metadata,
+			},
+			{
+				OperationIdentifier: newOpID(4),
+				RelatedOperations:   []*types.OperationIdentifier{newOpID(3)},
+				Type:                ConvertCoinToERC20OpType,
+				Status:              &success,
+				Account:             newAccountID(account),
+				Amount:              &types.Amount{Value: "1500000", Currency: usdt},
+				Metadata:            metadata,
+			},
+		}, ops)
+
+		// the contract is not known for failed conversions
+		ops, err = MsgToOperations(registry, &msg, sdk.ABCIMessageLog{}, &failure, 0)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		assert.Equal(t, "-1500000", ops[0].Amount.Value)
+		assert.Nil(t, ops[1].Amount)
+		assert.Equal(t, map[string]interface{}{evmAddressMetadataKey: evmAddress.Hex()}, ops[1].Metadata)
+	})
+
+	t.Run("erc20 to coin", func(t *testing.T) {
+		contract := "0xfA9343C3897324496A05fC75abeD6bAC29f8A40f"
+		msg := evmutiltypes.NewMsgConvertERC20ToCoin(
+			evmutiltypes.NewInternalEVMAddress(evmAddress),
+			getAccAddr(t, account),
+			evmutiltypes.NewInternalEVMAddress(common.HexToAddress(contract)),
+			sdkmath.NewInt(25000000000),
+		)
+		log := sdk.ABCIMessageLog{
+			Events: sdk.StringEvents{
+				{Type: evmutiltypes.EventTypeConvertERC20ToCoin, Attributes: []sdk.Attribute{
+					{Key: evmutiltypes.AttributeKeyERC20Address, Value: contract},
+					{Key: evmutiltypes.AttributeKeyInitiator, Value: evmAddress.Hex()},
+					{Key: evmutiltypes.AttributeKeyReceiver, Value: account},
+					{Key: evmutiltypes.AttributeKeyAmount, Value: "2bnb"},
+				}},
+			},
+		}
+
+		// contracts that are not registered do not have an erc20 amount
+		ops, err := MsgToOperations(registry, &msg, log, &success, 0)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		assert.Equal(t, ConvertERC20ToCoinOpType, ops[0].Type)
+		assert.Equal(t, "2", ops[0].Amount.Value)
+		assert.Equal(t, "bnb", ops[0].Amount.Currency.Symbol)
+		assert.Equal(t, newAccountID(sdk.AccAddress(evmAddress.Bytes()).String()), ops[1].Account)
+		assert.Nil(t, ops[1].Amount)
+
+		require.NoError(t, registry.RegisterERC20(contract, &types.Currency{Symbol: "BNB", Decimals: 18}))
+		bnb, ok := registry.ERC20Currency(common.HexToAddress(contract))
+		require.True(t, ok)
+
+		// bep3 coins use 8 decimals and their erc20 tokens 18 decimals
+		ops, err = MsgToOperations(registry, &msg, log, &success, 0)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		assert.Equal(t, &types.Amount{Value: "-20000000000", Currency: bnb}, ops[1].Amount)
+
+		// the denom is not known for failed conversions
+		ops, err = MsgToOperations(registry, &msg, sdk.ABCIMessageLog{}, &failure, 0)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		assert.Equal(t, &types.Amount{Value: "-25000000000", Currency: bnb}, ops[0].Amount)
+		assert.Nil(t, ops[0].RelatedOperations)
+
+		log.Events[0].Attributes[3].Value = "bnb"
+		_, err = MsgToOperations(registry, &msg, log, &success, 0)
+		assert.ErrorContains(t, err, "could not parse coin \"bnb\" in convert_evm_erc20_to_coin event")
+	})
+}
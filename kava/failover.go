@@ -0,0 +1,480 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// maxLatestHeightLag is the number of blocks an endpoint may be behind the highest
+	// endpoint before requests for the latest state prefer other endpoints
+	maxLatestHeightLag = 3
+
+	// healthCheckTimeout is the maximum duration of a single endpoint status request
+	healthCheckTimeout = 5 * time.Second
+)
+
+// failoverErrors match errors returned by a node that may succeed on another node,
+// such as a pruned height, a missing tx index entry or an unavailable server
+var failoverErrors = regexp.MustCompile(
+	`is not available, lowest height is|` +
+		`must be less than or equal to the current blockchain height|` +
+		`could not find results for height|` +
+		`tx \([0-9A-F]+\) not found|` +
+		`version does not exist|` +
+		`failed to load state at height|` +
+		`Status: 5\d\d`,
+)
+
+// errUnavailableOnEndpoint is returned by a request that found no result on one endpoint
+// but may find one on another
+var errUnavailableOnEndpoint = errors.New("unavailable on endpoint")
+
+// RPCEndpoint is a kava rpc endpoint used by a FailoverRPCClient
+type RPCEndpoint struct {
+	URL    string
+	Client RPCClient
+}
+
+// RPCEndpointStatus is the last known health of an rpc endpoint.  The network and heights
+// are zero until the first successful health check.
+type RPCEndpointStatus struct {
+	URL            string
+	Healthy        bool
+	CatchingUp     bool
+	Network        string
+	EarliestHeight int64
+	LatestHeight   int64
+}
+
+type rpcEndpoint struct {
+	client RPCClient
+
+	mu     sync.RWMutex
+	status RPCEndpointStatus
+}
+
+func (e *rpcEndpoint) getStatus() RPCEndpointStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.status
+}
+
+func (e *rpcEndpoint) setHealthy(healthy bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.status.Healthy = healthy
+}
+
+func (e *rpcEndpoint) updateStatus(status *ctypes.ResultStatus) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.status.Healthy = true
+	e.status.CatchingUp = status.SyncInfo.CatchingUp
+	e.status.Network = status.NodeInfo.Network
+	e.status.EarliestHeight = status.SyncInfo.EarliestBlockHeight
+	e.status.LatestHeight = status.SyncInfo.LatestBlockHeight
+}
+
+// FailoverRPCClient implements RPCClient over several kava rpc endpoints.
+//
+// Requests are sent to the first endpoint in order that is healthy and holds the
+// requested height, and are retried on the next endpoint when a node is unreachable
+// or can not serve the request.  Requests for the latest state skip endpoints that
+// are catching up or lagging behind the other endpoints.  Endpoint health and heights
+// are refreshed by UpdateHealth, and endpoints are marked unhealthy on connection errors.
+// When a network is required, endpoints are only used once a health check reports that
+// network, so a node of another chain is never used.
+//
+// Methods not used by the rosetta service, such as subscriptions, use the first endpoint.
+type FailoverRPCClient struct {
+	RPCClient
+	network   string
+	endpoints []*rpcEndpoint
+}
+
+var _ RPCClient = (*FailoverRPCClient)(nil)
+
+// NewFailoverRPCClient returns a FailoverRPCClient for the endpoints in order of preference,
+// using only endpoints of the network, or the chain id, provided.  An empty network uses
+// endpoints of any network.
+func NewFailoverRPCClient(endpoints []RPCEndpoint, network string) (*FailoverRPCClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one rpc endpoint is required")
+	}
+
+	c := &FailoverRPCClient{
+		RPCClient: endpoints[0].Client,
+		network:   network,
+	}
+
+	for _, endpoint := range endpoints {
+		c.endpoints = append(c.endpoints, &rpcEndpoint{
+			client: endpoint.Client,
+			status: RPCEndpointStatus{
+				URL:     endpoint.URL,
+				Healthy: true,
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// EndpointStatuses returns the last known status of each endpoint
+func (c *FailoverRPCClient) EndpointStatuses() []RPCEndpointStatus {
+	statuses := make([]RPCEndpointStatus, 0, len(c.endpoints))
+	for _, endpoint := range c.endpoints {
+		statuses = append(statuses, endpoint.getStatus())
+	}
+
+	return statuses
+}
+
+// UpdateHealth fetches the status of every endpoint and updates its health and heights
+func (c *FailoverRPCClient) UpdateHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, endpoint := range c.endpoints {
+		wg.Add(1)
+
+		go func(endpoint *rpcEndpoint) {
+			defer wg.Done()
+
+			statusCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			status, err := endpoint.client.Status(statusCtx)
+			if err != nil {
+				log.Printf("rpc endpoint %s is unhealthy: %s", endpoint.getStatus().URL, err)
+				endpoint.setHealthy(false)
+				return
+			}
+
+			if !c.isNetwork(status.NodeInfo.Network) {
+				log.Printf(
+					"rpc endpoint %s reports network %s instead of %s and is not used",
+					endpoint.getStatus().URL, status.NodeInfo.Network, c.network,
+				)
+			}
+
+			endpoint.updateStatus(status)
+		}(endpoint)
+	}
+
+	wg.Wait()
+}
+
+// MonitorHealth updates endpoint health every interval until the context is done
+func (c *FailoverRPCClient) MonitorHealth(ctx context.Context, interval time.Duration) {
+	for {
+		c.UpdateHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isNetwork returns true if endpoints of the network may be used
+func (c *FailoverRPCClient) isNetwork(network string) bool {
+	return c.network == "" || network == c.network
+}
+
+// candidates returns the endpoints to try in order for a height, where a height
+// of zero or less is the latest height
+func (c *FailoverRPCClient) candidates(height int64) []*rpcEndpoint {
+	var highest int64
+	for _, endpoint := range c.endpoints {
+		if status := endpoint.getStatus(); status.Healthy && c.isNetwork(status.Network) && status.LatestHeight > highest {
+			highest = status.LatestHeight
+		}
+	}
+
+	var preferred, fallback []*rpcEndpoint
+	for _, endpoint := range c.endpoints {
+		status := endpoint.getStatus()
+
+		// endpoints of another network, or not checked yet, are never used
+		if !c.isNetwork(status.Network) {
+			continue
+		}
+
+		// pruned endpoints can never serve the height
+		if height > 0 && status.EarliestHeight > 0 && height < status.EarliestHeight {
+			continue
+		}
+
+		var lagging bool
+		if height > 0 {
+			lagging = status.LatestHeight > 0 && height > status.LatestHeight
+		} else {
+			lagging = status.CatchingUp || status.LatestHeight < highest-maxLatestHeightLag
+		}
+
+		if status.Healthy && !lagging {
+			preferred = append(preferred, endpoint)
+		} else {
+			fallback = append(fallback, endpoint)
+		}
+	}
+
+	return append(preferred, fallback...)
+}
+
+// do calls fn on each candidate endpoint for the height until it succeeds or
+// returns an error that would not succeed on another endpoint
+func do[T any](ctx context.Context, c *FailoverRPCClient, height int64, fn func(RPCClient) (T, error)) (T, error) {
+	var zero T
+
+	candidates := c.candidates(height)
+	if len(candidates) == 0 {
+		if height <= 0 {
+			return zero, fmt.Errorf("no rpc endpoint of network %s is available", c.network)
+		}
+
+		return zero, fmt.Errorf("no rpc endpoint has height %d available", height)
+	}
+
+	var err error
+	for _, endpoint := range candidates {
+		var result T
+
+		result, err = fn(endpoint.client)
+		if err == nil {
+			return result, nil
+		}
+
+		if ctx.Err() != nil {
+			return zero, err
+		}
+
+		if isConnectionError(err) {
+			log.Printf("rpc endpoint %s is unhealthy: %s", endpoint.getStatus().URL, err)
+			endpoint.setHealthy(false)
+		} else if !errors.Is(err, errUnavailableOnEndpoint) && !failoverErrors.MatchString(err.Error()) {
+			return zero, err
+		}
+	}
+
+	return zero, err
+}
+
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	var urlErr *url.Error
+
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
+
+// isDialError returns true for errors connecting to an endpoint, which happen before a request is sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func heightOrLatest(height *int64) int64 {
+	if height == nil {
+		return 0
+	}
+
+	return *height
+}
+
+// Status returns the status of the preferred endpoint for the latest height
+func (c *FailoverRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return do(ctx, c, 0, func(rpc RPCClient) (*ctypes.ResultStatus, error) {
+		return rpc.Status(ctx)
+	})
+}
+
+// NetInfo returns the network info of the preferred endpoint for the latest height
+func (c *FailoverRPCClient) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
+	return do(ctx, c, 0, func(rpc RPCClient) (*ctypes.ResultNetInfo, error) {
+		return rpc.NetInfo(ctx)
+	})
+}
+
+// Block returns the block at a height from an endpoint holding the height
+func (c *FailoverRPCClient) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	return do(ctx, c, heightOrLatest(height), func(rpc RPCClient) (*ctypes.ResultBlock, error) {
+		return rpc.Block(ctx, height)
+	})
+}
+
+// BlockByHash returns the block for a hash, trying other endpoints when a block is not found
+func (c *FailoverRPCClient) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBlock, error) {
+	var notFound *ctypes.ResultBlock
+
+	block, err := do(ctx, c, 0, func(rpc RPCClient) (*ctypes.ResultBlock, error) {
+		block, err := rpc.BlockByHash(ctx, hash)
+		if err == nil && block.Block == nil {
+			// a pruned or lagging node returns an empty block for an unknown hash
+			notFound = block
+			return nil, fmt.Errorf("block %X: %w", hash, errUnavailableOnEndpoint)
+		}
+
+		return block, err
+	})
+	if err != nil && notFound != nil {
+		return notFound, nil
+	}
+
+	return block, err
+}
+
+// BlockResults returns the block results at a height from an endpoint holding the height
+func (c *FailoverRPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	return do(ctx, c, heightOrLatest(height), func(rpc RPCClient) (*ctypes.ResultBlockResults, error) {
+		return rpc.BlockResults(ctx, height)
+	})
+}
+
+// Header returns the block header at a height from an endpoint holding the height
+func (c *FailoverRPCClient) Header(ctx context.Context, height *int64) (*ctypes.ResultHeader, error) {
+	return do(ctx, c, heightOrLatest(height), func(rpc RPCClient) (*ctypes.ResultHeader, error) {
+		return rpc.Header(ctx, height)
+	})
+}
+
+// Tx returns a transaction by hash, trying other endpoints when it is not found
+func (c *FailoverRPCClient) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	return do(ctx, c, 0, func(rpc RPCClient) (*ctypes.ResultTx, error) {
+		return rpc.Tx(ctx, hash, prove)
+	})
+}
+
+// UnconfirmedTxs returns the mempool transactions of the preferred endpoint
+func (c *FailoverRPCClient) UnconfirmedTxs(ctx context.Context, limit *int) (*ctypes.ResultUnconfirmedTxs, error) {
+	return do(ctx, c, 0, func(rpc RPCClient) (*ctypes.ResultUnconfirmedTxs, error) {
+		return rpc.UnconfirmedTxs(ctx, limit)
+	})
+}
+
+// BroadcastTxSync broadcasts a transaction to the preferred endpoint.  A broadcast that may have
+// reached a node is not sent again, so the next endpoint is only tried when an endpoint could not
+// be dialed and the error of the node that received the transaction is always returned.
+func (c *FailoverRPCClient) BroadcastTxSync(ctx context.Context, tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	var err error
+	for _, endpoint := range c.candidates(0) {
+		var result *ctypes.ResultBroadcastTx
+
+		result, err = endpoint.client.BroadcastTxSync(ctx, tx)
+		if err == nil || ctx.Err() != nil || !isDialError(err) {
+			return result, err
+		}
+
+		log.Printf("rpc endpoint %s is unhealthy: %s", endpoint.getStatus().URL, err)
+		endpoint.setHealthy(false)
+	}
+
+	return nil, err
+}
+
+// Account returns the account at a height from an endpoint holding the height
+func (c *FailoverRPCClient) Account(ctx context.Context, addr sdk.AccAddress, height int64) (authtypes.AccountI, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (authtypes.AccountI, error) {
+		return rpc.Account(ctx, addr, height)
+	})
+}
+
+// Balance returns the balance at a height from an endpoint holding the height
+func (c *FailoverRPCClient) Balance(ctx context.Context, addr sdk.AccAddress, height int64) (sdk.Coins, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (sdk.Coins, error) {
+		return rpc.Balance(ctx, addr, height)
+	})
+}
+
+// Delegations returns the delegations at a height from an endpoint holding the height
+func (c *FailoverRPCClient) Delegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.DelegationResponses, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (stakingtypes.DelegationResponses, error) {
+		return rpc.Delegations(ctx, addr, height)
+	})
+}
+
+// UnbondingDelegations returns the unbonding delegations at a height from an endpoint holding the height
+func (c *FailoverRPCClient) UnbondingDelegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.UnbondingDelegations, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (stakingtypes.UnbondingDelegations, error) {
+		return rpc.UnbondingDelegations(ctx, addr, height)
+	})
+}
+
+// StakingValidators returns the staking validators at a height from an endpoint holding the height
+func (c *FailoverRPCClient) StakingValidators(ctx context.Context, status string, height int64) (stakingtypes.Validators, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (stakingtypes.Validators, error) {
+		return rpc.StakingValidators(ctx, status, height)
+	})
+}
+
+// DelegationRewards returns the delegation rewards at a height from an endpoint holding the height
+func (c *FailoverRPCClient) DelegationRewards(ctx context.Context, addr sdk.AccAddress, height int64) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+		return rpc.DelegationRewards(ctx, addr, height)
+	})
+}
+
+// ERC20Balance returns the erc20 balance of a holder at a height from an endpoint holding the height
+func (c *FailoverRPCClient) ERC20Balance(ctx context.Context, contract common.Address, holder common.Address, height int64) (*big.Int, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (*big.Int, error) {
+		return rpc.ERC20Balance(ctx, contract, holder, height)
+	})
+}
+
+// DenomsMetadata returns the denom metadata at a height from an endpoint holding the height
+func (c *FailoverRPCClient) DenomsMetadata(ctx context.Context, height int64) ([]banktypes.Metadata, error) {
+	return do(ctx, c, height, func(rpc RPCClient) ([]banktypes.Metadata, error) {
+		return rpc.DenomsMetadata(ctx, height)
+	})
+}
+
+// DenomTraces returns the ibc denom traces at a height from an endpoint holding the height
+func (c *FailoverRPCClient) DenomTraces(ctx context.Context, height int64) (ibctransfertypes.Traces, error) {
+	return do(ctx, c, height, func(rpc RPCClient) (ibctransfertypes.Traces, error) {
+		return rpc.DenomTraces(ctx, height)
+	})
+}
+
+// SimulateTx simulates a transaction on the preferred endpoint
+func (c *FailoverRPCClient) SimulateTx(ctx context.Context, tx authsigning.Tx) (*sdk.SimulationResponse, error) {
+	return do(ctx, c, 0, func(rpc RPCClient) (*sdk.SimulationResponse, error) {
+		return rpc.SimulateTx(ctx, tx)
+	})
+}
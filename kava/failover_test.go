@@ -0,0 +1,301 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+	"github.com/kava-labs/rosetta-kava/kava/mocks"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFailoverClient(t *testing.T) (*mocks.RPCClient, *mocks.RPCClient, *kava.FailoverRPCClient) {
+	primary := &mocks.RPCClient{}
+	archive := &mocks.RPCClient{}
+
+	client, err := kava.NewFailoverRPCClient([]kava.RPCEndpoint{
+		{URL: "http://primary:26657", Client: primary},
+		{URL: "http://archive:26657", Client: archive},
+	}, "")
+	require.NoError(t, err)
+
+	return primary, archive, client
+}
+
+func mockStatus(earliest, latest int64, catchingUp bool) *ctypes.ResultStatus {
+	return &ctypes.ResultStatus{
+		SyncInfo: ctypes.SyncInfo{
+			EarliestBlockHeight: earliest,
+			LatestBlockHeight:   latest,
+			CatchingUp:          catchingUp,
+		},
+	}
+}
+
+func TestNewFailoverRPCClient_NoEndpoints(t *testing.T) {
+	_, err := kava.NewFailoverRPCClient(nil, "")
+	assert.EqualError(t, err, "at least one rpc endpoint is required")
+}
+
+func TestFailoverRPCClient_ConnectionError(t *testing.T) {
+	ctx := context.Background()
+	primary, archive, client := setupFailoverClient(t)
+
+	connErr := &url.Error{Op: "Post", URL: "http://primary:26657", Err: errors.New("connection refused")}
+	primary.On("Block", ctx, (*int64)(nil)).Return(nil, connErr).Once()
+	archive.On("Block", ctx, (*int64)(nil)).Return(mockResultBlock(100), nil).Twice()
+
+	block, err := client.Block(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), block.Block.Height)
+
+	statuses := client.EndpointStatuses()
+	assert.False(t, statuses[0].Healthy)
+	assert.True(t, statuses[1].Healthy)
+
+	// unhealthy endpoints are tried last
+	_, err = client.Block(ctx, nil)
+	require.NoError(t, err)
+
+	// a successful health check restores the endpoint
+	primary.On("Status", mock.Anything).Return(mockStatus(1, 100, false), nil).Once()
+	archive.On("Status", mock.Anything).Return(mockStatus(1, 100, false), nil).Once()
+	client.UpdateHealth(ctx)
+	assert.True(t, client.EndpointStatuses()[0].Healthy)
+
+	primary.AssertExpectations(t)
+	archive.AssertExpectations(t)
+}
+
+func TestFailoverRPCClient_NonRetriableError(t *testing.T) {
+	ctx := context.Background()
+	primary, archive, client := setupFailoverClient(t)
+
+	height := int64(100)
+	rpcErr := errors.New("invalid params")
+	primary.On("BlockResults", ctx, &height).Return(nil, rpcErr).Once()
+
+	_, err := client.BlockResults(ctx, &height)
+	assert.Equal(t, rpcErr, err)
+	assert.True(t, client.EndpointStatuses()[0].Healthy)
+
+	primary.AssertExpectations(t)
+	archive.AssertExpectations(t)
+}
+
+func TestFailoverRPCClient_BroadcastTxSync(t *testing.T) {
+	tx := tmtypes.Tx("test tx")
+	result := &ctypes.ResultBroadcastTx{Hash: tx.Hash()}
+
+	testCases := []struct {
+		name         string
+		primaryErr   error
+		expectedErr  error
+		failedOver   bool
+		primaryState bool
+	}{
+		{
+			name:         "dial errors fail over",
+			primaryErr:   &url.Error{Op: "Post", URL: "http://primary:26657", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}},
+			failedOver:   true,
+			primaryState: false,
+		},
+		{
+			name:         "errors after the request is sent do not fail over",
+			primaryErr:   &url.Error{Op: "Post", URL: "http://primary:26657", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}},
+			expectedErr:  &url.Error{Op: "Post", URL: "http://primary:26657", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}},
+			primaryState: true,
+		},
+		{
+			name:         "server errors do not fail over",
+			primaryErr:   errors.New("post failed: Status: 503 Service Unavailable"),
+			expectedErr:  errors.New("post failed: Status: 503 Service Unavailable"),
+			primaryState: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			primary, archive, client := setupFailoverClient(t)
+
+			primary.On("BroadcastTxSync", ctx, tx).Return(nil, tc.primaryErr).Once()
+			if tc.failedOver {
+				archive.On("BroadcastTxSync", ctx, tx).Return(result, nil).Once()
+			}
+
+			broadcast, err := client.BroadcastTxSync(ctx, tx)
+			if tc.expectedErr != nil {
+				assert.Nil(t, broadcast)
+				assert.Equal(t, tc.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, result, broadcast)
+			}
+			assert.Equal(t, tc.primaryState, client.EndpointStatuses()[0].Healthy)
+
+			primary.AssertExpectations(t)
+			archive.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFailoverRPCClient_HeightRouting(t *testing.T) {
+	ctx := context.Background()
+	primary, archive, client := setupFailoverClient(t)
+
+	primary.On("Status", mock.Anything).Return(mockStatus(1000, 2000, false), nil).Once()
+	archive.On("Status", mock.Anything).Return(mockStatus(1, 1990, false), nil).Once()
+	client.UpdateHealth(ctx)
+
+	assert.Equal(t, []kava.RPCEndpointStatus{
+		{URL: "http://primary:26657", Healthy: true, EarliestHeight: 1000, LatestHeight: 2000},
+		{URL: "http://archive:26657", Healthy: true, EarliestHeight: 1, LatestHeight: 1990},
+	}, client.EndpointStatuses())
+
+	addr := sdk.AccAddress("test address")
+
+	// heights pruned by the primary are only routed to the archive
+	archive.On("Balance", ctx, addr, int64(500)).Return(sdk.NewCoins(sdk.NewInt64Coin("ukava", 1)), nil).Once()
+	_, err := client.Balance(ctx, addr, 500)
+	require.NoError(t, err)
+
+	// heights above the archive height prefer the primary
+	primary.On("Balance", ctx, addr, int64(1995)).Return(sdk.NewCoins(sdk.NewInt64Coin("ukava", 2)), nil).Once()
+	_, err = client.Balance(ctx, addr, 1995)
+	require.NoError(t, err)
+
+	// heights held by both use endpoints in order
+	primary.On("Balance", ctx, addr, int64(1500)).Return(sdk.NewCoins(sdk.NewInt64Coin("ukava", 3)), nil).Once()
+	_, err = client.Balance(ctx, addr, 1500)
+	require.NoError(t, err)
+
+	// endpoints lagging behind are not preferred for the latest height
+	primary.On("Status", mock.Anything).Return(mockStatus(1000, 1980, false), nil).Once()
+	archive.On("Status", mock.Anything).Return(mockStatus(1, 1990, false), nil).Once()
+	client.UpdateHealth(ctx)
+
+	archive.On("Balance", ctx, addr, int64(0)).Return(sdk.NewCoins(sdk.NewInt64Coin("ukava", 4)), nil).Once()
+	_, err = client.Balance(ctx, addr, 0)
+	require.NoError(t, err)
+
+	// pruned state errors are retried on the next endpoint
+	primary.On("Balance", ctx, addr, int64(1200)).Return(nil, errors.New("version does not exist")).Once()
+	archive.On("Balance", ctx, addr, int64(1200)).Return(sdk.NewCoins(sdk.NewInt64Coin("ukava", 5)), nil).Once()
+	coins, err := client.Balance(ctx, addr, 1200)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ukava", 5)), coins)
+
+	primary.AssertExpectations(t)
+	archive.AssertExpectations(t)
+}
+
+func TestFailoverRPCClient_NoEndpointForHeight(t *testing.T) {
+	ctx := context.Background()
+	primary, archive, client := setupFailoverClient(t)
+
+	primary.On("Status", mock.Anything).Return(mockStatus(1000, 2000, false), nil).Once()
+	archive.On("Status", mock.Anything).Return(mockStatus(500, 2000, false), nil).Once()
+	client.UpdateHealth(ctx)
+
+	height := int64(100)
+	_, err := client.Block(ctx, &height)
+	assert.EqualError(t, err, "no rpc endpoint has height 100 available")
+
+	primary.AssertExpectations(t)
+	archive.AssertExpectations(t)
+}
+
+func TestFailoverRPCClient_Network(t *testing.T) {
+	ctx := context.Background()
+	primary := &mocks.RPCClient{}
+	archive := &mocks.RPCClient{}
+
+	client, err := kava.NewFailoverRPCClient([]kava.RPCEndpoint{
+		{URL: "http://primary:26657", Client: primary},
+		{URL: "http://archive:26657", Client: archive},
+	}, "kava_2221-16000")
+	require.NoError(t, err)
+
+	// endpoints are not used before they report their network
+	_, err = client.Status(ctx)
+	assert.EqualError(t, err, "no rpc endpoint of network kava_2221-16000 is available")
+
+	wrongNetwork := mockStatus(1, 2000, false)
+	wrongNetwork.NodeInfo.Network = "kava_2222-10"
+	primary.On("Status", mock.Anything).Return(wrongNetwork, nil).Once()
+
+	network := mockStatus(1, 1990, false)
+	network.NodeInfo.Network = "kava_2221-16000"
+	archive.On("Status", mock.Anything).Return(network, nil).Once()
+	client.UpdateHealth(ctx)
+
+	assert.Equal(t, []kava.RPCEndpointStatus{
+		{URL: "http://primary:26657", Healthy: true, Network: "kava_2222-10", EarliestHeight: 1, LatestHeight: 2000},
+		{URL: "http://archive:26657", Healthy: true, Network: "kava_2221-16000", EarliestHeight: 1, LatestHeight: 1990},
+	}, client.EndpointStatuses())
+
+	// the endpoint of another network is removed from rotation, even when it is ahead
+	archive.On("Status", ctx).Return(network, nil).Once()
+	status, err := client.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "kava_2221-16000", status.NodeInfo.Network)
+
+	height := int64(1000)
+	archive.On("Block", ctx, &height).Return(nil, errors.New("Status: 503")).Once()
+	_, err = client.Block(ctx, &height)
+	assert.EqualError(t, err, "Status: 503")
+
+	primary.AssertExpectations(t)
+	archive.AssertExpectations(t)
+}
+
+func TestFailoverRPCClient_BlockByHash(t *testing.T) {
+	ctx := context.Background()
+	primary, archive, client := setupFailoverClient(t)
+
+	found := mockResultBlock(100)
+	hash := []byte(found.BlockID.Hash)
+
+	primary.On("BlockByHash", ctx, hash).Return(&ctypes.ResultBlock{}, nil).Once()
+	archive.On("BlockByHash", ctx, hash).Return(found, nil).Once()
+
+	block, err := client.BlockByHash(ctx, hash)
+	require.NoError(t, err)
+	assert.Equal(t, found, block)
+
+	// a hash unknown to every endpoint returns an empty block
+	unknown := []byte{0xff}
+	primary.On("BlockByHash", ctx, unknown).Return(&ctypes.ResultBlock{}, nil).Once()
+	archive.On("BlockByHash", ctx, unknown).Return(&ctypes.ResultBlock{}, nil).Once()
+
+	block, err = client.BlockByHash(ctx, unknown)
+	require.NoError(t, err)
+	assert.Nil(t, block.Block)
+
+	primary.AssertExpectations(t)
+	archive.AssertExpectations(t)
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"strconv"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+)
+
+const (
+	// sourcePortMetadataKey is the operation metadata key for the source port of an ibc transfer
+	sourcePortMetadataKey = "source_port"
+	// sourceChannelMetadataKey is the operation metadata key for the source channel of an ibc transfer
+	sourceChannelMetadataKey = "source_channel"
+	// receiverMetadataKey is the operation metadata key for the receiver of an ibc transfer on the
+	// counterparty chain
+	receiverMetadataKey = "receiver"
+	// timeoutHeightMetadataKey is the operation metadata key for the counterparty height after which
+	// an ibc transfer times out
+	timeoutHeightMetadataKey = "timeout_height"
+	// timeoutTimestampMetadataKey is the operation metadata key for the counterparty time in unix
+	// nanoseconds after which an ibc transfer times out
+	timeoutTimestampMetadataKey = "timeout_timestamp"
+)
+
+// markIBCTransferOperations sets the type and metadata of the transfer operations of an ibc transfer,
+// which move tokens from the sender to the escrow account of the channel, or to the transfer module
+// account when vouchers are burned on their way back to their source chain
+func markIBCTransferOperations(ops []*types.Operation, msg *ibctransfertypes.MsgTransfer) {
+	for _, op := range ops {
+		if op.Type != TransferOpType {
+			continue
+		}
+
+		op.Type = IBCTransferOpType
+		op.Metadata = IBCTransferMetadata(msg)
+	}
+}
+
+// msgTransferToOperations returns operations debiting the sender and crediting the escrow account
+// of the source channel, used for failed ibc transfers which do not emit transfer events
+func msgTransferToOperations(registry *CurrencyRegistry, msg *ibctransfertypes.MsgTransfer, status *string, index int64) []*types.Operation {
+	if msg.Token.Amount.IsNil() || !msg.Token.Amount.IsPositive() {
+		return []*types.Operation{}
+	}
+
+	sender := newAccountID(msg.Sender)
+	escrow := newAccountID(ibctransfertypes.GetEscrowAddress(msg.SourcePort, msg.SourceChannel).String())
+
+	ops := balanceTrackingOps(registry, IBCTransferOpType, sender, sdk.Coins{msg.Token}, escrow, status, index)
+	for _, op := range ops {
+		op.Metadata = IBCTransferMetadata(msg)
+	}
+
+	return ops
+}
+
+// IBCTransferMetadata returns the operation metadata of an ibc transfer, including only the
+// timeouts that are set
+func IBCTransferMetadata(msg *ibctransfertypes.MsgTransfer) map[string]interface{} {
+	metadata := map[string]interface{}{
+		sourcePortMetadataKey:    msg.SourcePort,
+		sourceChannelMetadataKey: msg.SourceChannel,
+		receiverMetadataKey:      msg.Receiver,
+	}
+
+	if !msg.TimeoutHeight.IsZero() {
+		metadata[timeoutHeightMetadataKey] = msg.TimeoutHeight.String()
+	}
+
+	if msg.TimeoutTimestamp != 0 {
+		metadata[timeoutTimestampMetadataKey] = strconv.FormatUint(msg.TimeoutTimestamp, 10)
+	}
+
+	return metadata
+}
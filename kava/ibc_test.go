@@ -0,0 +1,106 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgToOperations_IBCTransfer(t *testing.T) {
+	sender := testAddresses[0]
+	escrow := ibctransfertypes.GetEscrowAddress(ibctransfertypes.PortID, "channel-0").String()
+	msg := ibctransfertypes.NewMsgTransfer(
+		ibctransfertypes.PortID,
+		"channel-0",
+		sdk.NewInt64Coin("ukava", 1000000),
+		sender,
+		"cosmos1vlpsrmdyuywvaqrv7rx6xga224sqfwz3t7gsv6",
+		clienttypes.ZeroHeight(),
+		1700000000000000000,
+		"",
+	)
+	metadata := map[string]interface{}{
+		sourcePortMetadataKey:       ibctransfertypes.PortID,
+		sourceChannelMetadataKey:    "channel-0",
+		receiverMetadataKey:         "cosmos1vlpsrmdyuywvaqrv7rx6xga224sqfwz3t7gsv6",
+		timeoutTimestampMetadataKey: "1700000000000000000",
+	}
+	kava := &types.Currency{Symbol: "KAVA", Decimals: 6}
+
+	t.Run("success", func(t *testing.T) {
+		status := SuccessStatus
+		log := sdk.ABCIMessageLog{
+			Events: sdk.StringEvents{
+				{Type: banktypes.EventTypeTransfer, Attributes: []sdk.Attribute{
+					{Key: banktypes.AttributeKeyRecipient, Value: escrow},
+					{Key: banktypes.AttributeKeySender, Value: sender},
+					{Key: sdk.AttributeKeyAmount, Value: "1000000ukava"},
+				}},
+				{Type: ibctransfertypes.EventTypeTransfer, Attributes: []sdk.Attribute{
+					{Key: sdk.AttributeKeySender, Value: sender},
+					{Key: ibctransfertypes.AttributeKeyReceiver, Value: msg.Receiver},
+				}},
+			},
+		}
+
+		ops, err := MsgToOperations(testRegistry, msg, log, &status, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []*types.Operation{
+			{
+				OperationIdentifier: newOpID(1),
+				Type:                IBCTransferOpType,
+				Status:              &status,
+				Account:             newAccountID(sender),
+				Amount:              &types.Amount{Value: "-1000000", Currency: kava},
+				Metadata:            metadata,
+			},
+			{
+				OperationIdentifier: newOpID(2),
+				RelatedOperations:   []*types.OperationIdentifier{newOpID(1)},
+				Type:                IBCTransferOpType,
+				Status:              &status,
+				Account:             newAccountID(escrow),
+				Amount:              &types.Amount{Value: "1000000", Currency: kava},
+				Metadata:            metadata,
+			},
+		}, ops)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		status := FailureStatus
+
+		ops, err := MsgToOperations(testRegistry, msg, sdk.ABCIMessageLog{}, &status, 0)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+
+		assert.Equal(t, IBCTransferOpType, ops[0].Type)
+		assert.Equal(t, &status, ops[0].Status)
+		assert.Equal(t, newAccountID(sender), ops[0].Account)
+		assert.Equal(t, "-1000000", ops[0].Amount.Value)
+		assert.Equal(t, metadata, ops[0].Metadata)
+
+		assert.Equal(t, newAccountID(escrow), ops[1].Account)
+		assert.Equal(t, "1000000", ops[1].Amount.Value)
+		assert.Equal(t, metadata, ops[1].Metadata)
+	})
+}
@@ -6,12 +6,22 @@ import (
 	bytes "github.com/cometbft/cometbft/libs/bytes"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	big "math/big"
+
 	client "github.com/cometbft/cometbft/rpc/client"
 
 	cometbfttypes "github.com/cometbft/cometbft/types"
 
+	common "github.com/ethereum/go-ethereum/common"
+
 	context "context"
 
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+
 	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 
 	log "github.com/cometbft/cometbft/libs/log"
@@ -570,6 +580,36 @@ func (_m *RPCClient) ConsensusState(_a0 context.Context) (*coretypes.ResultConse
 	return r0, r1
 }
 
+// DelegationRewards provides a mock function with given fields: ctx, addr, height
+func (_m *RPCClient) DelegationRewards(ctx context.Context, addr types.AccAddress, height int64) (*distributiontypes.QueryDelegationTotalRewardsResponse, error) {
+	ret := _m.Called(ctx, addr, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DelegationRewards")
+	}
+
+	var r0 *distributiontypes.QueryDelegationTotalRewardsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, int64) (*distributiontypes.QueryDelegationTotalRewardsResponse, error)); ok {
+		return rf(ctx, addr, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress, int64) *distributiontypes.QueryDelegationTotalRewardsResponse); ok {
+		r0 = rf(ctx, addr, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*distributiontypes.QueryDelegationTotalRewardsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress, int64) error); ok {
+		r1 = rf(ctx, addr, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delegations provides a mock function with given fields: ctx, addr, height
 func (_m *RPCClient) Delegations(ctx context.Context, addr types.AccAddress, height int64) (stakingtypes.DelegationResponses, error) {
 	ret := _m.Called(ctx, addr, height)
@@ -600,6 +640,66 @@ func (_m *RPCClient) Delegations(ctx context.Context, addr types.AccAddress, hei
 	return r0, r1
 }
 
+// DenomTraces provides a mock function with given fields: ctx, height
+func (_m *RPCClient) DenomTraces(ctx context.Context, height int64) (ibctransfertypes.Traces, error) {
+	ret := _m.Called(ctx, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DenomTraces")
+	}
+
+	var r0 ibctransfertypes.Traces
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (ibctransfertypes.Traces, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ibctransfertypes.Traces); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ibctransfertypes.Traces)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DenomsMetadata provides a mock function with given fields: ctx, height
+func (_m *RPCClient) DenomsMetadata(ctx context.Context, height int64) ([]banktypes.Metadata, error) {
+	ret := _m.Called(ctx, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DenomsMetadata")
+	}
+
+	var r0 []banktypes.Metadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]banktypes.Metadata, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []banktypes.Metadata); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]banktypes.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DumpConsensusState provides a mock function with given fields: _a0
 func (_m *RPCClient) DumpConsensusState(_a0 context.Context) (*coretypes.ResultDumpConsensusState, error) {
 	ret := _m.Called(_a0)
@@ -630,6 +730,36 @@ func (_m *RPCClient) DumpConsensusState(_a0 context.Context) (*coretypes.ResultD
 	return r0, r1
 }
 
+// ERC20Balance provides a mock function with given fields: ctx, contract, holder, height
+func (_m *RPCClient) ERC20Balance(ctx context.Context, contract common.Address, holder common.Address, height int64) (*big.Int, error) {
+	ret := _m.Called(ctx, contract, holder, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ERC20Balance")
+	}
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, common.Address, int64) (*big.Int, error)); ok {
+		return rf(ctx, contract, holder, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, common.Address, int64) *big.Int); ok {
+		r0 = rf(ctx, contract, holder, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, common.Address, int64) error); ok {
+		r1 = rf(ctx, contract, holder, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Genesis provides a mock function with given fields: _a0
 func (_m *RPCClient) Genesis(_a0 context.Context) (*coretypes.ResultGenesis, error) {
 	ret := _m.Called(_a0)
@@ -972,6 +1102,36 @@ func (_m *RPCClient) SimulateTx(ctx context.Context, tx signing.Tx) (*types.Simu
 	return r0, r1
 }
 
+// StakingValidators provides a mock function with given fields: ctx, status, height
+func (_m *RPCClient) StakingValidators(ctx context.Context, status string, height int64) (stakingtypes.Validators, error) {
+	ret := _m.Called(ctx, status, height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StakingValidators")
+	}
+
+	var r0 stakingtypes.Validators
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (stakingtypes.Validators, error)); ok {
+		return rf(ctx, status, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) stakingtypes.Validators); ok {
+		r0 = rf(ctx, status, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(stakingtypes.Validators)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, status, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *RPCClient) Start() error {
 	ret := _m.Called()
@@ -22,29 +22,38 @@ import (
 	authante "github.com/cosmos/cosmos-sdk/x/auth/ante"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/crypto"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	evmutiltypes "github.com/kava-labs/kava/x/evmutil/types"
 )
 
+const validatorAddressMetadataKey = "validator_address"
+
 var (
 	feeCollectorAddress = sdk.AccAddress(crypto.AddressHash([]byte(authtypes.FeeCollectorName)))
 )
 
 // EventsToOperations returns rosetta operations from abci block events
-func EventsToOperations(events sdk.StringEvents, status *string, index int64) []*types.Operation {
+func EventsToOperations(registry *CurrencyRegistry, events sdk.StringEvents, status *string, index int64) ([]*types.Operation, error) {
 	operations := []*types.Operation{}
 
 	for _, event := range events {
-		eventOps := EventToOperations(event, status, index)
+		eventOps, err := EventToOperations(registry, event, status, index)
+		if err != nil {
+			return nil, err
+		}
 		operations = appendOperationsAndUpdateIndex(operations, eventOps, &index)
 	}
 
-	return operations
+	return operations, nil
 }
 
 // EventToOperations returns rosetta operations from a abci block event
-func EventToOperations(event sdk.StringEvent, status *string, index int64) []*types.Operation {
+func EventToOperations(registry *CurrencyRegistry, event sdk.StringEvent, status *string, index int64) ([]*types.Operation, error) {
 	attributeMap := make(map[string]string)
 
 	for _, attribute := range event.Attributes {
@@ -53,79 +62,205 @@ func EventToOperations(event sdk.StringEvent, status *string, index int64) []*ty
 
 	switch event.Type {
 	case banktypes.EventTypeTransfer:
-		return bankTransferEventToOperations(attributeMap, status, index)
+		return bankTransferEventToOperations(registry, attributeMap, status, index)
 	case banktypes.EventTypeCoinMint:
-		return bankMintEventToOperations(attributeMap, status, index)
+		return bankMintEventToOperations(registry, attributeMap, status, index)
 	case banktypes.EventTypeCoinBurn:
-		return bankBurnEventToOperations(attributeMap, status, index)
+		return bankBurnEventToOperations(registry, attributeMap, status, index)
+	case stakingtypes.EventTypeCreateValidator:
+		return stakingCreateValidatorEventToOperations(registry, attributeMap, status, index)
+	case stakingtypes.EventTypeDelegate:
+		return stakingDelegateEventToOperations(registry, attributeMap, status, index)
+	case stakingtypes.EventTypeUnbond:
+		return stakingUnbondEventToOperations(registry, attributeMap, status, index)
+	case stakingtypes.EventTypeCancelUnbondingDelegation:
+		return stakingCancelUnbondingEventToOperations(registry, attributeMap, status, index)
+	case stakingtypes.EventTypeCompleteUnbonding:
+		return stakingCompleteUnbondingEventToOperations(registry, attributeMap, status, index)
 	}
 
-	return []*types.Operation{}
+	return []*types.Operation{}, nil
 }
 
-func bankTransferEventToOperations(attributes map[string]string, status *string, index int64) []*types.Operation {
+func bankTransferEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
 	recipient := &types.AccountIdentifier{
 		Address: attributes[banktypes.AttributeKeyRecipient],
 	}
 
-	amount, err := sdk.ParseCoinsNormalized(attributes[sdk.AttributeKeyAmount])
+	amount, err := parseEventCoins(banktypes.EventTypeTransfer, attributes)
 	if err != nil {
-		panic(fmt.Sprintf("could not parse coins: %s", attributes[sdk.AttributeKeyAmount]))
+		return nil, err
 	}
 
 	sender := &types.AccountIdentifier{
 		Address: attributes[banktypes.AttributeKeySender],
 	}
 
-	return balanceTrackingOps(TransferOpType, sender, amount, recipient, status, index)
+	return balanceTrackingOps(registry, TransferOpType, sender, amount, recipient, status, index), nil
 }
 
-func bankMintEventToOperations(attributes map[string]string, status *string, index int64) []*types.Operation {
+func bankMintEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
 	minter := &types.AccountIdentifier{
 		Address: attributes[banktypes.AttributeKeyMinter],
 	}
 
-	amount, err := sdk.ParseCoinsNormalized(attributes[sdk.AttributeKeyAmount])
+	amount, err := parseEventCoins(banktypes.EventTypeCoinMint, attributes)
 	if err != nil {
-		panic(fmt.Sprintf("could not parse coins: %s", attributes[sdk.AttributeKeyAmount]))
+		return nil, err
 	}
 
-	return accountBalanceOps(MintOpType, amount, false, minter, status, index)
+	return accountBalanceOps(registry, MintOpType, amount, false, minter, status, index), nil
 }
 
-func bankBurnEventToOperations(attributes map[string]string, status *string, index int64) []*types.Operation {
+func bankBurnEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
 	burner := &types.AccountIdentifier{
 		Address: attributes[banktypes.AttributeKeyBurner],
 	}
 
+	amount, err := parseEventCoins(banktypes.EventTypeCoinBurn, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountBalanceOps(registry, BurnOpType, amount, true, burner, status, index), nil
+}
+
+// the self delegation of a new validator is made from the validator operator's account
+func stakingCreateValidatorEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
+	validator := attributes[stakingtypes.AttributeKeyValidator]
+	valAddr, err := sdk.ValAddressFromBech32(validator)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse validator address %q in %s event: %w", validator, stakingtypes.EventTypeCreateValidator, err)
+	}
+
+	amount, err := parseEventCoins(stakingtypes.EventTypeCreateValidator, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	delegated := newSubAccountID(sdk.AccAddress(valAddr).String(), AccLiquidDelegated)
+
+	ops := accountBalanceOps(registry, DelegateOpType, amount, false, delegated, status, index)
+	return withValidatorMetadata(ops, validator), nil
+}
+
+func stakingDelegateEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
+	// events emitted outside of the staking module may not include a delegator
+	delegator, ok := attributes[stakingtypes.AttributeKeyDelegator]
+	if !ok {
+		return []*types.Operation{}, nil
+	}
+
+	amount, err := parseEventCoins(stakingtypes.EventTypeDelegate, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	delegated := newSubAccountID(delegator, AccLiquidDelegated)
+
+	ops := accountBalanceOps(registry, DelegateOpType, amount, false, delegated, status, index)
+	return withValidatorMetadata(ops, attributes[stakingtypes.AttributeKeyValidator]), nil
+}
+
+func stakingUnbondEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
+	delegator, ok := attributes[stakingtypes.AttributeKeyDelegator]
+	if !ok {
+		return []*types.Operation{}, nil
+	}
+
+	amount, err := parseEventCoins(stakingtypes.EventTypeUnbond, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	delegated := newSubAccountID(delegator, AccLiquidDelegated)
+	unbonding := newSubAccountID(delegator, AccLiquidUnbonding)
+
+	ops := balanceTrackingOps(registry, UndelegateOpType, delegated, amount, unbonding, status, index)
+	return withValidatorMetadata(ops, attributes[stakingtypes.AttributeKeyValidator]), nil
+}
+
+func stakingCancelUnbondingEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
+	delegator, ok := attributes[stakingtypes.AttributeKeyDelegator]
+	if !ok {
+		return []*types.Operation{}, nil
+	}
+
+	amount, err := parseEventCoins(stakingtypes.EventTypeCancelUnbondingDelegation, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	unbonding := newSubAccountID(delegator, AccLiquidUnbonding)
+	delegated := newSubAccountID(delegator, AccLiquidDelegated)
+
+	ops := balanceTrackingOps(registry, CancelUnbondingOpType, unbonding, amount, delegated, status, index)
+	return withValidatorMetadata(ops, attributes[stakingtypes.AttributeKeyValidator]), nil
+}
+
+// the matching transfer of the unbonded coins to the delegator is emitted as a separate bank transfer event
+func stakingCompleteUnbondingEventToOperations(registry *CurrencyRegistry, attributes map[string]string, status *string, index int64) ([]*types.Operation, error) {
+	delegator, ok := attributes[stakingtypes.AttributeKeyDelegator]
+	if !ok {
+		return []*types.Operation{}, nil
+	}
+
+	amount, err := parseEventCoins(stakingtypes.EventTypeCompleteUnbonding, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	unbonding := newSubAccountID(delegator, AccLiquidUnbonding)
+
+	ops := accountBalanceOps(registry, CompleteUnbondingOpType, amount, true, unbonding, status, index)
+	return withValidatorMetadata(ops, attributes[stakingtypes.AttributeKeyValidator]), nil
+}
+
+func parseEventCoins(eventType string, attributes map[string]string) (sdk.Coins, error) {
 	amount, err := sdk.ParseCoinsNormalized(attributes[sdk.AttributeKeyAmount])
 	if err != nil {
-		panic(fmt.Sprintf("could not parse coins: %s", attributes[sdk.AttributeKeyAmount]))
+		return nil, fmt.Errorf("could not parse coins %q in %s event: %w", attributes[sdk.AttributeKeyAmount], eventType, err)
 	}
 
-	return accountBalanceOps(BurnOpType, amount, true, burner, status, index)
+	return amount, nil
+}
+
+func withValidatorMetadata(ops []*types.Operation, validator string) []*types.Operation {
+	for _, op := range ops {
+		op.Metadata = map[string]interface{}{
+			validatorAddressMetadataKey: validator,
+		}
+	}
+
+	return ops
 }
 
 // TxToOperations returns rosetta operations from a transaction
-func TxToOperations(tx authsigning.Tx, events sdk.StringEvents, logs sdk.ABCIMessageLogs, feeStatus *string, opStatus *string) []*types.Operation {
+func TxToOperations(registry *CurrencyRegistry, tx authsigning.Tx, events sdk.StringEvents, logs sdk.ABCIMessageLogs, feeStatus *string, opStatus *string) ([]*types.Operation, error) {
 
 	if txWithExtensions, ok := tx.(authante.HasExtensionOptionsTx); ok {
 		if opts := txWithExtensions.GetExtensionOptions(); len(opts) > 0 {
 			if opts[0].GetTypeUrl() == "/ethermint.evm.v1.ExtensionOptionsEthereumTx" {
-				return ethereumTxToOperations(events)
+				return ethereumTxToOperations(registry, events)
 			}
 		}
 	}
 
-	return cosmosTxToOperations(tx, logs, feeStatus, opStatus)
+	return cosmosTxToOperations(registry, tx, logs, feeStatus, opStatus)
 }
 
-func cosmosTxToOperations(tx authsigning.Tx, logs sdk.ABCIMessageLogs, feeStatus *string, opStatus *string) []*types.Operation {
+func cosmosTxToOperations(registry *CurrencyRegistry, tx authsigning.Tx, logs sdk.ABCIMessageLogs, feeStatus *string, opStatus *string) ([]*types.Operation, error) {
 	operationIndex := int64(0)
 	operations := []*types.Operation{}
 
 	if !tx.GetFee().Empty() {
-		feeOps := FeeToOperations(tx.FeePayer(), tx.GetFee(), feeStatus, operationIndex)
+		// fees are deducted from the fee granter instead of the fee payer when a fee grant is used
+		feeAccount := tx.FeePayer()
+		if feeGranter := tx.FeeGranter(); feeGranter != nil {
+			feeAccount = feeGranter
+		}
+
+		feeOps := FeeToOperations(registry, feeAccount, tx.GetFee(), feeStatus, operationIndex)
 		operations = appendOperationsAndUpdateIndex(operations, feeOps, &operationIndex)
 	}
 
@@ -140,31 +275,45 @@ func cosmosTxToOperations(tx authsigning.Tx, logs sdk.ABCIMessageLogs, feeStatus
 			}
 		}
 
-		msgOps := MsgToOperations(msg, log, opStatus, operationIndex)
+		msgOps, err := MsgToOperations(registry, msg, log, opStatus, operationIndex)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", msgIndex, err)
+		}
 		operations = appendOperationsAndUpdateIndex(operations, msgOps, &operationIndex)
 	}
 
-	return operations
+	return operations, nil
 }
 
-func ethereumTxToOperations(events sdk.StringEvents) []*types.Operation {
+// ethereumTxToOperations returns the operations of the bank events of an ethereum transaction,
+// followed by the transfers of registered erc20 contracts from its evm logs
+func ethereumTxToOperations(registry *CurrencyRegistry, events sdk.StringEvents) ([]*types.Operation, error) {
 	eventOpStatus := SuccessStatus
-	return EventsToOperations(events, &eventOpStatus, 0)
+
+	operations, err := EventsToOperations(registry, events, &eventOpStatus, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	erc20Ops, err := erc20EventsToOperations(registry, events, &eventOpStatus, int64(len(operations)))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(operations, erc20Ops...), nil
 }
 
 // FeeToOperations returns rosetta operations from a transaction fee
-func FeeToOperations(feePayer sdk.AccAddress, amount sdk.Coins, status *string, index int64) []*types.Operation {
+func FeeToOperations(registry *CurrencyRegistry, feePayer sdk.AccAddress, amount sdk.Coins, status *string, index int64) []*types.Operation {
 	sender := newAccountID(feePayer.String())
 	recipient := newAccountID(feeCollectorAddress.String())
 
-	return balanceTrackingOps(FeeOpType, sender, amount, recipient, status, index)
+	return balanceTrackingOps(registry, FeeOpType, sender, amount, recipient, status, index)
 }
 
 // MsgToOperations returns rosetta operations for a cosmos sdk or kava message
-func MsgToOperations(msg sdk.Msg, log sdk.ABCIMessageLog, status *string, index int64) []*types.Operation {
-	ops := getOpsFromMsg(msg, log, status, index)
-
-	return ops
+func MsgToOperations(registry *CurrencyRegistry, msg sdk.Msg, log sdk.ABCIMessageLog, status *string, index int64) ([]*types.Operation, error) {
+	return getOpsFromMsg(registry, msg, log, status, index)
 }
 
 func appendOperationsAndUpdateIndex(
@@ -188,7 +337,17 @@ func newAccountID(addr string) *types.AccountIdentifier {
 	}
 }
 
+func newSubAccountID(addr string, subAccount string) *types.AccountIdentifier {
+	return &types.AccountIdentifier{
+		Address: addr,
+		SubAccount: &types.SubAccountIdentifier{
+			Address: subAccount,
+		},
+	}
+}
+
 func balanceTrackingOps(
+	registry *CurrencyRegistry,
 	opType string,
 	sender *types.AccountIdentifier,
 	amount sdk.Coins,
@@ -199,7 +358,7 @@ func balanceTrackingOps(
 	operations := []*types.Operation{}
 
 	for _, coin := range amount {
-		currency, ok := Currencies[coin.Denom]
+		currency, ok := registry.Currency(coin.Denom)
 		if !ok {
 			continue
 		}
@@ -234,6 +393,7 @@ func balanceTrackingOps(
 }
 
 func accountBalanceOps(
+	registry *CurrencyRegistry,
 	opType string,
 	amount sdk.Coins,
 	negative bool,
@@ -244,7 +404,7 @@ func accountBalanceOps(
 	operations := []*types.Operation{}
 
 	for _, coin := range amount {
-		currency, ok := Currencies[coin.Denom]
+		currency, ok := registry.Currency(coin.Denom)
 		if !ok {
 			continue
 		}
@@ -271,74 +431,149 @@ func accountBalanceOps(
 	return operations
 }
 
-func getOpsFromMsg(msg sdk.Msg, log sdk.ABCIMessageLog, status *string, index int64) []*types.Operation {
+func getOpsFromMsg(registry *CurrencyRegistry, msg sdk.Msg, log sdk.ABCIMessageLog, status *string, index int64) ([]*types.Operation, error) {
 	var ops []*types.Operation
 
 	if m, ok := msg.(*banktypes.MsgMultiSend); ok {
-		transferOps := msgMultiSendToTransferOperations(m, status, index)
+		transferOps := msgMultiSendToTransferOperations(registry, m, status, index)
 		ops = appendOperationsAndUpdateIndex(ops, transferOps, &index)
-		return ops
+		return ops, nil
+	}
+
+	// conversions are parsed from the conversion event instead of the transfers to and from the evmutil module
+	switch m := msg.(type) {
+	case *evmutiltypes.MsgConvertCoinToERC20:
+		return msgConvertCoinToERC20ToOperations(registry, m, log, status, index), nil
+	case *evmutiltypes.MsgConvertERC20ToCoin:
+		return msgConvertERC20ToCoinToOperations(registry, m, log, status, index)
 	}
 
 	for _, ev := range log.Events {
-		if ev.Type == banktypes.EventTypeTransfer {
-			events := unflattenEvents(ev, banktypes.EventTypeTransfer, 3)
-			transferOps := EventsToOperations(events, status, index)
-			ops = appendOperationsAndUpdateIndex(ops, transferOps, &index)
+		var events sdk.StringEvents
+		var err error
+
+		switch ev.Type {
+		case banktypes.EventTypeTransfer:
+			events, err = unflattenEvents(ev, banktypes.EventTypeTransfer, 3)
+		case banktypes.EventTypeCoinMint, banktypes.EventTypeCoinBurn:
+			events, err = unflattenEvents(ev, ev.Type, 2)
+		case stakingtypes.EventTypeCreateValidator,
+			stakingtypes.EventTypeDelegate,
+			stakingtypes.EventTypeUnbond,
+			stakingtypes.EventTypeCancelUnbondingDelegation:
+			// staking events do not have a fixed number of attributes across modules
+			if len(ev.Attributes) > 0 {
+				events = splitEvents(ev, ev.Type, ev.Attributes[0].Key)
+			}
+		case stakingtypes.EventTypeRedelegate:
+			// redelegate events do not include the delegator; parse message contents instead
+			if m, ok := msg.(*stakingtypes.MsgBeginRedelegate); ok {
+				redelegateOps := msgBeginRedelegateToOperations(registry, m, status, index)
+				ops = appendOperationsAndUpdateIndex(ops, redelegateOps, &index)
+			}
 		}
-
-		if ev.Type == banktypes.EventTypeCoinMint {
-			events := unflattenEvents(ev, banktypes.EventTypeCoinMint, 2)
-			mintOps := EventsToOperations(events, status, index)
-			ops = appendOperationsAndUpdateIndex(ops, mintOps, &index)
+		if err != nil {
+			return nil, err
 		}
 
-		if ev.Type == banktypes.EventTypeCoinBurn {
-			events := unflattenEvents(ev, banktypes.EventTypeCoinBurn, 2)
-			burnOps := EventsToOperations(events, status, index)
-			ops = appendOperationsAndUpdateIndex(ops, burnOps, &index)
+		eventOps, err := EventsToOperations(registry, events, status, index)
+		if err != nil {
+			return nil, err
 		}
+		ops = appendOperationsAndUpdateIndex(ops, eventOps, &index)
+	}
+
+	if m, ok := msg.(*ibctransfertypes.MsgTransfer); ok {
+		markIBCTransferOperations(ops, m)
 	}
 
 	// Gives contstruction support for msg send -- required for proper construction?
 	if *status != SuccessStatus {
-		switch m := msg.(type) {
-		case *banktypes.MsgSend:
-			transferOps := msgSendToTransferOperations(m, status, index)
-			ops = appendOperationsAndUpdateIndex(ops, transferOps, &index)
+		failedOps := failedMsgToOperations(registry, msg, status, index)
+		ops = appendOperationsAndUpdateIndex(ops, failedOps, &index)
+	}
+
+	if m, ok := msg.(*authz.MsgExec); ok {
+		markAuthzExecOperations(ops, m)
+	}
+	return ops, nil
+}
+
+// failedMsgToOperations returns operations from the contents of messages that do not emit
+// events when they fail, including the messages executed by an authz exec
+func failedMsgToOperations(registry *CurrencyRegistry, msg sdk.Msg, status *string, index int64) []*types.Operation {
+	switch m := msg.(type) {
+	case *banktypes.MsgSend:
+		return msgSendToTransferOperations(registry, m, status, index)
+	case *ibctransfertypes.MsgTransfer:
+		return msgTransferToOperations(registry, m, status, index)
+	case *authz.MsgExec:
+		ops := []*types.Operation{}
+
+		msgs, err := m.GetMessages()
+		if err != nil {
+			return ops
+		}
+
+		for _, execMsg := range msgs {
+			execOps := failedMsgToOperations(registry, execMsg, status, index)
+			ops = appendOperationsAndUpdateIndex(ops, execOps, &index)
 		}
+
+		return ops
 	}
-	return ops
+
+	return []*types.Operation{}
 }
 
-func msgSendToTransferOperations(msg *banktypes.MsgSend, status *string, index int64) []*types.Operation {
+func msgSendToTransferOperations(registry *CurrencyRegistry, msg *banktypes.MsgSend, status *string, index int64) []*types.Operation {
 	sender := newAccountID(msg.FromAddress)
 	recipient := newAccountID(msg.ToAddress)
 	amount := msg.Amount
 
-	return balanceTrackingOps(TransferOpType, sender, amount, recipient, status, index)
+	return balanceTrackingOps(registry, TransferOpType, sender, amount, recipient, status, index)
+}
+
+func msgBeginRedelegateToOperations(registry *CurrencyRegistry, msg *stakingtypes.MsgBeginRedelegate, status *string, index int64) []*types.Operation {
+	delegated := newSubAccountID(msg.DelegatorAddress, AccLiquidDelegated)
+	amount := sdk.NewCoins(msg.Amount)
+
+	ops := balanceTrackingOps(registry, RedelegateOpType, delegated, amount, delegated, status, index)
+	for i, op := range ops {
+		// balance tracking ops alternate between sender and recipient
+		validator := msg.ValidatorSrcAddress
+		if i%2 == 1 {
+			validator = msg.ValidatorDstAddress
+		}
+
+		op.Metadata = map[string]interface{}{
+			validatorAddressMetadataKey: validator,
+		}
+	}
+
+	return ops
 }
 
 // we do not properly parse transfer and spent/receive events for multisends yet; parse message contents instead
-func msgMultiSendToTransferOperations(msg *banktypes.MsgMultiSend, status *string, index int64) []*types.Operation {
+func msgMultiSendToTransferOperations(registry *CurrencyRegistry, msg *banktypes.MsgMultiSend, status *string, index int64) []*types.Operation {
 	ops := []*types.Operation{}
 
 	for _, input := range msg.Inputs {
 		sender := newAccountID(input.Address)
-		transferOps := accountBalanceOps(TransferOpType, input.Coins, true, sender, status, index)
+		transferOps := accountBalanceOps(registry, TransferOpType, input.Coins, true, sender, status, index)
 		ops = appendOperationsAndUpdateIndex(ops, transferOps, &index)
 	}
 
 	for _, output := range msg.Outputs {
 		recipient := newAccountID(output.Address)
-		transferOps := accountBalanceOps(TransferOpType, output.Coins, false, recipient, status, index)
+		transferOps := accountBalanceOps(registry, TransferOpType, output.Coins, false, recipient, status, index)
 		ops = appendOperationsAndUpdateIndex(ops, transferOps, &index)
 	}
 
 	return ops
 }
 
-func unflattenEvents(ev sdk.StringEvent, eventType string, numAttributes int) (events sdk.StringEvents) {
+func unflattenEvents(ev sdk.StringEvent, eventType string, numAttributes int) (events sdk.StringEvents, err error) {
 	// drop authz_msg_index additions
 	attributes := []sdk.Attribute{}
 	for _, attribute := range ev.Attributes {
@@ -351,7 +586,7 @@ func unflattenEvents(ev sdk.StringEvent, eventType string, numAttributes int) (e
 	}
 
 	if len(attributes)%numAttributes != 0 {
-		panic(fmt.Sprintf("unexpected number of attributes in transfer event %s", attributes))
+		return nil, fmt.Errorf("unexpected number of attributes in %s event %s", eventType, attributes)
 	}
 
 	numberOfEvents := len(attributes) / numAttributes
@@ -360,21 +595,33 @@ func unflattenEvents(ev sdk.StringEvent, eventType string, numAttributes int) (e
 		event := sdk.NewEvent(eventType, attributes[startingIndex:startingIndex+numAttributes]...)
 		events = append(events, sdk.StringifyEvent(abci.Event(event)))
 	}
-	return events
+	return events, nil
 }
 
-func mustAccAddressFromBech32(addr string) sdk.AccAddress {
-	acc, err := sdk.AccAddressFromBech32(addr)
-	if err != nil {
-		panic(err)
+// splitEvents splits a flattened log event into events that each start with firstKey
+func splitEvents(ev sdk.StringEvent, eventType string, firstKey string) (events sdk.StringEvents) {
+	var attributes []sdk.Attribute
+	flush := func() {
+		if len(attributes) > 0 {
+			event := sdk.NewEvent(eventType, attributes...)
+			events = append(events, sdk.StringifyEvent(abci.Event(event)))
+		}
+		attributes = nil
 	}
-	return acc
-}
 
-func mustParseCoinsNormalized(coinsStr string) sdk.Coins {
-	coins, err := sdk.ParseCoinsNormalized(coinsStr)
-	if err != nil {
-		panic(err)
+	for _, attribute := range ev.Attributes {
+		// remove authz_msg_index attributes
+		if attribute.Key == "authz_msg_index" {
+			continue
+		}
+
+		if attribute.Key == firstKey {
+			flush()
+		}
+
+		attributes = append(attributes, attribute)
 	}
-	return coins
+	flush()
+
+	return events
 }
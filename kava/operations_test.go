@@ -37,6 +37,7 @@ import (
 )
 
 var (
+	testRegistry         = NewCurrencyRegistry(Currencies)
 	stakingModuleAddress = sdk.AccAddress(crypto.AddressHash([]byte(stakingtypes.BondedPoolName)))
 	testAddresses        = []string{
 		"kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea",
@@ -176,10 +177,11 @@ func runAndAssertOperationInvariants(
 
 				// assert seen currencies are supported and correct
 				symbol := op.Amount.Currency.Symbol
-				denom, ok := Denoms[symbol]
+				denom, ok := testRegistry.Denom(op.Amount.Currency)
 				assert.Truef(t, ok, "currency %s not supported", symbol)
 				if ok {
-					assert.Equal(t, Currencies[denom], op.Amount.Currency)
+					currency, _ := testRegistry.Currency(denom)
+					assert.Equal(t, currency, op.Amount.Currency)
 				}
 			}
 		})
@@ -197,7 +199,7 @@ func assertTrackedBalance(
 	t.Run(name, func(t *testing.T) {
 		supportedCurrenciesFound := false
 		for _, coin := range amount {
-			_, ok := Currencies[coin.Denom]
+			_, ok := testRegistry.Currency(coin.Denom)
 			if ok {
 				supportedCurrenciesFound = true
 			}
@@ -242,11 +244,10 @@ func assertTrackedBalance(
 					continue
 				}
 
-				symbol := op.Amount.Currency.Symbol
 				value, err := types.AmountValue(op.Amount)
 				require.NoError(t, err)
 
-				denom, ok := Denoms[symbol]
+				denom, ok := testRegistry.Denom(op.Amount.Currency)
 				require.True(t, ok)
 
 				// sender operations are negative
@@ -264,11 +265,10 @@ func assertTrackedBalance(
 					continue
 				}
 
-				symbol := op.Amount.Currency.Symbol
 				value, err := types.AmountValue(op.Amount)
 				require.NoError(t, err)
 
-				denom, ok := Denoms[symbol]
+				denom, ok := testRegistry.Denom(op.Amount.Currency)
 				require.True(t, ok)
 
 				// recipient operations are negative
@@ -369,7 +369,8 @@ func TestEventsToOperations(t *testing.T) {
 	index := int64(0)
 	events := sdk.StringEvents{testEvent1, testEvent2}
 	status := SuccessStatus
-	ops := EventsToOperations(events, &status, index)
+	ops, err := EventsToOperations(testRegistry, events, &status, index)
+	require.NoError(t, err)
 
 	assert.Greater(t, len(ops), 0)
 	for opIndex, op := range ops {
@@ -379,7 +380,8 @@ func TestEventsToOperations(t *testing.T) {
 
 	index = int64(10)
 	events = sdk.StringEvents{testEvent1, testEvent2}
-	ops = EventsToOperations(events, &status, index)
+	ops, err = EventsToOperations(testRegistry, events, &status, index)
+	require.NoError(t, err)
 
 	assert.Greater(t, len(ops), 0)
 	for opIndex, op := range ops {
@@ -425,13 +427,64 @@ func TestEventToOperations(t *testing.T) {
 				Address: testAddresses[1],
 			},
 		},
+		{
+			name: "staking unbond",
+			createFn: func(coins sdk.Coins) sdk.StringEvent {
+				return sdk.StringEvent{
+					Type: stakingtypes.EventTypeUnbond,
+					Attributes: []sdk.Attribute{
+						{
+							Key:   stakingtypes.AttributeKeyValidator,
+							Value: testValidatorAddress(t, 1),
+						},
+						{
+							Key:   sdk.AttributeKeyAmount,
+							Value: coins.String(),
+						},
+						{
+							Key:   stakingtypes.AttributeKeyDelegator,
+							Value: testAddresses[0],
+						},
+					},
+				}
+			},
+			opType:    UndelegateOpType,
+			sender:    newSubAccountID(testAddresses[0], AccLiquidDelegated),
+			recipient: newSubAccountID(testAddresses[0], AccLiquidUnbonding),
+		},
+		{
+			name: "staking cancel unbonding",
+			createFn: func(coins sdk.Coins) sdk.StringEvent {
+				return sdk.StringEvent{
+					Type: stakingtypes.EventTypeCancelUnbondingDelegation,
+					Attributes: []sdk.Attribute{
+						{
+							Key:   sdk.AttributeKeyAmount,
+							Value: coins.String(),
+						},
+						{
+							Key:   stakingtypes.AttributeKeyValidator,
+							Value: testValidatorAddress(t, 1),
+						},
+						{
+							Key:   stakingtypes.AttributeKeyDelegator,
+							Value: testAddresses[0],
+						},
+					},
+				}
+			},
+			opType:    CancelUnbondingOpType,
+			sender:    newSubAccountID(testAddresses[0], AccLiquidUnbonding),
+			recipient: newSubAccountID(testAddresses[0], AccLiquidDelegated),
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			runAndAssertOperationInvariants(t, tc.opType, func(otc *operationTestCase) []*types.Operation {
 				event := tc.createFn(otc.coins)
-				ops := EventToOperations(event, &otc.status, otc.index)
+				ops, err := EventToOperations(testRegistry, event, &otc.status, otc.index)
+				require.NoError(t, err)
 
 				assertTrackedBalance(t, otc.name, ops, tc.sender, otc.coins, tc.recipient)
 
@@ -441,6 +494,238 @@ func TestEventToOperations(t *testing.T) {
 	}
 }
 
+func TestEventToOperations_Staking(t *testing.T) {
+	validator := testValidatorAddress(t, 1)
+	amount := sdk.NewCoins(sdk.NewInt64Coin("ukava", 1e6))
+	status := SuccessStatus
+
+	tests := []struct {
+		name       string
+		event      sdk.StringEvent
+		opType     string
+		account    *types.AccountIdentifier
+		value      string
+		noDelegate bool
+	}{
+		{
+			name: "delegate",
+			event: sdk.StringEvent{
+				Type: stakingtypes.EventTypeDelegate,
+				Attributes: []sdk.Attribute{
+					{Key: stakingtypes.AttributeKeyValidator, Value: validator},
+					{Key: stakingtypes.AttributeKeyDelegator, Value: testAddresses[0]},
+					{Key: sdk.AttributeKeyAmount, Value: amount.String()},
+					{Key: stakingtypes.AttributeKeyNewShares, Value: "1000000.000000000000000000"},
+				},
+			},
+			opType:  DelegateOpType,
+			account: newSubAccountID(testAddresses[0], AccLiquidDelegated),
+			value:   "1000000",
+		},
+		{
+			name: "create validator",
+			event: sdk.StringEvent{
+				Type: stakingtypes.EventTypeCreateValidator,
+				Attributes: []sdk.Attribute{
+					{Key: stakingtypes.AttributeKeyValidator, Value: validator},
+					{Key: sdk.AttributeKeyAmount, Value: amount.String()},
+				},
+			},
+			opType:  DelegateOpType,
+			account: newSubAccountID(testAddresses[1], AccLiquidDelegated),
+			value:   "1000000",
+		},
+		{
+			name: "complete unbonding",
+			event: sdk.StringEvent{
+				Type: stakingtypes.EventTypeCompleteUnbonding,
+				Attributes: []sdk.Attribute{
+					{Key: sdk.AttributeKeyAmount, Value: amount.String()},
+					{Key: stakingtypes.AttributeKeyValidator, Value: validator},
+					{Key: stakingtypes.AttributeKeyDelegator, Value: testAddresses[0]},
+				},
+			},
+			opType:  CompleteUnbondingOpType,
+			account: newSubAccountID(testAddresses[0], AccLiquidUnbonding),
+			value:   "-1000000",
+		},
+		{
+			name: "delegate without delegator",
+			event: sdk.StringEvent{
+				Type: stakingtypes.EventTypeDelegate,
+				Attributes: []sdk.Attribute{
+					{Key: stakingtypes.AttributeKeyValidator, Value: validator},
+					{Key: sdk.AttributeKeyAmount, Value: amount.String()},
+					{Key: stakingtypes.AttributeKeyNewShares, Value: "1000000.000000000000000000"},
+				},
+			},
+			noDelegate: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ops, err := EventToOperations(testRegistry, tc.event, &status, 5)
+			require.NoError(t, err)
+
+			if tc.noDelegate {
+				assert.Len(t, ops, 0)
+				return
+			}
+
+			require.Len(t, ops, 1)
+			assert.Equal(t, int64(5), ops[0].OperationIdentifier.Index)
+			assert.Equal(t, tc.opType, ops[0].Type)
+			assert.Equal(t, tc.account, ops[0].Account)
+			assert.Equal(t, tc.value, ops[0].Amount.Value)
+			assert.Equal(t, Currencies["ukava"], ops[0].Amount.Currency)
+			assert.Equal(t, validator, ops[0].Metadata[validatorAddressMetadataKey])
+		})
+	}
+}
+
+func TestEventToOperations_InvalidAttributes(t *testing.T) {
+	status := SuccessStatus
+
+	events := []sdk.StringEvent{
+		{
+			Type: banktypes.EventTypeTransfer,
+			Attributes: []sdk.Attribute{
+				{Key: banktypes.AttributeKeyRecipient, Value: testAddresses[0]},
+				{Key: banktypes.AttributeKeySender, Value: testAddresses[1]},
+				{Key: sdk.AttributeKeyAmount, Value: "not coins"},
+			},
+		},
+		{
+			Type: stakingtypes.EventTypeCreateValidator,
+			Attributes: []sdk.Attribute{
+				{Key: stakingtypes.AttributeKeyValidator, Value: "invalid"},
+				{Key: sdk.AttributeKeyAmount, Value: "1000000ukava"},
+			},
+		},
+	}
+
+	for _, event := range events {
+		t.Run(event.Type, func(t *testing.T) {
+			ops, err := EventToOperations(testRegistry, event, &status, 0)
+			assert.Nil(t, ops)
+			assert.ErrorContains(t, err, event.Type+" event")
+
+			ops, err = EventsToOperations(testRegistry, sdk.StringEvents{event}, &status, 0)
+			assert.Nil(t, ops)
+			assert.Error(t, err)
+		})
+	}
+
+	// flattened log events must contain a multiple of the event attributes
+	log := sdk.ABCIMessageLog{
+		Events: sdk.StringEvents{
+			{
+				Type: banktypes.EventTypeTransfer,
+				Attributes: []sdk.Attribute{
+					{Key: banktypes.AttributeKeyRecipient, Value: testAddresses[0]},
+					{Key: banktypes.AttributeKeySender, Value: testAddresses[1]},
+				},
+			},
+		},
+	}
+
+	ops, err := MsgToOperations(testRegistry, &banktypes.MsgSend{}, log, &status, 0)
+	assert.Nil(t, ops)
+	assert.ErrorContains(t, err, "unexpected number of attributes in transfer event")
+}
+
+func TestMsgToOperations_Staking(t *testing.T) {
+	delegator := testAddresses[0]
+	srcValidator := testValidatorAddress(t, 1)
+	dstValidator := testValidatorAddress(t, 2)
+	status := SuccessStatus
+
+	t.Run("flattened delegate events", func(t *testing.T) {
+		log := sdk.ABCIMessageLog{
+			Events: sdk.StringEvents{
+				{
+					Type: stakingtypes.EventTypeDelegate,
+					Attributes: []sdk.Attribute{
+						{Key: stakingtypes.AttributeKeyValidator, Value: srcValidator},
+						{Key: stakingtypes.AttributeKeyDelegator, Value: delegator},
+						{Key: sdk.AttributeKeyAmount, Value: "100ukava"},
+						{Key: stakingtypes.AttributeKeyNewShares, Value: "100.000000000000000000"},
+						{Key: "authz_msg_index", Value: "0"},
+						// events emitted outside of the staking module may omit the delegator
+						{Key: stakingtypes.AttributeKeyValidator, Value: dstValidator},
+						{Key: sdk.AttributeKeyAmount, Value: "200ukava"},
+						{Key: stakingtypes.AttributeKeyNewShares, Value: "200.000000000000000000"},
+						{Key: stakingtypes.AttributeKeyValidator, Value: dstValidator},
+						{Key: stakingtypes.AttributeKeyDelegator, Value: delegator},
+						{Key: sdk.AttributeKeyAmount, Value: "300ukava"},
+						{Key: stakingtypes.AttributeKeyNewShares, Value: "300.000000000000000000"},
+					},
+				},
+			},
+		}
+
+		ops, err := MsgToOperations(testRegistry, &stakingtypes.MsgDelegate{}, log, &status, 0)
+		require.NoError(t, err)
+
+		require.Len(t, ops, 2)
+		for i, op := range ops {
+			assert.Equal(t, int64(i), op.OperationIdentifier.Index)
+			assert.Equal(t, DelegateOpType, op.Type)
+			assert.Equal(t, newSubAccountID(delegator, AccLiquidDelegated), op.Account)
+		}
+		assert.Equal(t, "100", ops[0].Amount.Value)
+		assert.Equal(t, srcValidator, ops[0].Metadata[validatorAddressMetadataKey])
+		assert.Equal(t, "300", ops[1].Amount.Value)
+		assert.Equal(t, dstValidator, ops[1].Metadata[validatorAddressMetadataKey])
+	})
+
+	t.Run("redelegate", func(t *testing.T) {
+		msg := &stakingtypes.MsgBeginRedelegate{
+			DelegatorAddress:    delegator,
+			ValidatorSrcAddress: srcValidator,
+			ValidatorDstAddress: dstValidator,
+			Amount:              sdk.NewInt64Coin("ukava", 100),
+		}
+		log := sdk.ABCIMessageLog{
+			Events: sdk.StringEvents{
+				{
+					Type: stakingtypes.EventTypeRedelegate,
+					Attributes: []sdk.Attribute{
+						{Key: stakingtypes.AttributeKeySrcValidator, Value: srcValidator},
+						{Key: stakingtypes.AttributeKeyDstValidator, Value: dstValidator},
+						{Key: sdk.AttributeKeyAmount, Value: "100ukava"},
+						{Key: stakingtypes.AttributeKeyCompletionTime, Value: "2024-01-01T00:00:00Z"},
+					},
+				},
+			},
+		}
+
+		ops, err := MsgToOperations(testRegistry, msg, log, &status, 3)
+		require.NoError(t, err)
+
+		require.Len(t, ops, 2)
+		delegated := newSubAccountID(delegator, AccLiquidDelegated)
+
+		assert.Equal(t, int64(3), ops[0].OperationIdentifier.Index)
+		assert.Equal(t, RedelegateOpType, ops[0].Type)
+		assert.Equal(t, delegated, ops[0].Account)
+		assert.Equal(t, "-100", ops[0].Amount.Value)
+		assert.Equal(t, srcValidator, ops[0].Metadata[validatorAddressMetadataKey])
+
+		assert.Equal(t, int64(4), ops[1].OperationIdentifier.Index)
+		assert.Equal(t, []*types.OperationIdentifier{{Index: 3}}, ops[1].RelatedOperations)
+		assert.Equal(t, RedelegateOpType, ops[1].Type)
+		assert.Equal(t, delegated, ops[1].Account)
+		assert.Equal(t, "100", ops[1].Amount.Value)
+		assert.Equal(t, dstValidator, ops[1].Metadata[validatorAddressMetadataKey])
+	})
+}
+
+func testValidatorAddress(t *testing.T, i int) string {
+	return sdk.ValAddress(getAccAddr(t, testAddresses[i])).String()
+}
+
 func TestTxToOperations(t *testing.T) {
 	msg1 := banktypes.MsgSend{
 		FromAddress: getAccAddr(t, testAddresses[0]).String(),
@@ -470,21 +755,24 @@ func TestTxToOperations(t *testing.T) {
 		}
 
 		// all ops succesful and indexed correctly
-		ops := TxToOperations(&tx, events, logs, &success, &success)
+		ops, err := TxToOperations(testRegistry, &tx, events, logs, &success, &success)
+		require.NoError(t, err)
 		for index, op := range ops {
 			assert.Equal(t, int64(index), op.OperationIdentifier.Index)
 			assert.Equal(t, success, *op.Status)
 		}
 
 		// all ops failed and indexed correctly
-		ops = TxToOperations(&tx, events, logs, &failure, &failure)
+		ops, err = TxToOperations(testRegistry, &tx, events, logs, &failure, &failure)
+		require.NoError(t, err)
 		for index, op := range ops {
 			assert.Equal(t, int64(index), op.OperationIdentifier.Index)
 			assert.Equal(t, failure, *op.Status)
 		}
 
 		// there are no fee operations
-		ops = TxToOperations(&tx, events, logs, &success, &success)
+		ops, err = TxToOperations(testRegistry, &tx, events, logs, &success, &success)
+		require.NoError(t, err)
 		for _, op := range ops {
 			assert.NotEqual(t, FeeOpType, op.Type)
 		}
@@ -497,14 +785,16 @@ func TestTxToOperations(t *testing.T) {
 		}
 
 		// all ops succesful and indexed correctly
-		ops := TxToOperations(&tx, events, logs, &success, &success)
+		ops, err := TxToOperations(testRegistry, &tx, events, logs, &success, &success)
+		require.NoError(t, err)
 		for index, op := range ops {
 			assert.Equal(t, int64(index), op.OperationIdentifier.Index)
 			assert.Equal(t, success, *op.Status)
 		}
 
 		// all ops failed and indexed correctly
-		ops = TxToOperations(&tx, events, logs, &failure, &failure)
+		ops, err = TxToOperations(testRegistry, &tx, events, logs, &failure, &failure)
+		require.NoError(t, err)
 		for index, op := range ops {
 			assert.Equal(t, int64(index), op.OperationIdentifier.Index)
 			assert.Equal(t, failure, *op.Status)
@@ -512,7 +802,8 @@ func TestTxToOperations(t *testing.T) {
 
 		// there are fee operations
 		feeOpTypeFound := false
-		ops = TxToOperations(&tx, events, logs, &success, &success)
+		ops, err = TxToOperations(testRegistry, &tx, events, logs, &success, &success)
+		require.NoError(t, err)
 		for _, op := range ops {
 			if op.Type == FeeOpType {
 				feeOpTypeFound = true
@@ -534,7 +825,7 @@ func TestFeeToOperations(t *testing.T) {
 
 	// assert balance tracking and operation invarians are correct
 	runAndAssertOperationInvariants(t, FeeOpType, func(tc *operationTestCase) []*types.Operation {
-		ops := FeeToOperations(payerAddr, tc.coins, &tc.status, tc.index)
+		ops := FeeToOperations(testRegistry, payerAddr, tc.coins, &tc.status, tc.index)
 
 		sender := &types.AccountIdentifier{
 			Address: payer,
@@ -549,6 +840,35 @@ func TestFeeToOperations(t *testing.T) {
 	})
 }
 
+func TestTxToOperations_FeeGranter(t *testing.T) {
+	payer := getAccAddr(t, testAddresses[0])
+	granter := getAccAddr(t, testAddresses[1])
+	feeCollector := newAccountID("kava17xpfvakm2amg962yls6f84z3kell8c5lvvhaa6")
+	status := SuccessStatus
+
+	txBuilder := app.MakeEncodingConfig().TxConfig.NewTxBuilder()
+	require.NoError(t, txBuilder.SetMsgs(banktypes.NewMsgSend(payer, granter, sdk.NewCoins(sdk.NewInt64Coin("ukava", 1)))))
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("ukava", 5000)))
+
+	ops, err := TxToOperations(testRegistry, txBuilder.GetTx(), sdk.StringEvents{}, sdk.ABCIMessageLogs{}, &status, &status)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	assert.Equal(t, newAccountID(payer.String()), ops[0].Account)
+	assert.Equal(t, feeCollector, ops[1].Account)
+
+	// fee grants deduct fees from the granter instead of the fee payer
+	txBuilder.SetFeeGranter(granter)
+
+	ops, err = TxToOperations(testRegistry, txBuilder.GetTx(), sdk.StringEvents{}, sdk.ABCIMessageLogs{}, &status, &status)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	assert.Equal(t, FeeOpType, ops[0].Type)
+	assert.Equal(t, newAccountID(granter.String()), ops[0].Account)
+	assert.Equal(t, "-5000", ops[0].Amount.Value)
+	assert.Equal(t, feeCollector, ops[1].Account)
+	assert.Equal(t, "5000", ops[1].Amount.Value)
+}
+
 func TestMsgToOperations_BalanceTracking(t *testing.T) {
 	t.Skip()
 
@@ -662,7 +982,8 @@ func TestMsgToOperations_BalanceTracking(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			runAndAssertOperationInvariants(t, TransferOpType,
 				func(otc *operationTestCase) []*types.Operation {
-					ops := MsgToOperations(tc.msg, tc.log, &otc.status, otc.index)
+					ops, err := MsgToOperations(testRegistry, tc.msg, tc.log, &otc.status, otc.index)
+					require.NoError(t, err)
 					senders, receivers := calculateSendersReceivers(tc.msg, tc.log)
 					coins := calculateCoins(tc.log)
 					assertTransferOpsBalanceTrack(t, otc.name, ops, senders, receivers, coins)
@@ -683,7 +1004,7 @@ func assertTransferOpsBalanceTrack(
 	t.Run(name, func(t *testing.T) {
 		supportedCurrenciesFound := false
 		for _, coin := range transferCoins {
-			_, ok := Currencies[coin.Denom]
+			_, ok := testRegistry.Currency(coin.Denom)
 			if ok {
 				supportedCurrenciesFound = true
 			}
@@ -738,14 +1059,13 @@ func assertTransferOpsBalanceTrack(
 					continue
 				}
 
-				symbol := op.Amount.Currency.Symbol
 				value, err := types.AmountValue(op.Amount)
 				if value.Sign() != -1 {
 					continue // exit if value is non-negative, as this is not a send
 				}
 				require.NoError(t, err)
 
-				denom, ok := Denoms[symbol]
+				denom, ok := testRegistry.Denom(op.Amount.Currency)
 				require.True(t, ok)
 
 				opCoins = opCoins.Add(sdk.NewCoin(denom, sdkmath.NewIntFromBigInt(value.Neg(value))))
@@ -768,14 +1088,13 @@ func assertTransferOpsBalanceTrack(
 				if !mustAccAddressFromBech32(op.Account.Address).Equals(rt.Account) {
 					continue
 				}
-				symbol := op.Amount.Currency.Symbol
 				value, err := types.AmountValue(op.Amount)
 				if value.Sign() != 1 {
 					continue
 				}
 				require.NoError(t, err)
 
-				denom, ok := Denoms[symbol]
+				denom, ok := testRegistry.Denom(op.Amount.Currency)
 				require.True(t, ok)
 
 				opCoins = opCoins.Add(sdk.NewCoin(denom, sdkmath.NewIntFromBigInt(value)))
@@ -917,7 +1236,10 @@ func calculateSendersReceivers(msg sdk.Msg, log sdk.ABCIMessageLog) (senders, re
 
 	for _, ev := range log.Events {
 		if ev.Type == banktypes.EventTypeTransfer {
-			unflattenedTransferEvents := unflattenEvents(ev, banktypes.EventTypeTransfer, numTransferAttributes)
+			unflattenedTransferEvents, err := unflattenEvents(ev, banktypes.EventTypeTransfer, numTransferAttributes)
+			if err != nil {
+				panic(err)
+			}
 			for _, event := range unflattenedTransferEvents {
 				var recipient sdk.AccAddress
 				var amount sdk.Coins
@@ -1021,10 +1343,26 @@ func calcCreateValdiatorSendersReceivers(senders, receivers []accountBalance, lo
 func filterCoins(amount sdk.Coins) sdk.Coins {
 	filtered := sdk.NewCoins()
 	for _, c := range amount {
-		_, ok := Currencies[c.Denom]
+		_, ok := testRegistry.Currency(c.Denom)
 		if ok {
 			filtered = filtered.Add(c)
 		}
 	}
 	return filtered
 }
+
+func mustAccAddressFromBech32(addr string) sdk.AccAddress {
+	acc, err := sdk.AccAddressFromBech32(addr)
+	if err != nil {
+		panic(err)
+	}
+	return acc
+}
+
+func mustParseCoinsNormalized(coinsStr string) sdk.Coins {
+	coins, err := sdk.ParseCoinsNormalized(coinsStr)
+	if err != nil {
+		panic(err)
+	}
+	return coins
+}
@@ -16,7 +16,11 @@ package kava
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/cometbft/cometbft/libs/bytes"
 	tmrpcclient "github.com/cometbft/cometbft/rpc/client"
@@ -29,11 +33,22 @@ import (
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	evmtypes "github.com/evmos/ethermint/x/evm/types"
 	kava "github.com/kava-labs/kava/app"
 	"github.com/kava-labs/kava/app/params"
 )
 
+// erc20BalanceOfSelector is the function selector of the erc20 balanceOf(address) call
+var erc20BalanceOfSelector = []byte{0x70, 0xa0, 0x82, 0x31}
+
+// erc20BalanceGasCap is the gas limit of an erc20 balanceOf call
+const erc20BalanceGasCap = 1_000_000
+
 // HTTPClient extends the tendermint http client to enable finding blocks by hash
 type HTTPClient struct {
 	*tmhttp.HTTP
@@ -44,10 +59,17 @@ type HTTPClient struct {
 
 // NewHTTPClient returns a new HTTPClient with additional capabilities
 func NewHTTPClient(remote string) (*HTTPClient, error) {
+	return NewHTTPClientWithTimeout(remote, 0)
+}
+
+// NewHTTPClientWithTimeout returns a new HTTPClient with a maximum duration for each request.
+// A timeout of zero means no timeout.
+func NewHTTPClientWithTimeout(remote string, timeout time.Duration) (*HTTPClient, error) {
 	client, err := tmclient.DefaultHTTPClient(remote)
 	if err != nil {
 		return nil, err
 	}
+	client.Timeout = timeout
 
 	http, err := tmhttp.NewWithClient(remote, "/websocket", client)
 	if err != nil {
@@ -136,6 +158,80 @@ func (c *HTTPClient) Balance(ctx context.Context, addr sdk.AccAddress, height in
 	return totalBalances, nil
 }
 
+// DenomsMetadata returns the bank metadata of all denoms
+func (c *HTTPClient) DenomsMetadata(ctx context.Context, height int64) ([]banktypes.Metadata, error) {
+	path := "/cosmos.bank.v1beta1.Query/DenomsMetadata"
+	metadatas := []banktypes.Metadata{}
+
+	request := banktypes.QueryDenomsMetadataRequest{
+		Pagination: &query.PageRequest{Key: nil, Limit: query.DefaultLimit},
+	}
+
+	for {
+		bz, err := c.encodingConfig.Marshaler.Marshal(&request)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := c.abciQuery(ctx, path, bz, height)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp banktypes.QueryDenomsMetadataResponse
+		err = c.encodingConfig.Marshaler.Unmarshal(data, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		metadatas = append(metadatas, resp.Metadatas...)
+
+		if resp.Pagination == nil || resp.Pagination.NextKey == nil {
+			break
+		}
+		request.Pagination.Key = resp.Pagination.NextKey
+	}
+
+	return metadatas, nil
+}
+
+// DenomTraces returns the ibc transfer denom traces of all ibc vouchers
+func (c *HTTPClient) DenomTraces(ctx context.Context, height int64) (ibctransfertypes.Traces, error) {
+	path := "/ibc.applications.transfer.v1.Query/DenomTraces"
+	traces := ibctransfertypes.Traces{}
+
+	request := ibctransfertypes.QueryDenomTracesRequest{
+		Pagination: &query.PageRequest{Key: nil, Limit: query.DefaultLimit},
+	}
+
+	for {
+		bz, err := c.encodingConfig.Marshaler.Marshal(&request)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := c.abciQuery(ctx, path, bz, height)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp ibctransfertypes.QueryDenomTracesResponse
+		err = c.encodingConfig.Marshaler.Unmarshal(data, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		traces = append(traces, resp.DenomTraces...)
+
+		if resp.Pagination == nil || resp.Pagination.NextKey == nil {
+			break
+		}
+		request.Pagination.Key = resp.Pagination.NextKey
+	}
+
+	return traces, nil
+}
+
 // Delegations returns the delegations for an acc address
 func (c *HTTPClient) Delegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.DelegationResponses, error) {
 	path := "/cosmos.staking.v1beta1.Query/DelegatorDelegations"
@@ -212,6 +308,110 @@ func (c *HTTPClient) UnbondingDelegations(ctx context.Context, addr sdk.AccAddre
 	return unbondingDelegations, nil
 }
 
+// StakingValidators returns the staking validators with a bond status, or all validators when status is empty
+func (c *HTTPClient) StakingValidators(ctx context.Context, status string, height int64) (stakingtypes.Validators, error) {
+	path := "/cosmos.staking.v1beta1.Query/Validators"
+	validators := stakingtypes.Validators{}
+
+	request := stakingtypes.QueryValidatorsRequest{
+		Status:     status,
+		Pagination: &query.PageRequest{Key: nil, Limit: query.DefaultLimit},
+	}
+
+	for {
+		bz, err := c.encodingConfig.Marshaler.Marshal(&request)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := c.abciQuery(ctx, path, bz, height)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp stakingtypes.QueryValidatorsResponse
+		err = c.encodingConfig.Marshaler.Unmarshal(data, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		validators = append(validators, resp.Validators...)
+
+		if resp.Pagination == nil || resp.Pagination.NextKey == nil {
+			break
+		}
+		request.Pagination.Key = resp.Pagination.NextKey
+	}
+
+	return validators, nil
+}
+
+// DelegationRewards returns the outstanding staking rewards of a delegator for each validator
+func (c *HTTPClient) DelegationRewards(ctx context.Context, addr sdk.AccAddress, height int64) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	bz, err := c.encodingConfig.Marshaler.Marshal(&distrtypes.QueryDelegationTotalRewardsRequest{DelegatorAddress: addr.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/cosmos.distribution.v1beta1.Query/DelegationTotalRewards"
+
+	data, err := c.abciQuery(ctx, path, bz, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp distrtypes.QueryDelegationTotalRewardsResponse
+	err = c.encodingConfig.Marshaler.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ERC20Balance returns the erc20 balanceOf of a holder at a height, calling the contract through the
+// evm module without a transaction.  A call without return data, such as to a contract that is not
+// deployed at the height, returns a zero balance.
+func (c *HTTPClient) ERC20Balance(ctx context.Context, contract common.Address, holder common.Address, height int64) (*big.Int, error) {
+	data := hexutil.Bytes(append(append([]byte{}, erc20BalanceOfSelector...), common.LeftPadBytes(holder.Bytes(), 32)...))
+	args, err := json.Marshal(&evmtypes.TransactionArgs{To: &contract, Data: &data})
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := c.encodingConfig.Marshaler.Marshal(&evmtypes.EthCallRequest{Args: args, GasCap: erc20BalanceGasCap})
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/ethermint.evm.v1.Query/EthCall"
+
+	result, err := c.abciQuery(ctx, path, bz, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp evmtypes.MsgEthereumTxResponse
+	err = c.encodingConfig.Marshaler.Unmarshal(result, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Failed() {
+		return nil, fmt.Errorf("balanceOf call to contract %s failed: %s", contract, resp.VmError)
+	}
+
+	if len(resp.Ret) == 0 {
+		return big.NewInt(0), nil
+	}
+
+	if len(resp.Ret) != common.HashLength {
+		return nil, fmt.Errorf("balanceOf call to contract %s returned %d bytes", contract, len(resp.Ret))
+	}
+
+	return new(big.Int).SetBytes(resp.Ret), nil
+}
+
 // SimulateTx simulates a transaction and returns the response containing the gas used and result
 func (c *HTTPClient) SimulateTx(ctx context.Context, tx authsigning.Tx) (*sdk.SimulationResponse, error) {
 	bz, err := c.encodingConfig.TxConfig.TxEncoder()(tx)
@@ -219,18 +419,36 @@ func (c *HTTPClient) SimulateTx(ctx context.Context, tx authsigning.Tx) (*sdk.Si
 		return nil, err
 	}
 
-	data, err := c.abciQuery(ctx, "/app/simulate", bz, 0)
+	opts := tmrpcclient.ABCIQueryOptions{Height: 0, Prove: false}
+	result, err := c.ABCIQueryWithOptions(ctx, "/app/simulate", bz, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	if resp := result.Response; !resp.IsOK() {
+		return nil, &SimulationError{Codespace: resp.Codespace, Code: resp.Code, Log: resp.Log}
+	}
+
 	var simRes sdk.SimulationResponse
-	if err := c.encodingConfig.Marshaler.UnmarshalJSON(data, &simRes); err != nil {
+	if err := c.encodingConfig.Marshaler.UnmarshalJSON(result.Response.Value, &simRes); err != nil {
 		return nil, err
 	}
 	return &simRes, nil
 }
 
+// SimulationError is returned when a simulated transaction fails, with the abci error
+// the transaction would fail with if it were delivered
+type SimulationError struct {
+	Codespace string
+	Code      uint32
+	Log       string
+}
+
+// Error returns the abci error log
+func (e *SimulationError) Error() string {
+	return e.Log
+}
+
 func (c *HTTPClient) abciQuery(ctx context.Context, path string, data bytes.HexBytes, height int64) ([]byte, error) {
 	opts := tmrpcclient.ABCIQueryOptions{Height: height, Prove: false}
 	result, err := c.ABCIQueryWithOptions(ctx, path, data, opts)
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -41,7 +42,11 @@ import (
 	"github.com/cosmos/cosmos-sdk/types/query"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	evmtypes "github.com/evmos/ethermint/x/evm/types"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -603,6 +608,150 @@ func TestHTTPClient_UnbondingDelegations(t *testing.T) {
 	require.True(t, cmp.Equal(expectedUnbondingDelegations, unbondingDelegations))
 }
 
+func TestHTTPClient_StakingValidators(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	codec := encodingConfig.Marshaler
+
+	height := int64(103)
+	heightStr := strconv.FormatInt(height, 10)
+	queryPath := "/cosmos.staking.v1beta1.Query/Validators"
+	status := stakingtypes.Bonded.String()
+
+	expectedValidators := stakingtypes.Validators{}
+	for i, operatorAddress := range []string{
+		"kavavaloper1ppj7c8tqt2e3rzqtmztsmd6ea6u3nz6qggcp5e",
+		"kavavaloper1zw8ce44kdqzfu0r2t9qwr75gqdcarclf9fj9lt",
+	} {
+		expectedValidators = append(expectedValidators, stakingtypes.Validator{
+			OperatorAddress:   operatorAddress,
+			Status:            stakingtypes.Bonded,
+			Tokens:            sdk.NewInt(int64(i+1) * 100),
+			DelegatorShares:   sdk.NewDec(int64(i+1) * 100),
+			Commission:        stakingtypes.NewCommission(sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(2, 1), sdk.NewDecWithPrec(1, 2)),
+			MinSelfDelegation: sdk.OneInt(),
+		})
+	}
+
+	page1Request, err := codec.Marshal(&stakingtypes.QueryValidatorsRequest{
+		Status:     status,
+		Pagination: &query.PageRequest{Key: nil, Limit: query.DefaultLimit},
+	})
+	require.NoError(t, err)
+	page1Response, err := codec.Marshal(&stakingtypes.QueryValidatorsResponse{
+		Validators: expectedValidators[:1],
+		Pagination: &query.PageResponse{NextKey: []byte("request-page-2")},
+	})
+	require.NoError(t, err)
+
+	page2Request, err := codec.Marshal(&stakingtypes.QueryValidatorsRequest{
+		Status:     status,
+		Pagination: &query.PageRequest{Key: []byte("request-page-2"), Limit: query.DefaultLimit},
+	})
+	require.NoError(t, err)
+	page2Response, err := codec.Marshal(&stakingtypes.QueryValidatorsResponse{
+		Validators: expectedValidators[1:],
+		Pagination: &query.PageResponse{NextKey: nil},
+	})
+	require.NoError(t, err)
+
+	mockCalls := []abciQueryCall{
+		{abciRequestQuery{heightStr, queryPath, page1Request, false}, abcitypes.ResponseQuery{Height: height, Value: page1Response}},
+		{abciRequestQuery{heightStr, queryPath, page2Request, false}, abcitypes.ResponseQuery{Height: height, Value: page2Response}},
+	}
+
+	ts := rpcTestServer(t, newABCIQueryHandler(t, mockCalls))
+	client, err := kava.NewHTTPClient(ts.URL)
+	require.NoError(t, err)
+
+	validators, err := client.StakingValidators(context.Background(), status, height)
+	require.NoError(t, err)
+	require.Equal(t, expectedValidators, validators)
+}
+
+func TestHTTPClient_DelegationRewards(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	codec := encodingConfig.Marshaler
+
+	height := int64(103)
+	heightStr := strconv.FormatInt(height, 10)
+	queryPath := "/cosmos.distribution.v1beta1.Query/DelegationTotalRewards"
+
+	expectedRewards := &distrtypes.QueryDelegationTotalRewardsResponse{
+		Rewards: []distrtypes.DelegationDelegatorReward{
+			{
+				ValidatorAddress: "kavavaloper1ppj7c8tqt2e3rzqtmztsmd6ea6u3nz6qggcp5e",
+				Reward:           sdk.NewDecCoins(sdk.NewDecCoin("ukava", sdk.NewInt(12))),
+			},
+		},
+		Total: sdk.NewDecCoins(sdk.NewDecCoin("ukava", sdk.NewInt(12))),
+	}
+
+	request, err := codec.Marshal(&distrtypes.QueryDelegationTotalRewardsRequest{DelegatorAddress: testAddr.String()})
+	require.NoError(t, err)
+	response, err := codec.Marshal(expectedRewards)
+	require.NoError(t, err)
+
+	mockCalls := []abciQueryCall{
+		{abciRequestQuery{heightStr, queryPath, request, false}, abcitypes.ResponseQuery{Height: height, Value: response}},
+	}
+
+	ts := rpcTestServer(t, newABCIQueryHandler(t, mockCalls))
+	client, err := kava.NewHTTPClient(ts.URL)
+	require.NoError(t, err)
+
+	rewards, err := client.DelegationRewards(context.Background(), testAddr, height)
+	require.NoError(t, err)
+	require.Equal(t, expectedRewards, rewards)
+}
+
+func TestHTTPClient_ERC20Balance(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	codec := encodingConfig.Marshaler
+
+	height := int64(104)
+	heightStr := strconv.FormatInt(height, 10)
+	queryPath := "/ethermint.evm.v1.Query/EthCall"
+
+	contract := common.HexToAddress("0x919C1c267BC06a7039e03fcc2eF738525769109c")
+	holder := common.BytesToAddress(testAddr)
+
+	data := hexutil.Bytes(append(common.FromHex("0x70a08231"), common.LeftPadBytes(holder.Bytes(), 32)...))
+	args, err := json.Marshal(&evmtypes.TransactionArgs{To: &contract, Data: &data})
+	require.NoError(t, err)
+	request, err := codec.Marshal(&evmtypes.EthCallRequest{Args: args, GasCap: 1_000_000})
+	require.NoError(t, err)
+
+	balance, err := codec.Marshal(&evmtypes.MsgEthereumTxResponse{Ret: common.LeftPadBytes(big.NewInt(1500000).Bytes(), 32)})
+	require.NoError(t, err)
+	reverted, err := codec.Marshal(&evmtypes.MsgEthereumTxResponse{VmError: "execution reverted"})
+	require.NoError(t, err)
+	noCode, err := codec.Marshal(&evmtypes.MsgEthereumTxResponse{})
+	require.NoError(t, err)
+
+	mockCalls := []abciQueryCall{
+		{abciRequestQuery{heightStr, queryPath, request, false}, abcitypes.ResponseQuery{Height: height, Value: balance}},
+		{abciRequestQuery{heightStr, queryPath, request, false}, abcitypes.ResponseQuery{Height: height, Value: reverted}},
+		{abciRequestQuery{heightStr, queryPath, request, false}, abcitypes.ResponseQuery{Height: height, Value: noCode}},
+	}
+
+	ts := rpcTestServer(t, newABCIQueryHandler(t, mockCalls))
+	client, err := kava.NewHTTPClient(ts.URL)
+	require.NoError(t, err)
+
+	value, err := client.ERC20Balance(context.Background(), contract, holder, height)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500000), value)
+
+	value, err = client.ERC20Balance(context.Background(), contract, holder, height)
+	assert.Nil(t, value)
+	assert.EqualError(t, err, fmt.Sprintf("balanceOf call to contract %s failed: execution reverted", contract))
+
+	// contracts without code at the height return no data
+	value, err = client.ERC20Balance(context.Background(), contract, holder, height)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(0), value)
+}
+
 func TestHTTPClient_SimulateTx(t *testing.T) {
 	encodingConfig := app.MakeEncodingConfig()
 	testTx := encodingConfig.TxConfig.NewTxBuilder().GetTx()
@@ -695,6 +844,32 @@ func TestHTTPClient_SimulateTx(t *testing.T) {
 	simResp, err = client.SimulateTx(context.Background(), testTx)
 	assert.Nil(t, simResp)
 	assert.Error(t, err)
+
+	simulateResponse = func(request jsonrpctypes.RPCRequest) jsonrpctypes.RPCResponse {
+		abciResult := ctypes.ResultABCIQuery{
+			Response: abcitypes.ResponseQuery{
+				Codespace: "sdk",
+				Code:      5,
+				Log:       "insufficient funds",
+			},
+		}
+
+		data, err := json.Marshal(&abciResult)
+		require.NoError(t, err)
+
+		return jsonrpctypes.RPCResponse{
+			JSONRPC: request.JSONRPC,
+			ID:      request.ID,
+			Result:  data,
+		}
+	}
+	simResp, err = client.SimulateTx(context.Background(), testTx)
+	assert.Nil(t, simResp)
+	assert.EqualError(t, err, "insufficient funds")
+
+	var simErr *kava.SimulationError
+	require.ErrorAs(t, err, &simErr)
+	assert.Equal(t, &kava.SimulationError{Codespace: "sdk", Code: 5, Log: "insufficient funds"}, simErr)
 }
 
 func TestParseABCIResult(t *testing.T) {
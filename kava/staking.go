@@ -0,0 +1,404 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kava
+
+import (
+	"context"
+	"log"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	vestingexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+)
+
+// stakedSubAccounts are the sub-accounts of staked coins in the order split staking operations are returned
+var stakedSubAccounts = []string{
+	AccLiquidDelegated,
+	AccVestingDelegated,
+	AccLiquidUnbonding,
+	AccVestingUnbonding,
+}
+
+// VestingStake is the staking state of a vesting account, used to split its staking operations
+// between the liquid and vesting sub-accounts with the same rule as its sub-account balances
+type VestingStake struct {
+	// Vesting is the amount of vesting coins at the block time
+	Vesting          sdkmath.Int
+	DelegatedFree    sdkmath.Int
+	DelegatedVesting sdkmath.Int
+	Delegated        sdkmath.Int
+	Unbonding        sdkmath.Int
+}
+
+// balances returns the staked sub-account balances of the vesting account
+func (s *VestingStake) balances() map[string]sdkmath.Int {
+	liquidDelegated, vestingDelegated := splitDelegated(s.DelegatedFree, s.Delegated, s.Unbonding)
+	liquidUnbonding, vestingUnbonding := splitUnbonding(s.DelegatedFree, s.Unbonding)
+
+	return map[string]sdkmath.Int{
+		AccLiquidDelegated:  liquidDelegated,
+		AccVestingDelegated: vestingDelegated,
+		AccLiquidUnbonding:  liquidUnbonding,
+		AccVestingUnbonding: vestingUnbonding,
+	}
+}
+
+// apply updates the stake for a staking operation type, tracking delegated free and vesting coins
+// the same way as the vesting account
+func (s *VestingStake) apply(opType string, amount sdkmath.Int) {
+	switch opType {
+	case DelegateOpType:
+		// vesting coins that are not already delegated are delegated first
+		vesting := sdkmath.MinInt(sdkmath.MaxInt(s.Vesting.Sub(s.DelegatedVesting), sdkmath.ZeroInt()), amount)
+		s.DelegatedVesting = s.DelegatedVesting.Add(vesting)
+		s.DelegatedFree = s.DelegatedFree.Add(amount.Sub(vesting))
+		s.Delegated = s.Delegated.Add(amount)
+	case UndelegateOpType:
+		s.Delegated = s.Delegated.Sub(amount)
+		s.Unbonding = s.Unbonding.Add(amount)
+	case CancelUnbondingOpType:
+		s.Unbonding = s.Unbonding.Sub(amount)
+		s.Delegated = s.Delegated.Add(amount)
+	case CompleteUnbondingOpType:
+		// delegations are only untracked when unbonding completes, with free coins untracked first
+		free := sdkmath.MinInt(s.DelegatedFree, amount)
+		vesting := sdkmath.MinInt(s.DelegatedVesting, amount.Sub(free))
+		s.DelegatedFree = s.DelegatedFree.Sub(free)
+		s.DelegatedVesting = s.DelegatedVesting.Sub(vesting)
+		s.Unbonding = s.Unbonding.Sub(amount)
+	}
+}
+
+// VestingStakeLoader returns the staking state of a delegator, or nil if it is not a vesting account
+type VestingStakeLoader func(delegator sdk.AccAddress) (*VestingStake, error)
+
+// VestingStakes tracks the staking state of vesting delegators through the operations of a block
+type VestingStakes struct {
+	load   VestingStakeLoader
+	stakes map[string]*VestingStake
+}
+
+// NewVestingStakes returns vesting stakes that load the state of each delegator once.  A delegator
+// whose state can not be loaded is logged and its staking operations are returned unsplit, so a
+// failed lookup, such as for a height pruned by the node, does not fail the block.
+func NewVestingStakes(load VestingStakeLoader) *VestingStakes {
+	return &VestingStakes{
+		load:   load,
+		stakes: make(map[string]*VestingStake),
+	}
+}
+
+func (s *VestingStakes) get(delegator string) (*VestingStake, error) {
+	if stake, ok := s.stakes[delegator]; ok {
+		return stake, nil
+	}
+
+	addr, err := sdk.AccAddressFromBech32(delegator)
+	if err != nil {
+		return nil, err
+	}
+
+	stake, err := s.load(addr)
+	if err != nil {
+		log.Printf("could not load the vesting stake of %s, staking operations are not split: %s", delegator, err)
+		stake = nil
+	}
+	s.stakes[delegator] = stake
+
+	return stake, nil
+}
+
+// SplitOperations replaces the liquid staking operations of vesting delegators with operations on
+// the liquid and vesting sub-accounts whose balances they change, and updates their staking state.
+// Operations of other accounts are returned unchanged.
+func (s *VestingStakes) SplitOperations(registry *CurrencyRegistry, ops []*types.Operation) ([]*types.Operation, error) {
+	currency, ok := registry.Currency(stakingDenom)
+	if s == nil || !ok {
+		return ops, nil
+	}
+
+	split := make([]*types.Operation, 0, len(ops))
+	indexes := make(map[int64]int64, len(ops))
+	for i := 0; i < len(ops); {
+		change, ok := parseStakingChange(currency, ops[i:])
+
+		var stake *VestingStake
+		if ok {
+			var err error
+			stake, err = s.get(change.delegator)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if stake == nil {
+			op := ops[i]
+			indexes[op.OperationIdentifier.Index] = int64(len(split))
+			op.OperationIdentifier = newOpID(int64(len(split)))
+			for j, related := range op.RelatedOperations {
+				op.RelatedOperations[j] = newOpID(indexes[related.Index])
+			}
+
+			split = append(split, op)
+			i++
+			continue
+		}
+
+		for _, op := range change.ops {
+			indexes[op.OperationIdentifier.Index] = int64(len(split))
+		}
+		split = append(split, stake.changeOperations(change, currency, int64(len(split)))...)
+		i += len(change.ops)
+	}
+
+	return split, nil
+}
+
+// changeOperations applies a staking change and returns an operation for each staked sub-account
+// balance it changes
+func (s *VestingStake) changeOperations(change stakingChange, currency *types.Currency, index int64) []*types.Operation {
+	before := s.balances()
+	s.apply(change.ops[0].Type, change.amount)
+	after := s.balances()
+
+	first := index
+	ops := []*types.Operation{}
+	for _, subAccount := range stakedSubAccounts {
+		amount := after[subAccount].Sub(before[subAccount])
+		if amount.IsZero() {
+			continue
+		}
+
+		op := &types.Operation{
+			OperationIdentifier: newOpID(index),
+			Type:                change.ops[0].Type,
+			Status:              change.ops[0].Status,
+			Account:             newSubAccountID(change.delegator, subAccount),
+			Amount: &types.Amount{
+				Value:    amount.String(),
+				Currency: currency,
+			},
+			Metadata: make(map[string]interface{}),
+		}
+		if index != first {
+			op.RelatedOperations = []*types.OperationIdentifier{newOpID(first)}
+		}
+		for key, value := range change.ops[0].Metadata {
+			op.Metadata[key] = value
+		}
+
+		ops = append(ops, op)
+		index++
+	}
+
+	return ops
+}
+
+// replay applies the staking changes of a delegator in ops to the stake
+func (s *VestingStake) replay(registry *CurrencyRegistry, delegator string, ops []*types.Operation) {
+	currency, ok := registry.Currency(stakingDenom)
+	if !ok {
+		return
+	}
+
+	for i := 0; i < len(ops); {
+		change, ok := parseStakingChange(currency, ops[i:])
+		if !ok {
+			i++
+			continue
+		}
+
+		if change.delegator == delegator {
+			s.apply(change.ops[0].Type, change.amount)
+		}
+		i += len(change.ops)
+	}
+}
+
+// stakingChange is a change to the stake of a delegator from the operations of a staking event
+type stakingChange struct {
+	delegator string
+	amount    sdkmath.Int
+	ops       []*types.Operation
+}
+
+// parseStakingChange returns the staking change of the liquid staking operations at the start of ops.
+// Delegations and unbonding completions are a single operation, while unbonding and cancelled
+// unbonding move coins between the liquid delegated and unbonding sub-accounts.
+func parseStakingChange(currency *types.Currency, ops []*types.Operation) (stakingChange, bool) {
+	switch ops[0].Type {
+	case DelegateOpType:
+		return parseStakingOperations(currency, ops[:1], AccLiquidDelegated)
+	case CompleteUnbondingOpType:
+		return parseStakingOperations(currency, ops[:1], AccLiquidUnbonding)
+	case UndelegateOpType:
+		if len(ops) > 1 {
+			return parseStakingOperations(currency, ops[:2], AccLiquidDelegated, AccLiquidUnbonding)
+		}
+	case CancelUnbondingOpType:
+		if len(ops) > 1 {
+			return parseStakingOperations(currency, ops[:2], AccLiquidUnbonding, AccLiquidDelegated)
+		}
+	}
+
+	return stakingChange{}, false
+}
+
+// parseStakingOperations returns the staking change of successful operations of a single delegator
+// and amount on the given sub-accounts
+func parseStakingOperations(currency *types.Currency, ops []*types.Operation, subAccounts ...string) (stakingChange, bool) {
+	var change stakingChange
+
+	for i, op := range ops {
+		if op.Type != ops[0].Type || op.Status == nil || *op.Status != SuccessStatus ||
+			op.Account == nil || op.Account.SubAccount == nil || op.Account.SubAccount.Address != subAccounts[i] ||
+			op.Amount == nil || types.Hash(op.Amount.Currency) != types.Hash(currency) {
+			return stakingChange{}, false
+		}
+
+		amount, ok := sdkmath.NewIntFromString(op.Amount.Value)
+		if !ok {
+			return stakingChange{}, false
+		}
+
+		if i == 0 {
+			change = stakingChange{delegator: op.Account.Address, amount: amount.Abs(), ops: ops}
+		} else if op.Account.Address != change.delegator || !amount.Abs().Equal(change.amount) {
+			return stakingChange{}, false
+		}
+	}
+
+	return change, true
+}
+
+// vestingStakes returns the vesting stakes of delegators before the block at height, with vesting
+// coins at the block time
+func (c *Client) vestingStakes(ctx context.Context, height int64, blockTime time.Time) *VestingStakes {
+	return NewVestingStakes(func(delegator sdk.AccAddress) (*VestingStake, error) {
+		return c.vestingStake(ctx, delegator, height-1, blockTime)
+	})
+}
+
+// transactionVestingStakes returns the vesting stakes of delegators before the transaction at txIndex
+// of the block at height, replaying the staking operations of the begin block events and earlier
+// transactions of the block.  A txIndex of the number of transactions in the block returns the
+// stakes before the end block events.
+func (c *Client) transactionVestingStakes(ctx context.Context, height int64, blockTime time.Time, txIndex int) *VestingStakes {
+	var preceding []*types.Operation
+
+	return NewVestingStakes(func(delegator sdk.AccAddress) (*VestingStake, error) {
+		stake, err := c.vestingStake(ctx, delegator, height-1, blockTime)
+		if err != nil || stake == nil {
+			return stake, err
+		}
+
+		if preceding == nil {
+			preceding, err = c.precedingOperations(ctx, height, txIndex)
+			if err != nil {
+				return nil, err
+			}
+		}
+		stake.replay(c.registry, delegator.String(), preceding)
+
+		return stake, nil
+	})
+}
+
+// precedingOperations returns the operations of the begin block events and the successful
+// transaction events of a block before the transaction at txIndex
+func (c *Client) precedingOperations(ctx context.Context, height int64, txIndex int) ([]*types.Operation, error) {
+	results, err := c.rpc.BlockResults(ctx, &height)
+	if err != nil {
+		return nil, err
+	}
+
+	eventOpStatus := SuccessStatus
+	operations, err := EventsToOperations(c.registry, stringifyEvents(results.BeginBlockEvents), &eventOpStatus, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < txIndex && i < len(results.TxsResults); i++ {
+		if results.TxsResults[i].Code != abci.CodeTypeOK {
+			continue
+		}
+
+		txOps, err := EventsToOperations(c.registry, stringifyEvents(results.TxsResults[i].Events), &eventOpStatus, 0)
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, txOps...)
+	}
+
+	return operations, nil
+}
+
+// vestingStake returns the staking state of a delegator at a height, or nil if it is not a vesting account.
+// Delegations are only queried for vesting accounts.
+func (c *Client) vestingStake(ctx context.Context, delegator sdk.AccAddress, height int64, blockTime time.Time) (*VestingStake, error) {
+	if height < 1 {
+		return nil, nil
+	}
+
+	acc, err := c.rpc.Account(ctx, delegator, height)
+	if err != nil {
+		if addressNotFound.MatchString(err.Error()) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	vacc, ok := acc.(vestingexported.VestingAccount)
+	if !ok {
+		return nil, nil
+	}
+
+	delegations, err := c.rpc.Delegations(ctx, delegator, height)
+	if err != nil {
+		return nil, err
+	}
+
+	unbondingDelegations, err := c.rpc.UnbondingDelegations(ctx, delegator, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VestingStake{
+		Vesting:          vacc.GetVestingCoins(blockTime).AmountOf(stakingDenom),
+		DelegatedFree:    vacc.GetDelegatedFree().AmountOf(stakingDenom),
+		DelegatedVesting: vacc.GetDelegatedVesting().AmountOf(stakingDenom),
+		Delegated:        sumDelegations(delegations).AmountOf(stakingDenom),
+		Unbonding:        sumUnbondingDelegations(unbondingDelegations).AmountOf(stakingDenom),
+	}, nil
+}
+
+// splitVestingStakingOperations splits the staking operations of vesting delegators in a transaction
+func (c *Client) splitVestingStakingOperations(stakes *VestingStakes, transaction *types.Transaction) error {
+	if transaction == nil {
+		return nil
+	}
+
+	operations, err := stakes.SplitOperations(c.registry, transaction.Operations)
+	if err != nil {
+		return err
+	}
+	transaction.Operations = operations
+
+	return nil
+}
@@ -18,13 +18,18 @@ package kava
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
 	tmclient "github.com/cometbft/cometbft/rpc/client"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 const (
@@ -43,6 +48,9 @@ const (
 	// FailureStatus is the status of any
 	// Kava operation considered unsuccessful.
 	FailureStatus = "failure"
+	// PendingStatus is the status of any
+	// Kava operation that has not been included in a block.
+	PendingStatus = "pending"
 
 	// FeeOpType is used to reference fee operations
 	FeeOpType = "fee"
@@ -52,6 +60,28 @@ const (
 	MintOpType = "mint"
 	// BurnOpType is used to reference burn operations
 	BurnOpType = "burn"
+	// DelegateOpType is used to reference staking delegate operations
+	DelegateOpType = "delegate"
+	// UndelegateOpType is used to reference staking undelegate operations
+	UndelegateOpType = "undelegate"
+	// RedelegateOpType is used to reference staking redelegate operations
+	RedelegateOpType = "redelegate"
+	// WithdrawRewardsOpType is used to reference staking reward withdraw operations
+	WithdrawRewardsOpType = "withdraw_rewards"
+	// CancelUnbondingOpType is used to reference staking cancel unbonding operations
+	CancelUnbondingOpType = "cancel_unbonding"
+	// CompleteUnbondingOpType is used to reference staking unbonding completion operations
+	CompleteUnbondingOpType = "complete_unbonding"
+	// ConvertCoinToERC20OpType is used to reference evmutil coin to erc20 conversion operations
+	ConvertCoinToERC20OpType = "convert_coin_to_erc20"
+	// ConvertERC20ToCoinOpType is used to reference evmutil erc20 to coin conversion operations
+	ConvertERC20ToCoinOpType = "convert_erc20_to_coin"
+	// IBCTransferOpType is used to reference ibc transfer operations
+	IBCTransferOpType = "ibc_transfer"
+	// AuthzGrantOpType is used to reference authz grant operations
+	AuthzGrantOpType = "authz_grant"
+	// AuthzRevokeOpType is used to reference authz revoke operations
+	AuthzRevokeOpType = "authz_revoke"
 
 	// AccLiquid represents spendable coins
 	AccLiquid = "liquid"
@@ -65,6 +95,23 @@ const (
 	AccVestingDelegated = "vesting_delegated"
 	// AccVestingUnbonding represents vesting coins that are unbonding
 	AccVestingUnbonding = "vesting_unbonding"
+
+	// AccountCallMethod returns the account number, sequence and public key of an address
+	AccountCallMethod = "account"
+	// DelegationsCallMethod returns the delegations of an address
+	DelegationsCallMethod = "delegations"
+	// UnbondingDelegationsCallMethod returns the unbonding delegations of an address
+	UnbondingDelegationsCallMethod = "unbonding_delegations"
+	// ValidatorsCallMethod returns the staking validators, optionally filtered by bond status
+	ValidatorsCallMethod = "validators"
+	// StakingRewardsCallMethod returns the outstanding staking rewards of an address
+	StakingRewardsCallMethod = "staking_rewards"
+	// VestingScheduleCallMethod returns the vesting schedule of an address
+	VestingScheduleCallMethod = "vesting_schedule"
+	// SimulateTransactionCallMethod dry runs an unsigned or signed transaction
+	SimulateTransactionCallMethod = "simulate_transaction"
+	// TransactionCallMethod returns a transaction by hash
+	TransactionCallMethod = "transaction"
 )
 
 var (
@@ -74,6 +121,17 @@ var (
 		TransferOpType,
 		MintOpType,
 		BurnOpType,
+		DelegateOpType,
+		UndelegateOpType,
+		RedelegateOpType,
+		WithdrawRewardsOpType,
+		CancelUnbondingOpType,
+		CompleteUnbondingOpType,
+		ConvertCoinToERC20OpType,
+		ConvertERC20ToCoinOpType,
+		IBCTransferOpType,
+		AuthzGrantOpType,
+		AuthzRevokeOpType,
 	}
 
 	// OperationStatuses are all supported operation statuses.
@@ -86,12 +144,27 @@ var (
 			Status:     FailureStatus,
 			Successful: false,
 		},
+		{
+			Status:     PendingStatus,
+			Successful: false,
+		},
 	}
 
 	// CallMethods are all supported call methods.
-	CallMethods = []string{}
+	CallMethods = []string{
+		AccountCallMethod,
+		DelegationsCallMethod,
+		UnbondingDelegationsCallMethod,
+		ValidatorsCallMethod,
+		StakingRewardsCallMethod,
+		VestingScheduleCallMethod,
+		SimulateTransactionCallMethod,
+		TransactionCallMethod,
+	}
 
-	// BalanceExemptions lists sub-accounts that are balance exempt
+	// BalanceExemptions lists sub-accounts that are balance exempt.  Staked sub-accounts
+	// remain exempt since slashes, share rounding on unbond and delegations made by other
+	// modules change their balances without operations.
 	BalanceExemptions = []*types.BalanceExemption{
 		&types.BalanceExemption{
 			SubAccountAddress: strToPtr(AccLiquid),
@@ -120,7 +193,7 @@ var (
 	}
 )
 
-// Currencies represents supported kava denom to rosetta currencies
+// Currencies represents the default kava denom to rosetta currencies each network registry starts with
 var Currencies = map[string]*types.Currency{
 	"ukava": &types.Currency{
 		Symbol:   "KAVA",
@@ -140,7 +213,7 @@ var Currencies = map[string]*types.Currency{
 	},
 }
 
-// Denoms represents rosetta symbol to kava denom conversion
+// Denoms represents rosetta symbol to kava denom conversion for the default Currencies
 var Denoms = map[string]string{
 	"KAVA": "ukava",
 	"HARD": "hard",
@@ -156,6 +229,11 @@ type RPCClient interface {
 	Balance(ctx context.Context, addr sdk.AccAddress, height int64) (sdk.Coins, error)
 	Delegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.DelegationResponses, error)
 	UnbondingDelegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.UnbondingDelegations, error)
+	StakingValidators(ctx context.Context, status string, height int64) (stakingtypes.Validators, error)
+	DelegationRewards(ctx context.Context, addr sdk.AccAddress, height int64) (*distrtypes.QueryDelegationTotalRewardsResponse, error)
+	DenomsMetadata(ctx context.Context, height int64) ([]banktypes.Metadata, error)
+	DenomTraces(ctx context.Context, height int64) (ibctransfertypes.Traces, error)
+	ERC20Balance(ctx context.Context, contract common.Address, holder common.Address, height int64) (*big.Int, error)
 	SimulateTx(ctx context.Context, tx authsigning.Tx) (*sdk.SimulationResponse, error)
 }
 
@@ -0,0 +1,84 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheStatsFunc returns the hit and miss counts of caches by cache name
+type CacheStatsFunc func() map[string]kava.CacheStats
+
+// cacheCollector collects the hit and miss counts of the block caches of each network
+// when metrics are scraped
+type cacheCollector struct {
+	hits   *prometheus.Desc
+	misses *prometheus.Desc
+
+	mu    sync.RWMutex
+	stats map[string]CacheStatsFunc
+}
+
+var _ prometheus.Collector = (*cacheCollector)(nil)
+
+func newCacheCollector() *cacheCollector {
+	return &cacheCollector{
+		hits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_hits_total"),
+			"Number of block cache hits by network and cache.",
+			[]string{"network", "cache"},
+			nil,
+		),
+		misses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_misses_total"),
+			"Number of block cache misses by network and cache.",
+			[]string{"network", "cache"},
+			nil,
+		),
+		stats: make(map[string]CacheStatsFunc),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+}
+
+// Collect implements prometheus.Collector
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for network, stats := range c.stats {
+		for cache, cacheStats := range stats() {
+			ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(cacheStats.Hits), network, cache)
+			ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(cacheStats.Misses), network, cache)
+		}
+	}
+}
+
+// ObserveCaches reports the hit and miss counts returned by stats for the caches of a
+// network each time metrics are scraped, replacing any caches observed for the network
+func (m *Metrics) ObserveCaches(network string, stats CacheStatsFunc) {
+	m.caches.mu.Lock()
+	defer m.caches.mu.Unlock()
+
+	m.caches.stats[network] = stats
+}
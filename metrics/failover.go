@@ -0,0 +1,125 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/url"
+	"sync"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// EndpointStatusesFunc returns the last known status of each rpc endpoint
+type EndpointStatusesFunc func() []kava.RPCEndpointStatus
+
+// endpointCollector collects the health and heights of the failover rpc endpoints of each
+// network when metrics are scraped
+type endpointCollector struct {
+	healthy        *prometheus.Desc
+	catchingUp     *prometheus.Desc
+	earliestHeight *prometheus.Desc
+	latestHeight   *prometheus.Desc
+
+	mu       sync.RWMutex
+	statuses map[string]EndpointStatusesFunc
+}
+
+var _ prometheus.Collector = (*endpointCollector)(nil)
+
+func newEndpointCollector() *endpointCollector {
+	labels := []string{"network", "endpoint"}
+
+	return &endpointCollector{
+		healthy: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rpc_endpoint_healthy"),
+			"1 if a failover rpc endpoint of a network is used for requests, otherwise 0.",
+			labels,
+			nil,
+		),
+		catchingUp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rpc_endpoint_catching_up"),
+			"1 if a failover rpc endpoint of a network reported that it is catching up, otherwise 0.",
+			labels,
+			nil,
+		),
+		earliestHeight: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rpc_endpoint_earliest_block_height"),
+			"Earliest block height last reported by a failover rpc endpoint of a network.",
+			labels,
+			nil,
+		),
+		latestHeight: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rpc_endpoint_latest_block_height"),
+			"Latest block height last reported by a failover rpc endpoint of a network.",
+			labels,
+			nil,
+		),
+		statuses: make(map[string]EndpointStatusesFunc),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *endpointCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.healthy
+	ch <- c.catchingUp
+	ch <- c.earliestHeight
+	ch <- c.latestHeight
+}
+
+// Collect implements prometheus.Collector
+func (c *endpointCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for network, statuses := range c.statuses {
+		for _, status := range statuses() {
+			endpoint := redactURL(status.URL)
+
+			ch <- prometheus.MustNewConstMetric(c.healthy, prometheus.GaugeValue, boolToFloat(status.Healthy), network, endpoint)
+			ch <- prometheus.MustNewConstMetric(c.catchingUp, prometheus.GaugeValue, boolToFloat(status.CatchingUp), network, endpoint)
+			ch <- prometheus.MustNewConstMetric(c.earliestHeight, prometheus.GaugeValue, float64(status.EarliestHeight), network, endpoint)
+			ch <- prometheus.MustNewConstMetric(c.latestHeight, prometheus.GaugeValue, float64(status.LatestHeight), network, endpoint)
+		}
+	}
+}
+
+// ObserveRPCEndpoints reports the endpoint statuses returned by statuses for the failover
+// rpc endpoints of a network each time metrics are scraped
+func (m *Metrics) ObserveRPCEndpoints(network string, statuses EndpointStatusesFunc) {
+	m.endpoints.mu.Lock()
+	defer m.endpoints.mu.Unlock()
+
+	m.endpoints.statuses[network] = statuses
+}
+
+// redactURL removes any password from an endpoint url so credentials are not exported
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	return u.Redacted()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,206 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics collects prometheus metrics for the rosetta api and upstream kava rpc calls
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	tmclient "github.com/cometbft/cometbft/rpc/client"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "rosetta_kava"
+
+	// unknownEndpoint is the endpoint label for requests to paths that are not served
+	unknownEndpoint = "unknown"
+)
+
+// Metrics holds the prometheus collectors for a rosetta-kava service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	rosettaErrors   *prometheus.CounterVec
+
+	rpcDuration *prometheus.HistogramVec
+	rpcErrors   *prometheus.CounterVec
+
+	latestBlockHeight *prometheus.GaugeVec
+	syncLag           *prometheus.GaugeVec
+	catchingUp        *prometheus.GaugeVec
+
+	caches    *cacheCollector
+	endpoints *endpointCollector
+}
+
+// New returns Metrics registered with a new prometheus registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of rosetta api requests by endpoint and http status code.",
+		}, []string{"endpoint", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of rosetta api requests by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		rosettaErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rosetta_errors_total",
+			Help:      "Number of rosetta errors returned by endpoint and rosetta error code.",
+		}, []string{"endpoint", "error_code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_request_duration_seconds",
+			Help:      "Latency of upstream kava rpc calls by network and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"network", "method"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "Number of failed upstream kava rpc calls by network and method.",
+		}, []string{"network", "method"}),
+		latestBlockHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "latest_block_height",
+			Help:      "Latest block height reported by the kava node of each network.",
+		}, []string{"network"}),
+		syncLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sync_lag_seconds",
+			Help:      "Seconds between the time of the latest block reported by the kava node of each network and now.",
+		}, []string{"network"}),
+		catchingUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "catching_up",
+			Help:      "1 if the kava node of a network reports that it is catching up, otherwise 0.",
+		}, []string{"network"}),
+		caches:    newCacheCollector(),
+		endpoints: newEndpointCollector(),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.rosettaErrors,
+		m.rpcDuration,
+		m.rpcErrors,
+		m.latestBlockHeight,
+		m.syncLag,
+		m.catchingUp,
+		m.caches,
+		m.endpoints,
+	)
+
+	return m
+}
+
+// Handler returns the http handler serving the metrics in the prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records the request count, latency and rosetta error code of each request
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		endpoint := r.URL.Path
+		if recorder.status == http.StatusNotFound {
+			endpoint = unknownEndpoint
+		}
+
+		m.requests.WithLabelValues(endpoint, strconv.Itoa(recorder.status)).Inc()
+		m.requestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+		if recorder.status >= http.StatusBadRequest {
+			var rosettaErr types.Error
+			if err := json.Unmarshal(recorder.body.Bytes(), &rosettaErr); err == nil {
+				m.rosettaErrors.WithLabelValues(endpoint, strconv.Itoa(int(rosettaErr.Code))).Inc()
+			}
+		}
+	})
+}
+
+// ObserveStatus updates the latest block height and sync lag of a network from a node status
+func (m *Metrics) ObserveStatus(network string, status *ctypes.ResultStatus) {
+	m.latestBlockHeight.WithLabelValues(network).Set(float64(status.SyncInfo.LatestBlockHeight))
+	m.syncLag.WithLabelValues(network).Set(time.Since(status.SyncInfo.LatestBlockTime).Seconds())
+
+	if status.SyncInfo.CatchingUp {
+		m.catchingUp.WithLabelValues(network).Set(1)
+	} else {
+		m.catchingUp.WithLabelValues(network).Set(0)
+	}
+}
+
+// MonitorNode observes the node status every interval until the context is done, so
+// the latest block height and sync lag are current without api traffic
+func (m *Metrics) MonitorNode(ctx context.Context, client tmclient.StatusClient, interval time.Duration) {
+	for {
+		// statuses are observed by the instrumented rpc client
+		if _, err := client.Status(ctx); err != nil {
+			log.Printf("unable to fetch node status for metrics: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// responseRecorder records the status code of a response, and the body of error responses
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status >= http.StatusBadRequest {
+		r.body.Write(b)
+	}
+
+	return r.ResponseWriter.Write(b)
+}
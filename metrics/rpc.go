@@ -0,0 +1,203 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RPCClient records the latency and errors of each kava rpc call of a network by method
+type RPCClient struct {
+	kava.RPCClient
+	metrics *Metrics
+	network string
+}
+
+var _ kava.RPCClient = (*RPCClient)(nil)
+
+// NewRPCClient returns an RPCClient recording calls made to the rpc of a network
+func NewRPCClient(rpc kava.RPCClient, metrics *Metrics, network string) *RPCClient {
+	return &RPCClient{
+		RPCClient: rpc,
+		metrics:   metrics,
+		network:   network,
+	}
+}
+
+// observe records the duration of a call and counts the call as failed if it returns an error
+func observe[T any](c *RPCClient, method string, call func() (T, error)) (T, error) {
+	start := time.Now()
+	result, err := call()
+
+	c.metrics.rpcDuration.WithLabelValues(c.network, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		c.metrics.rpcErrors.WithLabelValues(c.network, method).Inc()
+	}
+
+	return result, err
+}
+
+// Status returns the node status and updates the latest block height and sync lag
+func (c *RPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	status, err := observe(c, "status", func() (*ctypes.ResultStatus, error) {
+		return c.RPCClient.Status(ctx)
+	})
+	if err == nil {
+		c.metrics.ObserveStatus(c.network, status)
+	}
+
+	return status, err
+}
+
+// NetInfo returns the node network info
+func (c *RPCClient) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
+	return observe(c, "net_info", func() (*ctypes.ResultNetInfo, error) {
+		return c.RPCClient.NetInfo(ctx)
+	})
+}
+
+// Block returns the block at a height
+func (c *RPCClient) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	return observe(c, "block", func() (*ctypes.ResultBlock, error) {
+		return c.RPCClient.Block(ctx, height)
+	})
+}
+
+// BlockByHash returns the block for a hash
+func (c *RPCClient) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBlock, error) {
+	return observe(c, "block_by_hash", func() (*ctypes.ResultBlock, error) {
+		return c.RPCClient.BlockByHash(ctx, hash)
+	})
+}
+
+// BlockResults returns the block results at a height
+func (c *RPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	return observe(c, "block_results", func() (*ctypes.ResultBlockResults, error) {
+		return c.RPCClient.BlockResults(ctx, height)
+	})
+}
+
+// Header returns the block header at a height
+func (c *RPCClient) Header(ctx context.Context, height *int64) (*ctypes.ResultHeader, error) {
+	return observe(c, "header", func() (*ctypes.ResultHeader, error) {
+		return c.RPCClient.Header(ctx, height)
+	})
+}
+
+// Tx returns a transaction by hash
+func (c *RPCClient) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	return observe(c, "tx", func() (*ctypes.ResultTx, error) {
+		return c.RPCClient.Tx(ctx, hash, prove)
+	})
+}
+
+// UnconfirmedTxs returns the mempool transactions
+func (c *RPCClient) UnconfirmedTxs(ctx context.Context, limit *int) (*ctypes.ResultUnconfirmedTxs, error) {
+	return observe(c, "unconfirmed_txs", func() (*ctypes.ResultUnconfirmedTxs, error) {
+		return c.RPCClient.UnconfirmedTxs(ctx, limit)
+	})
+}
+
+// BroadcastTxSync broadcasts a transaction
+func (c *RPCClient) BroadcastTxSync(ctx context.Context, tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return observe(c, "broadcast_tx_sync", func() (*ctypes.ResultBroadcastTx, error) {
+		return c.RPCClient.BroadcastTxSync(ctx, tx)
+	})
+}
+
+// Account returns the account at a height
+func (c *RPCClient) Account(ctx context.Context, addr sdk.AccAddress, height int64) (authtypes.AccountI, error) {
+	return observe(c, "account", func() (authtypes.AccountI, error) {
+		return c.RPCClient.Account(ctx, addr, height)
+	})
+}
+
+// Balance returns the balance at a height
+func (c *RPCClient) Balance(ctx context.Context, addr sdk.AccAddress, height int64) (sdk.Coins, error) {
+	return observe(c, "balance", func() (sdk.Coins, error) {
+		return c.RPCClient.Balance(ctx, addr, height)
+	})
+}
+
+// Delegations returns the delegations at a height
+func (c *RPCClient) Delegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.DelegationResponses, error) {
+	return observe(c, "delegations", func() (stakingtypes.DelegationResponses, error) {
+		return c.RPCClient.Delegations(ctx, addr, height)
+	})
+}
+
+// UnbondingDelegations returns the unbonding delegations at a height
+func (c *RPCClient) UnbondingDelegations(ctx context.Context, addr sdk.AccAddress, height int64) (stakingtypes.UnbondingDelegations, error) {
+	return observe(c, "unbonding_delegations", func() (stakingtypes.UnbondingDelegations, error) {
+		return c.RPCClient.UnbondingDelegations(ctx, addr, height)
+	})
+}
+
+// StakingValidators returns the staking validators at a height
+func (c *RPCClient) StakingValidators(ctx context.Context, status string, height int64) (stakingtypes.Validators, error) {
+	return observe(c, "staking_validators", func() (stakingtypes.Validators, error) {
+		return c.RPCClient.StakingValidators(ctx, status, height)
+	})
+}
+
+// DelegationRewards returns the delegation rewards at a height
+func (c *RPCClient) DelegationRewards(ctx context.Context, addr sdk.AccAddress, height int64) (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+	return observe(c, "delegation_rewards", func() (*distrtypes.QueryDelegationTotalRewardsResponse, error) {
+		return c.RPCClient.DelegationRewards(ctx, addr, height)
+	})
+}
+
+// ERC20Balance returns the erc20 balance of a holder at a height
+func (c *RPCClient) ERC20Balance(ctx context.Context, contract common.Address, holder common.Address, height int64) (*big.Int, error) {
+	return observe(c, "erc20_balance", func() (*big.Int, error) {
+		return c.RPCClient.ERC20Balance(ctx, contract, holder, height)
+	})
+}
+
+// DenomsMetadata returns the denom metadata at a height
+func (c *RPCClient) DenomsMetadata(ctx context.Context, height int64) ([]banktypes.Metadata, error) {
+	return observe(c, "denoms_metadata", func() ([]banktypes.Metadata, error) {
+		return c.RPCClient.DenomsMetadata(ctx, height)
+	})
+}
+
+// DenomTraces returns the ibc denom traces at a height
+func (c *RPCClient) DenomTraces(ctx context.Context, height int64) (ibctransfertypes.Traces, error) {
+	return observe(c, "denom_traces", func() (ibctransfertypes.Traces, error) {
+		return c.RPCClient.DenomTraces(ctx, height)
+	})
+}
+
+// SimulateTx simulates a transaction
+func (c *RPCClient) SimulateTx(ctx context.Context, tx authsigning.Tx) (*sdk.SimulationResponse, error) {
+	return observe(c, "simulate_tx", func() (*sdk.SimulationResponse, error) {
+		return c.RPCClient.SimulateTx(ctx, tx)
+	})
+}
@@ -5,14 +5,22 @@ package services
 import (
 	context "context"
 
+	kava "github.com/kava-labs/rosetta-kava/kava"
+
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+
 	mock "github.com/stretchr/testify/mock"
 
 	rosetta_sdk_gotypes "github.com/coinbase/rosetta-sdk-go/types"
 
 	signing "github.com/cosmos/cosmos-sdk/x/auth/signing"
 
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	distributiontypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+
 	types "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -111,6 +119,96 @@ func (_m *Client) Block(_a0 context.Context, _a1 *rosetta_sdk_gotypes.PartialBlo
 	return r0, r1
 }
 
+// BlockTransaction provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) BlockTransaction(_a0 context.Context, _a1 *rosetta_sdk_gotypes.BlockIdentifier, _a2 *rosetta_sdk_gotypes.TransactionIdentifier) (*rosetta_sdk_gotypes.Transaction, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlockTransaction")
+	}
+
+	var r0 *rosetta_sdk_gotypes.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *rosetta_sdk_gotypes.BlockIdentifier, *rosetta_sdk_gotypes.TransactionIdentifier) (*rosetta_sdk_gotypes.Transaction, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *rosetta_sdk_gotypes.BlockIdentifier, *rosetta_sdk_gotypes.TransactionIdentifier) *rosetta_sdk_gotypes.Transaction); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rosetta_sdk_gotypes.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *rosetta_sdk_gotypes.BlockIdentifier, *rosetta_sdk_gotypes.TransactionIdentifier) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DelegationRewards provides a mock function with given fields: _a0, _a1
+func (_m *Client) DelegationRewards(_a0 context.Context, _a1 types.AccAddress) (*distributiontypes.QueryDelegationTotalRewardsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DelegationRewards")
+	}
+
+	var r0 *distributiontypes.QueryDelegationTotalRewardsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress) (*distributiontypes.QueryDelegationTotalRewardsResponse, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress) *distributiontypes.QueryDelegationTotalRewardsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*distributiontypes.QueryDelegationTotalRewardsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delegations provides a mock function with given fields: _a0, _a1
+func (_m *Client) Delegations(_a0 context.Context, _a1 types.AccAddress) (stakingtypes.DelegationResponses, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delegations")
+	}
+
+	var r0 stakingtypes.DelegationResponses
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress) (stakingtypes.DelegationResponses, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress) stakingtypes.DelegationResponses); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(stakingtypes.DelegationResponses)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EstimateGas provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) EstimateGas(_a0 context.Context, _a1 signing.Tx, _a2 float64) (uint64, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -139,6 +237,66 @@ func (_m *Client) EstimateGas(_a0 context.Context, _a1 signing.Tx, _a2 float64)
 	return r0, r1
 }
 
+// Mempool provides a mock function with given fields: _a0
+func (_m *Client) Mempool(_a0 context.Context) ([]*rosetta_sdk_gotypes.TransactionIdentifier, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Mempool")
+	}
+
+	var r0 []*rosetta_sdk_gotypes.TransactionIdentifier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*rosetta_sdk_gotypes.TransactionIdentifier, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*rosetta_sdk_gotypes.TransactionIdentifier); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*rosetta_sdk_gotypes.TransactionIdentifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MempoolTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Client) MempoolTransaction(_a0 context.Context, _a1 *rosetta_sdk_gotypes.TransactionIdentifier) (*rosetta_sdk_gotypes.Transaction, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MempoolTransaction")
+	}
+
+	var r0 *rosetta_sdk_gotypes.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) (*rosetta_sdk_gotypes.Transaction, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) *rosetta_sdk_gotypes.Transaction); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rosetta_sdk_gotypes.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PostTx provides a mock function with given fields: ctx, txBytes
 func (_m *Client) PostTx(ctx context.Context, txBytes []byte) (*rosetta_sdk_gotypes.TransactionIdentifier, error) {
 	ret := _m.Called(ctx, txBytes)
@@ -169,6 +327,36 @@ func (_m *Client) PostTx(ctx context.Context, txBytes []byte) (*rosetta_sdk_goty
 	return r0, r1
 }
 
+// SimulateTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Client) SimulateTransaction(_a0 context.Context, _a1 signing.Tx) (*kava.SimulationResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateTransaction")
+	}
+
+	var r0 *kava.SimulationResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, signing.Tx) (*kava.SimulationResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, signing.Tx) *kava.SimulationResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*kava.SimulationResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, signing.Tx) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Status provides a mock function with given fields: _a0
 func (_m *Client) Status(_a0 context.Context) (*rosetta_sdk_gotypes.BlockIdentifier, int64, *rosetta_sdk_gotypes.BlockIdentifier, *rosetta_sdk_gotypes.SyncStatus, []*rosetta_sdk_gotypes.Peer, error) {
 	ret := _m.Called(_a0)
@@ -233,6 +421,135 @@ func (_m *Client) Status(_a0 context.Context) (*rosetta_sdk_gotypes.BlockIdentif
 	return r0, r1, r2, r3, r4, r5
 }
 
+// SyncInfo provides a mock function with given fields: _a0
+func (_m *Client) SyncInfo(_a0 context.Context) (*coretypes.SyncInfo, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncInfo")
+	}
+
+	var r0 *coretypes.SyncInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*coretypes.SyncInfo, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *coretypes.SyncInfo); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.SyncInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transaction provides a mock function with given fields: _a0, _a1
+func (_m *Client) Transaction(_a0 context.Context, _a1 *rosetta_sdk_gotypes.TransactionIdentifier) (*rosetta_sdk_gotypes.BlockIdentifier, *rosetta_sdk_gotypes.Transaction, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transaction")
+	}
+
+	var r0 *rosetta_sdk_gotypes.BlockIdentifier
+	var r1 *rosetta_sdk_gotypes.Transaction
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) (*rosetta_sdk_gotypes.BlockIdentifier, *rosetta_sdk_gotypes.Transaction, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) *rosetta_sdk_gotypes.BlockIdentifier); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rosetta_sdk_gotypes.BlockIdentifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) *rosetta_sdk_gotypes.Transaction); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*rosetta_sdk_gotypes.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *rosetta_sdk_gotypes.TransactionIdentifier) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UnbondingDelegations provides a mock function with given fields: _a0, _a1
+func (_m *Client) UnbondingDelegations(_a0 context.Context, _a1 types.AccAddress) (stakingtypes.UnbondingDelegations, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnbondingDelegations")
+	}
+
+	var r0 stakingtypes.UnbondingDelegations
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress) (stakingtypes.UnbondingDelegations, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.AccAddress) stakingtypes.UnbondingDelegations); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(stakingtypes.UnbondingDelegations)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.AccAddress) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Validators provides a mock function with given fields: _a0, _a1
+func (_m *Client) Validators(_a0 context.Context, _a1 string) (stakingtypes.Validators, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validators")
+	}
+
+	var r0 stakingtypes.Validators
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (stakingtypes.Validators, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) stakingtypes.Validators); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(stakingtypes.Validators)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
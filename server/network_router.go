@@ -0,0 +1,58 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// networkRouter dispatches each request to the router of the network in the request body.
+// Requests without a network identifier, such as /network/list, and requests for unknown
+// networks are served by the primary router, whose asserter rejects unknown networks.
+type networkRouter struct {
+	primary http.Handler
+	routers map[string]http.Handler
+}
+
+// networkRequest decodes the network identifier common to rosetta requests
+type networkRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+}
+
+func (n *networkRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router := n.primary
+
+	if r.Method == http.MethodPost && r.Body != nil {
+		bz, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+
+		// the body is always restored so that invalid requests are rejected by the asserter
+		r.Body = io.NopCloser(bytes.NewReader(bz))
+
+		var request networkRequest
+		if err == nil && json.Unmarshal(bz, &request) == nil && request.NetworkIdentifier != nil {
+			if networkRouter, ok := n.routers[request.NetworkIdentifier.Network]; ok {
+				router = networkRouter
+			}
+		}
+	}
+
+	router.ServeHTTP(w, r)
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoHandler responds with its name and the request body
+func echoHandler(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bz, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(name + ":" + string(bz)))
+	})
+}
+
+func TestNetworkRouter(t *testing.T) {
+	router := &networkRouter{
+		primary: echoHandler("mainnet"),
+		routers: map[string]http.Handler{
+			"kava-mainnet": echoHandler("mainnet"),
+			"kava-testnet": echoHandler("testnet"),
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		method   string
+		body     string
+		expected string
+	}{
+		{
+			name:     "additional network",
+			method:   http.MethodPost,
+			body:     `{"network_identifier":{"blockchain":"Kava","network":"kava-testnet"}}`,
+			expected: "testnet",
+		},
+		{
+			name:     "primary network",
+			method:   http.MethodPost,
+			body:     `{"network_identifier":{"blockchain":"Kava","network":"kava-mainnet"}}`,
+			expected: "mainnet",
+		},
+		{
+			name:     "unknown network",
+			method:   http.MethodPost,
+			body:     `{"network_identifier":{"blockchain":"Kava","network":"kava-devnet"}}`,
+			expected: "mainnet",
+		},
+		{
+			name:     "no network identifier",
+			method:   http.MethodPost,
+			body:     `{"metadata":{}}`,
+			expected: "mainnet",
+		},
+		{
+			name:     "invalid json",
+			method:   http.MethodPost,
+			body:     `{"network_identifier":`,
+			expected: "mainnet",
+		},
+		{
+			name:     "options request",
+			method:   http.MethodOptions,
+			expected: "mainnet",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tc.method, "/network/status", strings.NewReader(tc.body)))
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			// the body is passed on unchanged to the selected router
+			assert.Equal(t, tc.expected+":"+tc.body, rec.Body.String())
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -22,47 +23,51 @@ import (
 
 	"github.com/kava-labs/rosetta-kava/configuration"
 	"github.com/kava-labs/rosetta-kava/kava"
+	"github.com/kava-labs/rosetta-kava/metrics"
 	"github.com/kava-labs/rosetta-kava/services"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	sdkserver "github.com/coinbase/rosetta-sdk-go/server"
-	"github.com/coinbase/rosetta-sdk-go/types"
+	tmclient "github.com/cometbft/cometbft/rpc/client"
 )
 
 const (
-	// readTimeout is the maximum duration for reading the entire
-	// request, including the body.
-	readTimeout = 5 * time.Second
-
-	// writeTimeout is the maximum duration before timing out
-	// writes of the response. It is reset whenever a new
-	// request's header is read.
-	writeTimeout = 120 * time.Second
-
-	// idleTimeout is the maximum amount of time to wait for the
-	// next request when keep-alives are enabled.
-	idleTimeout = 30 * time.Second
-)
+	// statusRetryInterval is the time to wait between attempts to
+	// fetch the node status when verifying the configured chain id.
+	statusRetryInterval = 5 * time.Second
 
-// NewRouter returns an rossetta server handler with assertion, logging and cors support
-func NewRouter(config *configuration.Configuration) (http.Handler, error) {
-	http, err := kava.NewHTTPClient(config.KavaRPCURL)
-	if err != nil {
-		return nil, fmt.Errorf("%w: could not initialize http client", err)
-	}
+	// startupTimeout is the maximum time to wait for the nodes of every network to
+	// verify their chain id and load currencies before the server fails to start.
+	startupTimeout = 2 * time.Minute
 
-	accountBalanceFactory := kava.NewRPCBalanceFactory(http)
+	// rpcHealthCheckInterval is the time between health checks of
+	// rpc endpoints when failover urls are configured.
+	rpcHealthCheckInterval = 10 * time.Second
 
-	client, err := kava.NewClient(http, accountBalanceFactory)
-	if err != nil {
-		return nil, fmt.Errorf("%w: could not initialize kava client", err)
-	}
+	// nodeMetricsInterval is the time between node status requests
+	// updating the latest block height and sync lag metrics.
+	nodeMetricsInterval = 15 * time.Second
+
+	// metricsPath is the path serving prometheus metrics
+	metricsPath = "/metrics"
+
+	// healthzPath is the path serving the liveness probe
+	healthzPath = "/healthz"
+
+	// readyzPath is the path serving the readiness probe
+	readyzPath = "/readyz"
+)
+
+// NewRouter returns an rossetta server handler with assertion, logging, cors, metrics and health check support.
+// Requests are dispatched to the services of the network in the request.
+func NewRouter(config *configuration.Configuration) (http.Handler, error) {
+	m := metrics.New()
 
-	// The asserter automatically rejects incorrectly formatted requests.
+	// The asserter automatically rejects incorrectly formatted requests and unknown networks.
 	asserter, err := asserter.NewServer(
 		kava.OperationTypes,
 		kava.HistoricalBalanceSupported,
-		[]*types.NetworkIdentifier{config.NetworkIdentifier},
+		config.NetworkIdentifiers(),
 		kava.CallMethods,
 		kava.IncludeMempoolCoins,
 		"",
@@ -71,12 +76,194 @@ func NewRouter(config *configuration.Configuration) (http.Handler, error) {
 		return nil, fmt.Errorf("%w: could not initialize server asserter", err)
 	}
 
-	router := services.NewBlockchainRouter(config, client, asserter)
+	routers := make(map[string]http.Handler)
+	clients := make(map[string]services.Client)
+
+	// a node that does not respond fails startup instead of blocking it indefinitely
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	for _, networkConfig := range config.Networks() {
+		network := networkConfig.NetworkIdentifier.Network
+
+		client, registry, err := newClient(ctx, networkConfig, m)
+		if err != nil {
+			return nil, fmt.Errorf("%w: network %s", err, network)
+		}
+
+		m.ObserveCaches(network, client.CacheStats)
+
+		routers[network] = services.NewBlockchainRouter(networkConfig, client, registry, asserter)
+		clients[network] = client
+	}
+
+	router := routers[config.NetworkIdentifier.Network]
+	if len(routers) > 1 {
+		router = &networkRouter{primary: router, routers: routers}
+	}
 
 	loggedRouter := sdkserver.LoggerMiddleware(router)
 	corsRouter := sdkserver.CorsMiddleware(loggedRouter)
 
-	return corsRouter, nil
+	health := services.NewHealthService(config, clients)
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, m.Handler())
+	mux.HandleFunc(http.MethodGet+" "+healthzPath, health.Healthz)
+	mux.HandleFunc(http.MethodGet+" "+readyzPath, health.Readyz)
+	mux.Handle("/", m.Middleware(corsRouter))
+
+	return mux, nil
+}
+
+// newClient returns a kava client for the rpc endpoints of a network and the currency registry of
+// the network, loading currencies from the chain and verifying the chain id in online mode until
+// ctx is done
+func newClient(ctx context.Context, config *configuration.Configuration, m *metrics.Metrics) (*kava.Client, *kava.CurrencyRegistry, error) {
+	httpClient, err := kava.NewHTTPClientWithTimeout(config.KavaRPCURL, config.KavaRPCTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: could not initialize http client", err)
+	}
+
+	var rpc kava.RPCClient = httpClient
+	if len(config.KavaRPCFailoverURLs) > 0 {
+		failover, err := newFailoverRPCClient(httpClient, config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// endpoints are only used once they report the chain id, so each endpoint is
+		// checked before the chain id is verified
+		if config.Mode == configuration.Online {
+			failover.UpdateHealth(ctx)
+			go failover.MonitorHealth(context.Background(), rpcHealthCheckInterval)
+		}
+
+		m.ObserveRPCEndpoints(config.NetworkIdentifier.Network, failover.EndpointStatuses)
+
+		rpc = failover
+	}
+
+	// calls are instrumented before caching so cache hits are not recorded as rpc calls
+	rpc = metrics.NewRPCClient(rpc, m, config.NetworkIdentifier.Network)
+
+	if config.Mode == configuration.Online {
+		go m.MonitorNode(context.Background(), rpc, nodeMetricsInterval)
+	}
+
+	if config.BlockCacheSize > 0 {
+		rpc, err = kava.NewCachedRPCClient(rpc, config.BlockCacheSize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: could not initialize block cache", err)
+		}
+	}
+
+	accountBalanceFactory := kava.NewRPCBalanceFactory(rpc)
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+
+	client, err := kava.NewClient(
+		rpc,
+		accountBalanceFactory,
+		kava.WithCurrencyRegistry(registry),
+		kava.WithLenientParsing(config.LenientParsing),
+		kava.WithBlockResponseCache(config.BlockCacheSize),
+		kava.WithMempoolTxLimit(config.MempoolTxLimit),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: could not initialize kava client", err)
+	}
+
+	// The chain id is verified before the server starts, waiting for the node to respond,
+	// so that transactions are never signed for the wrong chain.  With failover urls, the
+	// failover client has already removed endpoints of another chain from rotation.
+	if config.Mode == configuration.Online {
+		if err := verifyChainID(ctx, rpc, config.ChainID, statusRetryInterval); err != nil {
+			return nil, nil, fmt.Errorf("%w: refusing to sign transactions for the wrong chain", err)
+		}
+	}
+
+	// each network has its own registry, so networks never share the denoms of another chain
+	if config.Mode == configuration.Online {
+		if err := loadDenomsMetadata(ctx, rpc, registry, statusRetryInterval); err != nil {
+			return nil, nil, fmt.Errorf("%w: could not load currencies", err)
+		}
+	}
+
+	// currencies from configuration take precedence over those loaded from the chain
+	if err := kava.RegisterCurrencies(registry, config.Currencies); err != nil {
+		return nil, nil, fmt.Errorf("%w: could not register configured currencies", err)
+	}
+
+	if err := kava.RegisterERC20Contracts(registry, config.ERC20Contracts); err != nil {
+		return nil, nil, fmt.Errorf("%w: could not register erc20 contracts", err)
+	}
+
+	return client, registry, nil
+}
+
+// newFailoverRPCClient returns a failover client using the primary http client followed by
+// a http client for each failover url, using only endpoints reporting the configured chain id
+func newFailoverRPCClient(primary *kava.HTTPClient, config *configuration.Configuration) (*kava.FailoverRPCClient, error) {
+	endpoints := []kava.RPCEndpoint{{URL: config.KavaRPCURL, Client: primary}}
+
+	for _, url := range config.KavaRPCFailoverURLs {
+		client, err := kava.NewHTTPClientWithTimeout(url, config.KavaRPCTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not initialize http client for %s", err, url)
+		}
+
+		endpoints = append(endpoints, kava.RPCEndpoint{URL: url, Client: client})
+	}
+
+	failover, err := kava.NewFailoverRPCClient(endpoints, config.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not initialize failover client", err)
+	}
+
+	return failover, nil
+}
+
+// verifyChainID waits for the node to report its status and returns an error if the node's
+// network does not match the configured chain id, or if ctx is done before the node responds
+func verifyChainID(ctx context.Context, client tmclient.StatusClient, chainID string, retryInterval time.Duration) error {
+	for {
+		status, err := client.Status(ctx)
+		if err == nil {
+			if status.NodeInfo.Network != chainID {
+				return fmt.Errorf("configured chain id %s does not match node network %s", chainID, status.NodeInfo.Network)
+			}
+
+			return nil
+		}
+
+		log.Printf("unable to fetch node status to verify chain id: %s", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: unable to fetch node status: %s", ctx.Err(), err)
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// loadDenomsMetadata registers currencies from the chain denom metadata, waiting for the node
+// to respond so that currencies do not change after the server has started.  An error is
+// returned if ctx is done before the currencies are loaded.
+func loadDenomsMetadata(ctx context.Context, rpc kava.RPCClient, registry *kava.CurrencyRegistry, retryInterval time.Duration) error {
+	for {
+		err := kava.LoadDenomsMetadata(ctx, rpc, registry)
+		if err == nil {
+			return nil
+		}
+
+		log.Printf("unable to load currencies: %s", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s", ctx.Err(), err)
+		case <-time.After(retryInterval):
+		}
+	}
 }
 
 // Run starts a http server using the provided handler with read, write, and idle timeouts
@@ -84,9 +271,9 @@ func Run(config *configuration.Configuration, handler http.Handler) error {
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", config.Port),
 		Handler:      handler,
-		ReadTimeout:  readTimeout,
-		WriteTimeout: writeTimeout,
-		IdleTimeout:  idleTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+		IdleTimeout:  config.IdleTimeout,
 	}
 
 	log.Printf("server listening on port %d", config.Port)
@@ -15,13 +15,29 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
 	"github.com/kava-labs/rosetta-kava/kava"
+	"github.com/kava-labs/rosetta-kava/kava/mocks"
+	"github.com/kava-labs/rosetta-kava/metrics"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/cometbft/cometbft/p2p"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -39,6 +55,181 @@ func TestRouter(t *testing.T) {
 		KavaRPCURL:        "https://rpc.testnet.kava.io:443",
 	}
 
-	_, err := NewRouter(config)
+	router, err := NewRouter(config)
+	require.NoError(t, err)
+
+	for _, path := range []string{healthzPath, readyzPath, metricsPath} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestRouter_AdditionalNetworks(t *testing.T) {
+	mainnetIdentifier := &types.NetworkIdentifier{
+		Blockchain: kava.Blockchain,
+		Network:    "kava-mainnet",
+	}
+	config := &configuration.Configuration{
+		Mode:              configuration.Offline,
+		NetworkIdentifier: networkIdentifier,
+		Port:              8000,
+		KavaRPCURL:        "https://rpc.testnet.kava.io:443",
+		AdditionalNetworks: []*configuration.Configuration{
+			{
+				Mode:              configuration.Offline,
+				NetworkIdentifier: mainnetIdentifier,
+				Port:              8000,
+				KavaRPCURL:        "https://rpc.kava.io:443",
+			},
+		},
+	}
+
+	router, err := NewRouter(config)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/network/list", strings.NewReader(`{}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var networkList types.NetworkListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &networkList))
+	assert.Equal(t, []*types.NetworkIdentifier{networkIdentifier, mainnetIdentifier}, networkList.NetworkIdentifiers)
+
+	// unknown networks are rejected by the asserter
+	rec = httptest.NewRecorder()
+	body := `{"network_identifier":{"blockchain":"Kava","network":"kava-devnet"}}`
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/network/options", strings.NewReader(body)))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestNewClient_CurrencyRegistry(t *testing.T) {
+	m := metrics.New()
+	testnetCurrency := &types.Currency{Symbol: "TUSD", Decimals: 6}
+	testnetContract := "0x919C1c267BC06a7039e03fcc2eF738525769109c"
+
+	_, testnetRegistry, err := newClient(context.Background(), &configuration.Configuration{
+		Mode:              configuration.Offline,
+		NetworkIdentifier: networkIdentifier,
+		KavaRPCURL:        "https://rpc.testnet.kava.io:443",
+		Currencies:        map[string]*types.Currency{"tusd": testnetCurrency},
+		ERC20Contracts:    map[string]*types.Currency{testnetContract: {Symbol: "USDt", Decimals: 6}},
+	}, m)
+	require.NoError(t, err)
+
+	_, mainnetRegistry, err := newClient(context.Background(), &configuration.Configuration{
+		Mode:              configuration.Offline,
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: kava.Blockchain, Network: "kava-mainnet"},
+		KavaRPCURL:        "https://rpc.kava.io:443",
+	}, m)
+	require.NoError(t, err)
+
+	// configured currencies are only registered for the network they are configured for
+	denom, ok := testnetRegistry.Denom(testnetCurrency)
+	require.True(t, ok)
+	assert.Equal(t, "tusd", denom)
+
+	_, ok = mainnetRegistry.Denom(testnetCurrency)
+	assert.False(t, ok)
+
+	// erc20 contracts are only registered for the network they are configured for
+	_, ok = testnetRegistry.ERC20Currency(common.HexToAddress(testnetContract))
+	assert.True(t, ok)
+
+	_, ok = mainnetRegistry.ERC20Currency(common.HexToAddress(testnetContract))
+	assert.False(t, ok)
+}
+
+func TestRouter_ChainIDMismatch(t *testing.T) {
+	// node responding to status requests with a network that does not match the chain id
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(request.ID) + `,"result":{"node_info":{"network":"kava_2222-10"}}}`))
+		require.NoError(t, err)
+	}))
+	defer node.Close()
+
+	config := &configuration.Configuration{
+		Mode:              configuration.Online,
+		NetworkIdentifier: networkIdentifier,
+		ChainID:           "kava_2221-16000",
+		Port:              8000,
+		KavaRPCURL:        node.URL,
+		KavaRPCTimeout:    time.Second,
+	}
+
+	router, err := NewRouter(config)
+	assert.Nil(t, router)
+	assert.EqualError(t, err, "configured chain id kava_2221-16000 does not match node network kava_2222-10: refusing to sign transactions for the wrong chain: network kava-testnet")
+}
+
+type testStatusClient struct {
+	network  string
+	failures int
+}
+
+func (c *testStatusClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	if c.failures > 0 {
+		c.failures--
+		return nil, errors.New("connection refused")
+	}
+
+	return &ctypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: c.network}}, nil
+}
+
+func TestVerifyChainID(t *testing.T) {
+	ctx := context.Background()
+
+	err := verifyChainID(ctx, &testStatusClient{network: "kava_2221-16000"}, "kava_2221-16000", time.Millisecond)
+	assert.NoError(t, err)
+
+	err = verifyChainID(ctx, &testStatusClient{network: "kava_2221-16000", failures: 2}, "kava_2221-16000", time.Millisecond)
 	assert.NoError(t, err)
+
+	err = verifyChainID(ctx, &testStatusClient{network: "kava_2222-10"}, "kava_2221-16000", time.Millisecond)
+	assert.EqualError(t, err, "configured chain id kava_2221-16000 does not match node network kava_2222-10")
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = verifyChainID(cancelledCtx, &testStatusClient{failures: 1}, "kava_2221-16000", time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// a node that never responds does not block startup past the deadline
+	deadlineCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err = verifyChainID(deadlineCtx, &testStatusClient{failures: math.MaxInt}, "kava_2221-16000", time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "context deadline exceeded: unable to fetch node status: connection refused")
+}
+
+func TestLoadDenomsMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	rpc := &mocks.RPCClient{}
+	rpc.On("DenomsMetadata", ctx, int64(0)).Return([]banktypes.Metadata{
+		{Base: "hard", Display: "HARD", DenomUnits: []*banktypes.DenomUnit{{Denom: "HARD", Exponent: 6}}},
+	}, nil).Once()
+	rpc.On("DenomTraces", ctx, int64(0)).Return(ibctransfertypes.Traces{}, nil).Once()
+
+	registry := kava.NewCurrencyRegistry(map[string]*types.Currency{})
+	err := loadDenomsMetadata(ctx, rpc, registry, time.Millisecond)
+	require.NoError(t, err)
+
+	_, ok := registry.Currency("hard")
+	assert.True(t, ok)
+
+	// currencies that can not be loaded before the deadline fail startup
+	deadlineCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	rpc = &mocks.RPCClient{}
+	rpc.On("DenomsMetadata", deadlineCtx, int64(0)).Return(nil, errors.New("connection refused"))
+
+	err = loadDenomsMetadata(deadlineCtx, rpc, kava.NewCurrencyRegistry(map[string]*types.Currency{}), time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
 }
@@ -21,7 +21,6 @@ import (
 	"context"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
-	"github.com/kava-labs/rosetta-kava/kava"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
@@ -54,13 +53,7 @@ func (s *BlockAPIService) Block(
 
 	blockReponse, err := s.client.Block(ctx, request.BlockIdentifier)
 	if err != nil {
-		rErr := wrapErr(ErrKava, err)
-
-		if kava.IsRetriableError(err) {
-			rErr.Retriable = true
-		}
-
-		return nil, rErr
+		return nil, wrapClientErr(err)
 	}
 
 	return blockReponse, nil
@@ -75,5 +68,16 @@ func (s *BlockAPIService) BlockTransaction(
 		return nil, ErrUnavailableOffline
 	}
 
-	return nil, ErrUnimplemented
+	transaction, err := s.client.BlockTransaction(
+		ctx,
+		request.BlockIdentifier,
+		request.TransactionIdentifier,
+	)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	return &types.BlockTransactionResponse{
+		Transaction: transaction,
+	}, nil
 }
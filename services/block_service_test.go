@@ -18,9 +18,11 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 	mocks "github.com/kava-labs/rosetta-kava/mocks/services"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -129,10 +131,51 @@ func TestBlockService_Online(t *testing.T) {
 	assert.Equal(t, ErrKava.Retriable, false)
 	assert.Equal(t, kavaErr.Error(), err.Details["context"])
 
-	blockTransaction, err := servicer.BlockTransaction(ctx, &types.BlockTransactionRequest{})
+	transactionIdentifier := &types.TransactionIdentifier{
+		Hash: "6E4B4A1B4D5E0BB9BB4E5DCA0D3A46A20A5BE3EB0E1A18F4F3B9F4C3A0E3F3A6",
+	}
+	transaction := &types.Transaction{
+		TransactionIdentifier: transactionIdentifier,
+		Operations:            []*types.Operation{},
+	}
+
+	mockClient.On(
+		"BlockTransaction",
+		ctx,
+		blockResponse.Block.BlockIdentifier,
+		transactionIdentifier,
+	).Return(
+		transaction,
+		nil,
+	).Once()
+
+	blockTransaction, err := servicer.BlockTransaction(ctx, &types.BlockTransactionRequest{
+		NetworkIdentifier:     networkIdentifier,
+		BlockIdentifier:       blockResponse.Block.BlockIdentifier,
+		TransactionIdentifier: transactionIdentifier,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, transaction, blockTransaction.Transaction)
+
+	mockClient.On(
+		"BlockTransaction",
+		ctx,
+		blockResponse.Block.BlockIdentifier,
+		transactionIdentifier,
+	).Return(
+		nil,
+		kavaErr,
+	).Once()
+
+	blockTransaction, err = servicer.BlockTransaction(ctx, &types.BlockTransactionRequest{
+		NetworkIdentifier:     networkIdentifier,
+		BlockIdentifier:       blockResponse.Block.BlockIdentifier,
+		TransactionIdentifier: transactionIdentifier,
+	})
 	assert.Nil(t, blockTransaction)
-	assert.Equal(t, ErrUnimplemented.Code, err.Code)
-	assert.Equal(t, ErrUnimplemented.Message, err.Message)
+	assert.Equal(t, ErrKava.Code, err.Code)
+	assert.Equal(t, ErrKava.Message, err.Message)
+	assert.Equal(t, kavaErr.Error(), err.Details["context"])
 
 	abciErr := tmstate.ErrNoABCIResponsesForHeight{Height: 10001}
 	kavaErr = tmrpctypes.RPCInternalError(tmrpctypes.JSONRPCIntID(1), abciErr).Error
@@ -170,5 +213,49 @@ func TestBlockService_Online(t *testing.T) {
 	// errors are not retriable
 	assert.Equal(t, err.Retriable, false, "expected error to not be retriable")
 
+	parseErr := &kava.TxParseError{
+		Height:  100,
+		TxIndex: 2,
+		TxHash:  transactionIdentifier.Hash,
+		Err:     errors.New("unable to unmarshal transaction"),
+	}
+	mockClient.On(
+		"Block",
+		ctx,
+		blockIdentifier,
+	).Return(
+		nil,
+		fmt.Errorf("wrapped: %w", parseErr),
+	).Once()
+
+	_, err = servicer.Block(ctx, &types.BlockRequest{
+		NetworkIdentifier: networkIdentifier,
+		BlockIdentifier:   blockIdentifier,
+	})
+	assert.Equal(t, ErrTxParse.Code, err.Code)
+	assert.Equal(t, ErrTxParse.Message, err.Message)
+	assert.False(t, err.Retriable)
+	assert.Equal(t, int64(100), err.Details["block_height"])
+	assert.Equal(t, 2, err.Details["tx_index"])
+	assert.Equal(t, transactionIdentifier.Hash, err.Details["tx_hash"])
+
+	mockClient.On(
+		"BlockTransaction",
+		ctx,
+		blockResponse.Block.BlockIdentifier,
+		transactionIdentifier,
+	).Return(
+		nil,
+		parseErr,
+	).Once()
+
+	_, err = servicer.BlockTransaction(ctx, &types.BlockTransactionRequest{
+		NetworkIdentifier:     networkIdentifier,
+		BlockIdentifier:       blockResponse.Block.BlockIdentifier,
+		TransactionIdentifier: transactionIdentifier,
+	})
+	assert.Equal(t, ErrTxParse.Code, err.Code)
+	assert.Equal(t, parseErr.Error(), err.Details["context"])
+
 	mockClient.AssertExpectations(t)
 }
@@ -18,25 +18,59 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	vestingexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/kava-labs/kava/app/params"
 )
 
+// callHandler implements a single call method
+type callHandler func(context.Context, map[string]interface{}) (*types.CallResponse, *types.Error)
+
 // CallAPIService implements the server.CallAPIServicer interface.
 type CallAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config         *configuration.Configuration
+	client         Client
+	encodingConfig params.EncodingConfig
+	handlers       map[string]callHandler
 }
 
 // NewCallAPIService creates a new instance of a CallAPIService.
-func NewCallAPIService(cfg *configuration.Configuration, client Client) *CallAPIService {
-	return &CallAPIService{
-		config: cfg,
-		client: client,
+func NewCallAPIService(
+	cfg *configuration.Configuration,
+	client Client,
+	encodingConfig params.EncodingConfig,
+) *CallAPIService {
+	s := &CallAPIService{
+		config:         cfg,
+		client:         client,
+		encodingConfig: encodingConfig,
+	}
+
+	s.handlers = map[string]callHandler{
+		kava.AccountCallMethod:              s.account,
+		kava.DelegationsCallMethod:          s.delegations,
+		kava.UnbondingDelegationsCallMethod: s.unbondingDelegations,
+		kava.ValidatorsCallMethod:           s.validators,
+		kava.StakingRewardsCallMethod:       s.stakingRewards,
+		kava.VestingScheduleCallMethod:      s.vestingSchedule,
+		kava.SimulateTransactionCallMethod:  s.simulateTransaction,
+		kava.TransactionCallMethod:          s.transaction,
 	}
+
+	return s
 }
 
 // Call implements the /call endpoint.
@@ -44,5 +78,393 @@ func (s *CallAPIService) Call(
 	ctx context.Context,
 	request *types.CallRequest,
 ) (*types.CallResponse, *types.Error) {
-	return nil, ErrUnimplemented
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	handler, ok := s.handlers[request.Method]
+	if !ok {
+		return nil, ErrUnimplemented
+	}
+
+	return handler(ctx, request.Parameters)
+}
+
+// addressCallParameters are the parameters of call methods querying a single address
+type addressCallParameters struct {
+	Address string `json:"address"`
+}
+
+// validatorsCallParameters are the parameters of the validators call method
+type validatorsCallParameters struct {
+	Status string `json:"status"`
+}
+
+// simulateTransactionCallParameters are the parameters of the simulate_transaction call method
+type simulateTransactionCallParameters struct {
+	Transaction string `json:"transaction"`
+}
+
+// transactionCallParameters are the parameters of the transaction call method
+type transactionCallParameters struct {
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+}
+
+// simulationResult is the result of the simulate_transaction call method
+type simulationResult struct {
+	Success    bool               `json:"success"`
+	GasWanted  uint64             `json:"gas_wanted"`
+	GasUsed    uint64             `json:"gas_used"`
+	Log        string             `json:"log,omitempty"`
+	Events     sdk.StringEvents   `json:"events,omitempty"`
+	Operations []*types.Operation `json:"operations"`
+	Error      *simulationError   `json:"error,omitempty"`
+}
+
+// simulationError is the abci error of a transaction that fails simulation
+type simulationError struct {
+	Codespace string `json:"codespace"`
+	Code      uint32 `json:"code"`
+	Log       string `json:"log"`
+}
+
+// vestingSchedule is the result of the vesting_schedule call method
+type vestingSchedule struct {
+	Address          string          `json:"address"`
+	VestingAccount   bool            `json:"vesting_account"`
+	OriginalVesting  sdk.Coins       `json:"original_vesting,omitempty"`
+	StartTime        int64           `json:"start_time,omitempty"`
+	EndTime          int64           `json:"end_time,omitempty"`
+	Vested           sdk.Coins       `json:"vested,omitempty"`
+	Vesting          sdk.Coins       `json:"vesting,omitempty"`
+	DelegatedFree    sdk.Coins       `json:"delegated_free,omitempty"`
+	DelegatedVesting sdk.Coins       `json:"delegated_vesting,omitempty"`
+	Periods          []vestingPeriod `json:"periods,omitempty"`
+}
+
+// vestingPeriod is a period of a periodic vesting schedule, ending at EndTime
+type vestingPeriod struct {
+	Length  int64     `json:"length"`
+	EndTime int64     `json:"end_time"`
+	Amount  sdk.Coins `json:"amount"`
+}
+
+func (s *CallAPIService) account(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	addr, rErr := decodeAddressParameter(parameters)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	account, err := s.client.Account(ctx, addr)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	bz, err := s.encodingConfig.Marshaler.MarshalInterfaceJSON(account)
+	if err != nil {
+		return nil, wrapErr(ErrKava, err)
+	}
+
+	result := map[string]interface{}{
+		"address":        account.GetAddress().String(),
+		"account_number": account.GetAccountNumber(),
+		"sequence":       account.GetSequence(),
+		"account":        json.RawMessage(bz),
+	}
+
+	return callResponse(result, false)
+}
+
+func (s *CallAPIService) delegations(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	addr, rErr := decodeAddressParameter(parameters)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	delegations, err := s.client.Delegations(ctx, addr)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	return s.protoCallResponse(&stakingtypes.QueryDelegatorDelegationsResponse{
+		DelegationResponses: delegations,
+	}, false)
+}
+
+func (s *CallAPIService) unbondingDelegations(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	addr, rErr := decodeAddressParameter(parameters)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	unbondingDelegations, err := s.client.UnbondingDelegations(ctx, addr)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	return s.protoCallResponse(&stakingtypes.QueryDelegatorUnbondingDelegationsResponse{
+		UnbondingResponses: unbondingDelegations,
+	}, false)
+}
+
+func (s *CallAPIService) validators(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	var validatorsParameters validatorsCallParameters
+	if rErr := decodeCallParameters(parameters, &validatorsParameters); rErr != nil {
+		return nil, rErr
+	}
+
+	if validatorsParameters.Status != "" {
+		if _, ok := stakingtypes.BondStatus_value[validatorsParameters.Status]; !ok {
+			return nil, wrapErr(ErrInvalidCallParameters, errors.New("invalid validator status"))
+		}
+	}
+
+	validators, err := s.client.Validators(ctx, validatorsParameters.Status)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	return s.protoCallResponse(&stakingtypes.QueryValidatorsResponse{
+		Validators: validators,
+	}, false)
+}
+
+func (s *CallAPIService) stakingRewards(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	addr, rErr := decodeAddressParameter(parameters)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	rewards, err := s.client.DelegationRewards(ctx, addr)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	return s.protoCallResponse(rewards, false)
+}
+
+// vestingSchedule returns the vesting schedule of an account, with the vested and vesting
+// coins at the current time
+func (s *CallAPIService) vestingSchedule(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	addr, rErr := decodeAddressParameter(parameters)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	account, err := s.client.Account(ctx, addr)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	schedule := vestingSchedule{Address: addr.String()}
+
+	if vacc, ok := account.(vestingexported.VestingAccount); ok {
+		now := time.Now()
+
+		schedule.VestingAccount = true
+		schedule.OriginalVesting = vacc.GetOriginalVesting()
+		schedule.StartTime = vacc.GetStartTime()
+		schedule.EndTime = vacc.GetEndTime()
+		schedule.Vested = vacc.GetVestedCoins(now)
+		schedule.Vesting = vacc.GetVestingCoins(now)
+		schedule.DelegatedFree = vacc.GetDelegatedFree()
+		schedule.DelegatedVesting = vacc.GetDelegatedVesting()
+
+		if pva, ok := vacc.(*vestingtypes.PeriodicVestingAccount); ok {
+			endTime := pva.StartTime
+			for _, period := range pva.VestingPeriods {
+				endTime += period.Length
+				schedule.Periods = append(schedule.Periods, vestingPeriod{
+					Length:  period.Length,
+					EndTime: endTime,
+					Amount:  period.Amount,
+				})
+			}
+		}
+	}
+
+	return callResponse(schedule, false)
+}
+
+// simulateTransaction simulates an unsigned or signed transaction.  Transactions without signer
+// infos are simulated with placeholder public keys and the current sequence of each signer.
+func (s *CallAPIService) simulateTransaction(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	var simulateParameters simulateTransactionCallParameters
+	if rErr := decodeCallParameters(parameters, &simulateParameters); rErr != nil {
+		return nil, rErr
+	}
+
+	txBytes, err := decodeTxBytes(simulateParameters.Transaction)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidTx, err)
+	}
+
+	tx, err := s.encodingConfig.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidTx, err)
+	}
+
+	txBuilder, err := s.encodingConfig.TxConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidTx, err)
+	}
+
+	sigsV2, err := txBuilder.GetTx().GetSignaturesV2()
+	if err != nil {
+		return nil, wrapErr(ErrInvalidTx, err)
+	}
+
+	if len(sigsV2) == 0 {
+		for _, signer := range txBuilder.GetTx().GetSigners() {
+			acc, err := s.client.Account(ctx, signer)
+			if err != nil {
+				return nil, wrapClientErr(err)
+			}
+
+			pubKey := simulationPubKey(acc.GetPubKey(), signer, nil)
+			sigsV2 = append(sigsV2, simulationSignature(pubKey, acc.GetSequence()))
+		}
+
+		if err := txBuilder.SetSignatures(sigsV2...); err != nil {
+			return nil, wrapErr(ErrInvalidTx, err)
+		}
+	}
+
+	simulation, err := s.client.SimulateTransaction(ctx, txBuilder.GetTx())
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	result := simulationResult{
+		Success:    simulation.Err == nil,
+		GasWanted:  simulation.GasInfo.GasWanted,
+		GasUsed:    simulation.GasInfo.GasUsed,
+		Log:        simulation.Log,
+		Events:     simulation.Events,
+		Operations: simulation.Operations,
+	}
+
+	if simulation.Err != nil {
+		result.Error = &simulationError{
+			Codespace: simulation.Err.Codespace,
+			Code:      simulation.Err.Code,
+			Log:       simulation.Err.Log,
+		}
+	}
+
+	return callResponse(result, false)
+}
+
+func (s *CallAPIService) transaction(
+	ctx context.Context,
+	parameters map[string]interface{},
+) (*types.CallResponse, *types.Error) {
+	var transactionParameters transactionCallParameters
+	if rErr := decodeCallParameters(parameters, &transactionParameters); rErr != nil {
+		return nil, rErr
+	}
+
+	if transactionParameters.TransactionIdentifier == nil || transactionParameters.TransactionIdentifier.Hash == "" {
+		return nil, wrapErr(ErrInvalidCallParameters, errors.New("transaction_identifier must be set"))
+	}
+
+	blockIdentifier, transaction, err := s.client.Transaction(ctx, transactionParameters.TransactionIdentifier)
+	if err != nil {
+		return nil, wrapClientErr(err)
+	}
+
+	// transactions are only returned once included in a block
+	return callResponse(map[string]interface{}{
+		"block_identifier": blockIdentifier,
+		"transaction":      transaction,
+	}, true)
+}
+
+// decodeCallParameters decodes call parameters into v, rejecting unknown parameters
+func decodeCallParameters(parameters map[string]interface{}, v interface{}) *types.Error {
+	bz, err := json.Marshal(parameters)
+	if err != nil {
+		return wrapErr(ErrInvalidCallParameters, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return wrapErr(ErrInvalidCallParameters, err)
+	}
+
+	return nil
+}
+
+// decodeAddressParameter decodes and validates the address of call methods querying a single address
+func decodeAddressParameter(parameters map[string]interface{}) (sdk.AccAddress, *types.Error) {
+	var addressParameters addressCallParameters
+	if rErr := decodeCallParameters(parameters, &addressParameters); rErr != nil {
+		return nil, rErr
+	}
+
+	addr, err := sdk.AccAddressFromBech32(addressParameters.Address)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
+
+	return addr, nil
+}
+
+// protoCallResponse returns a call response with the result encoded the same as
+// the json responses of the cosmos-sdk rest api
+func (s *CallAPIService) protoCallResponse(msg proto.Message, idempotent bool) (*types.CallResponse, *types.Error) {
+	bz, err := s.encodingConfig.Marshaler.MarshalJSON(msg)
+	if err != nil {
+		return nil, wrapErr(ErrKava, err)
+	}
+
+	return rawCallResponse(bz, idempotent)
+}
+
+// callResponse returns a call response with the json encoding of the result
+func callResponse(result interface{}, idempotent bool) (*types.CallResponse, *types.Error) {
+	bz, err := json.Marshal(result)
+	if err != nil {
+		return nil, wrapErr(ErrKava, err)
+	}
+
+	return rawCallResponse(bz, idempotent)
+}
+
+func rawCallResponse(bz []byte, idempotent bool) (*types.CallResponse, *types.Error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(bz, &result); err != nil {
+		return nil, wrapErr(ErrKava, err)
+	}
+
+	// queries of all results are not paginated
+	delete(result, "pagination")
+
+	return &types.CallResponse{
+		Result:     result,
+		Idempotent: idempotent,
+	}, nil
 }
@@ -19,43 +19,461 @@ package services
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 	mocks "github.com/kava-labs/rosetta-kava/mocks/services"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/kava-labs/kava/app"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-func TestCall_Offline(t *testing.T) {
+var callTestAddress = "kava1vlpsrmdyuywvaqrv7rx6xga224sqfwz3fyfhwq"
+
+func setupCallAPIServicer(mode configuration.Mode) (*CallAPIService, *mocks.Client) {
 	cfg := &configuration.Configuration{
-		Mode: configuration.Offline,
+		Mode: mode,
 	}
 	mockClient := &mocks.Client{}
-	servicer := NewCallAPIService(cfg, mockClient)
+	return NewCallAPIService(cfg, mockClient, app.MakeEncodingConfig()), mockClient
+}
+
+func TestCall_Offline(t *testing.T) {
+	servicer, mockClient := setupCallAPIServicer(configuration.Offline)
 	ctx := context.Background()
 
-	resp, err := servicer.Call(ctx, &types.CallRequest{})
+	resp, err := servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.AccountCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
 	assert.Nil(t, resp)
-	assert.Equal(t, ErrUnimplemented.Code, err.Code)
-	assert.Equal(t, ErrUnimplemented.Message, err.Message)
+	assert.Equal(t, ErrUnavailableOffline.Code, err.Code)
+	assert.Equal(t, ErrUnavailableOffline.Message, err.Message)
 
 	mockClient.AssertExpectations(t)
 }
 
-func TestCall_Online(t *testing.T) {
-	cfg := &configuration.Configuration{
-		Mode: configuration.Online,
-	}
-	mockClient := &mocks.Client{}
-	servicer := NewCallAPIService(cfg, mockClient)
+func TestCall_UnknownMethod(t *testing.T) {
+	servicer, mockClient := setupCallAPIServicer(configuration.Online)
 	ctx := context.Background()
 
-	resp, err := servicer.Call(ctx, &types.CallRequest{})
+	resp, err := servicer.Call(ctx, &types.CallRequest{Method: "unknown"})
 	assert.Nil(t, resp)
 	assert.Equal(t, ErrUnimplemented.Code, err.Code)
 	assert.Equal(t, ErrUnimplemented.Message, err.Message)
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestCall_Methods(t *testing.T) {
+	servicer, _ := setupCallAPIServicer(configuration.Online)
+
+	for _, method := range kava.CallMethods {
+		assert.Contains(t, servicer.handlers, method)
+	}
+	assert.Len(t, servicer.handlers, len(kava.CallMethods))
+}
+
+func TestCall_InvalidParameters(t *testing.T) {
+	testCases := []struct {
+		name        string
+		method      string
+		parameters  map[string]interface{}
+		expectedErr *types.Error
+	}{
+		{
+			name:        "missing address",
+			method:      kava.AccountCallMethod,
+			parameters:  map[string]interface{}{},
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name:        "invalid address",
+			method:      kava.DelegationsCallMethod,
+			parameters:  map[string]interface{}{"address": "kava1invalid"},
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name:        "unknown parameter",
+			method:      kava.StakingRewardsCallMethod,
+			parameters:  map[string]interface{}{"address": callTestAddress, "height": 100},
+			expectedErr: ErrInvalidCallParameters,
+		},
+		{
+			name:        "invalid parameter type",
+			method:      kava.UnbondingDelegationsCallMethod,
+			parameters:  map[string]interface{}{"address": 100},
+			expectedErr: ErrInvalidCallParameters,
+		},
+		{
+			name:        "invalid validator status",
+			method:      kava.ValidatorsCallMethod,
+			parameters:  map[string]interface{}{"status": "bonded"},
+			expectedErr: ErrInvalidCallParameters,
+		},
+		{
+			name:        "missing transaction identifier",
+			method:      kava.TransactionCallMethod,
+			parameters:  map[string]interface{}{},
+			expectedErr: ErrInvalidCallParameters,
+		},
+		{
+			name:        "invalid transaction hex",
+			method:      kava.SimulateTransactionCallMethod,
+			parameters:  map[string]interface{}{"transaction": "zz"},
+			expectedErr: ErrInvalidTx,
+		},
+		{
+			name:        "invalid transaction bytes",
+			method:      kava.SimulateTransactionCallMethod,
+			parameters:  map[string]interface{}{"transaction": "abcd"},
+			expectedErr: ErrInvalidTx,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			servicer, mockClient := setupCallAPIServicer(configuration.Online)
+
+			resp, err := servicer.Call(context.Background(), &types.CallRequest{
+				Method:     tc.method,
+				Parameters: tc.parameters,
+			})
+			assert.Nil(t, resp)
+			require.NotNil(t, err)
+			assert.Equal(t, tc.expectedErr.Code, err.Code)
+			assert.Equal(t, tc.expectedErr.Message, err.Message)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_Account(t *testing.T) {
+	servicer, mockClient := setupCallAPIServicer(configuration.Online)
+	addr := sdk.MustAccAddressFromBech32(callTestAddress)
+
+	account := authtypes.NewBaseAccount(addr, nil, 10, 5)
+	mockClient.On("Account", mock.Anything, addr).Return(account, nil).Once()
+
+	resp, err := servicer.Call(context.Background(), &types.CallRequest{
+		Method:     kava.AccountCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	require.Nil(t, err)
+	assert.False(t, resp.Idempotent)
+	assert.Equal(t, callTestAddress, resp.Result["address"])
+	assert.Equal(t, float64(10), resp.Result["account_number"])
+	assert.Equal(t, float64(5), resp.Result["sequence"])
+	assert.Equal(t, map[string]interface{}{
+		"@type":          "/cosmos.auth.v1beta1.BaseAccount",
+		"address":        callTestAddress,
+		"pub_key":        nil,
+		"account_number": "10",
+		"sequence":       "5",
+	}, resp.Result["account"])
+
+	mockClient.On("Account", mock.Anything, addr).Return(nil, errors.New("node unavailable")).Once()
+
+	resp, err = servicer.Call(context.Background(), &types.CallRequest{
+		Method:     kava.AccountCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrKava.Code, err.Code)
+	assert.Equal(t, "node unavailable", err.Details["context"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCall_Staking(t *testing.T) {
+	servicer, mockClient := setupCallAPIServicer(configuration.Online)
+	ctx := context.Background()
+	addr := sdk.MustAccAddressFromBech32(callTestAddress)
+	validatorAddress := "kavavaloper1ppj7c8tqt2e3rzqtmztsmd6ea6u3nz6qggcp5e"
+
+	mockClient.On("Delegations", mock.Anything, addr).Return(stakingtypes.DelegationResponses{
+		{
+			Delegation: stakingtypes.Delegation{
+				DelegatorAddress: callTestAddress,
+				ValidatorAddress: validatorAddress,
+				Shares:           sdk.MustNewDecFromStr("100.5"),
+			},
+			Balance: sdk.NewCoin("ukava", sdk.NewInt(100)),
+		},
+	}, nil).Once()
+
+	resp, err := servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.DelegationsCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"delegation_responses": []interface{}{
+			map[string]interface{}{
+				"delegation": map[string]interface{}{
+					"delegator_address": callTestAddress,
+					"validator_address": validatorAddress,
+					"shares":            "100.500000000000000000",
+				},
+				"balance": map[string]interface{}{"denom": "ukava", "amount": "100"},
+			},
+		},
+	}, resp.Result)
+
+	mockClient.On("UnbondingDelegations", mock.Anything, addr).Return(stakingtypes.UnbondingDelegations{}, nil).Once()
+
+	resp, err = servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.UnbondingDelegationsCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"unbonding_responses": []interface{}{}}, resp.Result)
+
+	mockClient.On("Validators", mock.Anything, "BOND_STATUS_BONDED").Return(stakingtypes.Validators{
+		{OperatorAddress: validatorAddress, Status: stakingtypes.Bonded, Tokens: sdk.NewInt(1000), DelegatorShares: sdk.NewDec(1000)},
+	}, nil).Once()
+
+	resp, err = servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.ValidatorsCallMethod,
+		Parameters: map[string]interface{}{"status": "BOND_STATUS_BONDED"},
+	})
+	require.Nil(t, err)
+	validators, ok := resp.Result["validators"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, validators, 1)
+	assert.Equal(t, validatorAddress, validators[0].(map[string]interface{})["operator_address"])
+	assert.Equal(t, "BOND_STATUS_BONDED", validators[0].(map[string]interface{})["status"])
+
+	mockClient.On("DelegationRewards", mock.Anything, addr).Return(&distrtypes.QueryDelegationTotalRewardsResponse{
+		Rewards: []distrtypes.DelegationDelegatorReward{
+			{ValidatorAddress: validatorAddress, Reward: sdk.NewDecCoins(sdk.NewDecCoin("ukava", sdk.NewInt(12)))},
+		},
+		Total: sdk.NewDecCoins(sdk.NewDecCoin("ukava", sdk.NewInt(12))),
+	}, nil).Once()
+
+	resp, err = servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.StakingRewardsCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"denom": "ukava", "amount": "12.000000000000000000"},
+	}, resp.Result["total"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCall_VestingSchedule(t *testing.T) {
+	servicer, mockClient := setupCallAPIServicer(configuration.Online)
+	ctx := context.Background()
+	addr := sdk.MustAccAddressFromBech32(callTestAddress)
+
+	baseAccount := authtypes.NewBaseAccount(addr, nil, 10, 5)
+	mockClient.On("Account", mock.Anything, addr).Return(baseAccount, nil).Once()
+
+	resp, err := servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.VestingScheduleCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"address":         callTestAddress,
+		"vesting_account": false,
+	}, resp.Result)
+
+	startTime := time.Now().Add(-90 * time.Second).Unix()
+	periods := vestingtypes.Periods{
+		{Length: 60, Amount: sdk.NewCoins(sdk.NewCoin("ukava", sdk.NewInt(100)))},
+		{Length: 60, Amount: sdk.NewCoins(sdk.NewCoin("ukava", sdk.NewInt(200)))},
+	}
+	vestingAccount := vestingtypes.NewPeriodicVestingAccount(
+		baseAccount,
+		sdk.NewCoins(sdk.NewCoin("ukava", sdk.NewInt(300))),
+		startTime,
+		periods,
+	)
+	mockClient.On("Account", mock.Anything, addr).Return(vestingAccount, nil).Once()
+
+	resp, err = servicer.Call(ctx, &types.CallRequest{
+		Method:     kava.VestingScheduleCallMethod,
+		Parameters: map[string]interface{}{"address": callTestAddress},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"address":          callTestAddress,
+		"vesting_account":  true,
+		"original_vesting": []interface{}{map[string]interface{}{"denom": "ukava", "amount": "300"}},
+		"start_time":       float64(startTime),
+		"end_time":         float64(startTime + 120),
+		"vested":           []interface{}{map[string]interface{}{"denom": "ukava", "amount": "100"}},
+		"vesting":          []interface{}{map[string]interface{}{"denom": "ukava", "amount": "200"}},
+		"periods": []interface{}{
+			map[string]interface{}{
+				"length":   float64(60),
+				"end_time": float64(startTime + 60),
+				"amount":   []interface{}{map[string]interface{}{"denom": "ukava", "amount": "100"}},
+			},
+			map[string]interface{}{
+				"length":   float64(60),
+				"end_time": float64(startTime + 120),
+				"amount":   []interface{}{map[string]interface{}{"denom": "ukava", "amount": "200"}},
+			},
+		},
+	}, resp.Result)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCall_SimulateTransaction(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	addr := sdk.MustAccAddressFromBech32(callTestAddress)
+
+	txBuilder := encodingConfig.TxConfig.NewTxBuilder()
+	require.NoError(t, txBuilder.SetMsgs(&banktypes.MsgSend{
+		FromAddress: callTestAddress,
+		ToAddress:   "kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea",
+		Amount:      sdk.NewCoins(sdk.NewCoin("ukava", sdk.NewInt(100))),
+	}))
+	txBuilder.SetGasLimit(200000)
+	unsignedTxBytes, encodeErr := encodingConfig.TxConfig.TxEncoder()(txBuilder.GetTx())
+	require.NoError(t, encodeErr)
+
+	require.NoError(t, txBuilder.SetSignatures(simulationSignature(simulationPubKey(nil, addr, nil), 3)))
+	signedTxBytes, encodeErr := encodingConfig.TxConfig.TxEncoder()(txBuilder.GetTx())
+	require.NoError(t, encodeErr)
+
+	operation := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 0},
+		Type:                kava.TransferOpType,
+		Status:              types.String(kava.SuccessStatus),
+		Account:             &types.AccountIdentifier{Address: callTestAddress},
+		Amount:              &types.Amount{Value: "-100", Currency: &types.Currency{Symbol: "KAVA", Decimals: 6}},
+	}
+
+	t.Run("unsigned transaction without signer infos", func(t *testing.T) {
+		servicer, mockClient := setupCallAPIServicer(configuration.Online)
+
+		mockClient.On("Account", mock.Anything, addr).Return(authtypes.NewBaseAccount(addr, nil, 10, 5), nil).Once()
+		mockClient.On("SimulateTransaction", mock.Anything, mock.MatchedBy(func(tx authsigning.Tx) bool {
+			sigs, err := tx.GetSignaturesV2()
+			return err == nil && len(sigs) == 1 && sigs[0].Sequence == 5
+		})).Return(&kava.SimulationResult{
+			GasInfo:    sdk.GasInfo{GasWanted: 200000, GasUsed: 81234},
+			Log:        "[]",
+			Operations: []*types.Operation{operation},
+		}, nil).Once()
+
+		resp, err := servicer.Call(context.Background(), &types.CallRequest{
+			Method:     kava.SimulateTransactionCallMethod,
+			Parameters: map[string]interface{}{"transaction": hex.EncodeToString(unsignedTxBytes)},
+		})
+		require.Nil(t, err)
+		assert.False(t, resp.Idempotent)
+		assert.Equal(t, map[string]interface{}{
+			"success":    true,
+			"gas_wanted": float64(200000),
+			"gas_used":   float64(81234),
+			"log":        "[]",
+			"operations": []interface{}{
+				map[string]interface{}{
+					"operation_identifier": map[string]interface{}{"index": float64(0)},
+					"type":                 kava.TransferOpType,
+					"status":               kava.SuccessStatus,
+					"account":              map[string]interface{}{"address": callTestAddress},
+					"amount": map[string]interface{}{
+						"value":    "-100",
+						"currency": map[string]interface{}{"symbol": "KAVA", "decimals": float64(6)},
+					},
+				},
+			},
+		}, resp.Result)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("signed transaction failing simulation", func(t *testing.T) {
+		servicer, mockClient := setupCallAPIServicer(configuration.Online)
+
+		mockClient.On("SimulateTransaction", mock.Anything, mock.Anything).Return(&kava.SimulationResult{
+			Operations: []*types.Operation{},
+			Err:        &kava.SimulationError{Codespace: "sdk", Code: 5, Log: "insufficient funds"},
+		}, nil).Once()
+
+		resp, err := servicer.Call(context.Background(), &types.CallRequest{
+			Method:     kava.SimulateTransactionCallMethod,
+			Parameters: map[string]interface{}{"transaction": hex.EncodeToString(signedTxBytes)},
+		})
+		require.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"success":    false,
+			"gas_wanted": float64(0),
+			"gas_used":   float64(0),
+			"operations": []interface{}{},
+			"error": map[string]interface{}{
+				"codespace": "sdk",
+				"code":      float64(5),
+				"log":       "insufficient funds",
+			},
+		}, resp.Result)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		servicer, mockClient := setupCallAPIServicer(configuration.Online)
+
+		mockClient.On("SimulateTransaction", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+		resp, err := servicer.Call(context.Background(), &types.CallRequest{
+			Method:     kava.SimulateTransactionCallMethod,
+			Parameters: map[string]interface{}{"transaction": hex.EncodeToString(signedTxBytes)},
+		})
+		assert.Nil(t, resp)
+		assert.Equal(t, ErrKava.Code, err.Code)
+
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestCall_Transaction(t *testing.T) {
+	servicer, mockClient := setupCallAPIServicer(configuration.Online)
+
+	transactionIdentifier := &types.TransactionIdentifier{Hash: "A6B7C28A7FC4B3D9C5A1A1E1E2C5B5FE6A54E5C2A9BC4A9EA4B1ABC1B2C3D4E5"}
+	blockIdentifier := &types.BlockIdentifier{Index: 100, Hash: "D92BDF0B5EDB04434B398A59B2FD4ED3D52B4820A18DAC7311EBDF5D37467E75"}
+	transaction := &types.Transaction{TransactionIdentifier: transactionIdentifier, Operations: []*types.Operation{}}
+
+	mockClient.On("Transaction", mock.Anything, transactionIdentifier).Return(blockIdentifier, transaction, nil).Once()
+
+	resp, err := servicer.Call(context.Background(), &types.CallRequest{
+		Method: kava.TransactionCallMethod,
+		Parameters: map[string]interface{}{
+			"transaction_identifier": map[string]interface{}{"hash": transactionIdentifier.Hash},
+		},
+	})
+	require.Nil(t, err)
+	assert.True(t, resp.Idempotent)
+	assert.Equal(t, map[string]interface{}{
+		"block_identifier": map[string]interface{}{"index": float64(100), "hash": blockIdentifier.Hash},
+		"transaction": map[string]interface{}{
+			"transaction_identifier": map[string]interface{}{"hash": transactionIdentifier.Hash},
+			"operations":             []interface{}{},
+		},
+	}, resp.Result)
+
+	mockClient.AssertExpectations(t)
+}
@@ -18,19 +18,33 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
+	"fmt"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 )
 
-// ConstructionCombine implements the /construction/combine endpoint.
+// ConstructionCombine implements the /construction/combine endpoint.  Signatures are matched to the
+// transaction signers by public key and verified against their sign bytes, using the public keys
+// and sequences of the transaction signer infos and the account numbers of the unsigned transaction.
+// Each signature must have the signature type of its key.  Signatures are verified against
+// SIGN_MODE_DIRECT sign bytes, except for transactions with a multisig signer, where every
+// signature, including each multisig member signature, is verified against LEGACY_AMINO_JSON sign bytes.
+// Hex encoded unsigned transactions have no account numbers, so their signatures are added without
+// being verified against sign bytes.
 func (s *ConstructionAPIService) ConstructionCombine(
 	ctx context.Context,
 	request *types.ConstructionCombineRequest,
 ) (*types.ConstructionCombineResponse, *types.Error) {
-	txBytes, err := hex.DecodeString(request.UnsignedTransaction)
+	txBytes, unsignedSigners, err := decodeUnsignedTx(request.UnsignedTransaction)
 	if err != nil {
 		return nil, wrapErr(ErrInvalidTx, err)
 	}
@@ -50,22 +64,101 @@ func (s *ConstructionAPIService) ConstructionCombine(
 		return nil, wrapErr(ErrInvalidTx, err)
 	}
 
-	for i, signature := range request.Signatures {
-		if i >= len(sigsV2) {
-			return nil, ErrMissingSignature
+	signerAddrs := txBuilder.GetTx().GetSigners()
+	if len(signerAddrs) != len(sigsV2) {
+		return nil, wrapErr(ErrInvalidTx, errors.New("unsigned transaction does not include a signer info for each signer"))
+	}
+	if unsignedSigners != nil && len(signerAddrs) != len(unsignedSigners) {
+		return nil, wrapErr(ErrInvalidTx, errors.New("unsigned transaction does not include signer data for each signer"))
+	}
+
+	signed := make([]bool, len(sigsV2))
+	seen := make(map[string]bool)
+	for _, signature := range request.Signatures {
+		if signature.PublicKey == nil {
+			return nil, ErrPublicKeyNil
+		}
+		pubKey, rerr := parsePublicKey(signature.PublicKey)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		i, memberKey, ok := findSigner(sigsV2, pubKey)
+		if !ok {
+			return nil, wrapErr(ErrInvalidSignature, fmt.Errorf("public key %x is not a signer of the transaction", signature.PublicKey.Bytes))
+		}
+
+		if seen[string(pubKey)] {
+			return nil, wrapErr(ErrInvalidSignature, fmt.Errorf("duplicate signature for public key %x", signature.PublicKey.Bytes))
 		}
-		// TODO: verify public key is equal to the set sig v2 public key
-		//tmpubkey, err := parsePublicKey(signature.PublicKey)
-		//if err != nil {
-		//return nil, err
-		//}
-		//pubkey := secp256k1.PubKey{Key: tmpubkey}
-
-		sigsV2[i].Data = &signing.SingleSignatureData{
+		seen[string(pubKey)] = true
+
+		if expected := signatureType(memberKey); signature.SignatureType != expected {
+			return nil, wrapErr(ErrInvalidSignature, fmt.Errorf("signature type %s does not match %s for public key %x", signature.SignatureType, expected, signature.PublicKey.Bytes))
+		}
+
+		signatureData := &signing.SingleSignatureData{
 			SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
 			Signature: signature.Bytes,
 		}
+		switch data := sigsV2[i].Data.(type) {
+		case *signing.MultiSignatureData:
+			signatureData.SignMode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+		case *signing.SingleSignatureData:
+			if data.SignMode != signing.SignMode_SIGN_MODE_UNSPECIFIED {
+				signatureData.SignMode = data.SignMode
+			}
+		}
+
+		if unsignedSigners != nil {
+			signerData := authsigning.SignerData{
+				Address:       signerAddrs[i].String(),
+				ChainID:       s.config.ChainID,
+				AccountNumber: unsignedSigners[i].AccountNumber,
+				Sequence:      sigsV2[i].Sequence,
+				PubKey:        sigsV2[i].PubKey,
+			}
+			signBytes, err := s.encodingConfig.TxConfig.SignModeHandler().GetSignBytes(signatureData.SignMode, signerData, tx)
+			if err != nil {
+				return nil, wrapErr(ErrInvalidTx, err)
+			}
+
+			if !memberKey.VerifySignature(signBytes, signature.Bytes) {
+				return nil, wrapErr(ErrInvalidSignature, fmt.Errorf("signature does not verify for public key %x", signature.PublicKey.Bytes))
+			}
+		}
+
+		signed[i] = true
+		if multisigKey, ok := sigsV2[i].PubKey.(*kmultisig.LegacyAminoPubKey); ok {
+			err := multisig.AddSignatureFromPubKey(
+				sigsV2[i].Data.(*signing.MultiSignatureData),
+				signatureData,
+				memberKey,
+				multisigKey.GetPubKeys(),
+			)
+			if err != nil {
+				return nil, wrapErr(ErrInvalidTx, err)
+			}
+			continue
+		}
+
+		sigsV2[i].Data = signatureData
+	}
+
+	for i, sigV2 := range sigsV2 {
+		if multisigKey, ok := sigV2.PubKey.(*kmultisig.LegacyAminoPubKey); ok {
+			threshold := int(multisigKey.Threshold)
+			if len(sigV2.Data.(*signing.MultiSignatureData).Signatures) < threshold {
+				return nil, wrapErr(ErrMissingSignature, fmt.Errorf("multisig %s requires %d signatures", signerAddrs[i], threshold))
+			}
+			continue
+		}
+
+		if !signed[i] {
+			return nil, wrapErr(ErrMissingSignature, fmt.Errorf("no signature for signer %s", signerAddrs[i]))
+		}
 	}
+
 	err = txBuilder.SetSignatures(sigsV2...)
 	if err != nil {
 		return nil, wrapErr(ErrInvalidTx, err)
@@ -80,3 +173,24 @@ func (s *ConstructionAPIService) ConstructionCombine(
 		SignedTransaction: hex.EncodeToString(signedTxBytes),
 	}, nil
 }
+
+// findSigner returns the index of the signature for a public key and the signing key, which is a
+// member of the public key of multisig signers
+func findSigner(sigsV2 []signing.SignatureV2, pubKey []byte) (int, cryptotypes.PubKey, bool) {
+	for i, sigV2 := range sigsV2 {
+		if multisigKey, ok := sigV2.PubKey.(*kmultisig.LegacyAminoPubKey); ok {
+			for _, member := range multisigKey.GetPubKeys() {
+				if bytes.Equal(member.Bytes(), pubKey) {
+					return i, member, true
+				}
+			}
+			continue
+		}
+
+		if bytes.Equal(sigV2.PubKey.Bytes(), pubKey) {
+			return i, sigV2.PubKey, true
+		}
+	}
+
+	return 0, nil, false
+}
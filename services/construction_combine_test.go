@@ -14,6 +14,27 @@
 
 package services
 
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
 //func TestConstructionCombine(t *testing.T) {
 //	encodingConfig := app.MakeEncodingConfig()
 //	networkIdentifier := &types.NetworkIdentifier{
@@ -98,3 +119,447 @@ package services
 //	//assert.Equal(t, expectedPubKey, signature.PubKey)
 //	//assert.Equal(t, mockSignBytes, signature.Signature)
 //}
+
+func TestConstructionCombine_VerifySignatures(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	signerKey := secp256k1.GenPrivKey()
+	signerAddr := sdk.AccAddress(signerKey.PubKey().Address())
+	feePayerKey := secp256k1.GenPrivKey()
+	feePayerAddr := sdk.AccAddress(feePayerKey.PubKey().Address())
+	otherKey := secp256k1.GenPrivKey()
+
+	networkIdentifier := &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"}
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			transferOp(0, signerAddr.String(), "-5000000", "ukava"),
+			transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+		},
+		Metadata: map[string]interface{}{
+			"signers":    "[{\"account_number\":10,\"account_sequence\":11},{\"account_number\":20,\"account_sequence\":21}]",
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+			"fee_payer":  feePayerAddr.String(),
+		},
+		PublicKeys: []*types.PublicKey{
+			{CurveType: types.Secp256k1, Bytes: signerKey.PubKey().Bytes()},
+			{CurveType: types.Secp256k1, Bytes: feePayerKey.PubKey().Bytes()},
+		},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 2, len(payloadsResponse.Payloads))
+
+	unsignedTx := mustDecodeTx(t, servicer, payloadsResponse.UnsignedTransaction)
+	handler := servicer.encodingConfig.TxConfig.SignModeHandler()
+
+	sign := func(key *secp256k1.PrivKey, payload *types.SigningPayload, signerData authsigning.SignerData) *types.Signature {
+		signBytes, err := handler.GetSignBytes(signing.SignMode_SIGN_MODE_DIRECT, signerData, unsignedTx)
+		require.NoError(t, err)
+		signature, err := key.Sign(signBytes)
+		require.NoError(t, err)
+
+		return &types.Signature{
+			SigningPayload: payload,
+			PublicKey:      &types.PublicKey{CurveType: types.Secp256k1, Bytes: key.PubKey().Bytes()},
+			SignatureType:  types.Ecdsa,
+			Bytes:          signature,
+		}
+	}
+
+	signerData := authsigning.SignerData{Address: signerAddr.String(), ChainID: servicer.config.ChainID, AccountNumber: 10, Sequence: 11}
+	feePayerData := authsigning.SignerData{Address: feePayerAddr.String(), ChainID: servicer.config.ChainID, AccountNumber: 20, Sequence: 21}
+	signerSig := sign(signerKey, payloadsResponse.Payloads[0], signerData)
+	feePayerSig := sign(feePayerKey, payloadsResponse.Payloads[1], feePayerData)
+
+	testCases := []struct {
+		name       string
+		signatures []*types.Signature
+		errCode    int32
+	}{
+		{
+			name:       "signatures in signer order",
+			signatures: []*types.Signature{signerSig, feePayerSig},
+		},
+		{
+			name:       "signatures out of signer order",
+			signatures: []*types.Signature{feePayerSig, signerSig},
+		},
+		{
+			name:       "missing signature",
+			signatures: []*types.Signature{signerSig},
+			errCode:    ErrMissingSignature.Code,
+		},
+		{
+			name:       "duplicate signature",
+			signatures: []*types.Signature{signerSig, feePayerSig, signerSig},
+			errCode:    ErrInvalidSignature.Code,
+		},
+		{
+			name:       "extra signature",
+			signatures: []*types.Signature{signerSig, feePayerSig, sign(otherKey, payloadsResponse.Payloads[0], signerData)},
+			errCode:    ErrInvalidSignature.Code,
+		},
+		{
+			name:       "signature of other sign bytes",
+			signatures: []*types.Signature{sign(signerKey, payloadsResponse.Payloads[0], feePayerData), feePayerSig},
+			errCode:    ErrInvalidSignature.Code,
+		},
+		{
+			name: "signature of other public key",
+			signatures: []*types.Signature{
+				{
+					SigningPayload: signerSig.SigningPayload,
+					PublicKey:      signerSig.PublicKey,
+					SignatureType:  types.Ecdsa,
+					Bytes:          feePayerSig.Bytes,
+				},
+				feePayerSig,
+			},
+			errCode: ErrInvalidSignature.Code,
+		},
+		{
+			name: "signature type of other key type",
+			signatures: []*types.Signature{
+				{
+					SigningPayload: signerSig.SigningPayload,
+					PublicKey:      signerSig.PublicKey,
+					SignatureType:  types.EcdsaRecovery,
+					Bytes:          signerSig.Bytes,
+				},
+				feePayerSig,
+			},
+			errCode: ErrInvalidSignature.Code,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, rerr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+				NetworkIdentifier:   networkIdentifier,
+				UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+				Signatures:          tc.signatures,
+			})
+
+			if tc.errCode != 0 {
+				require.NotNil(t, rerr)
+				assert.Equal(t, tc.errCode, rerr.Code)
+				return
+			}
+			require.Nil(t, rerr)
+
+			signedTxBytes, err := hex.DecodeString(response.SignedTransaction)
+			require.NoError(t, err)
+			signedTx, err := servicer.encodingConfig.TxConfig.TxDecoder()(signedTxBytes)
+			require.NoError(t, err)
+			sigsV2, err := signedTx.(authsigning.Tx).GetSignaturesV2()
+			require.NoError(t, err)
+			require.Equal(t, 2, len(sigsV2))
+			assert.NoError(t, authsigning.VerifySignature(sigsV2[0].PubKey, signerData, sigsV2[0].Data, handler, signedTx))
+			assert.NoError(t, authsigning.VerifySignature(sigsV2[1].PubKey, feePayerData, sigsV2[1].Data, handler, signedTx))
+		})
+	}
+
+	txBytes, unsignedSigners, err := decodeUnsignedTx(payloadsResponse.UnsignedTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, []unsignedSignerData{{AccountNumber: 10}, {AccountNumber: 20}}, unsignedSigners)
+
+	swappedAccountNumbers, err := json.Marshal(unsignedTransaction{
+		Tx:         hex.EncodeToString(txBytes),
+		SignerData: []unsignedSignerData{{AccountNumber: 20}, {AccountNumber: 10}},
+	})
+	require.NoError(t, err)
+	_, rerr = servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: string(swappedAccountNumbers),
+		Signatures:          []*types.Signature{signerSig, feePayerSig},
+	})
+	require.NotNil(t, rerr)
+	assert.Equal(t, ErrInvalidSignature.Code, rerr.Code)
+
+	withoutSignerData, err := json.Marshal(unsignedTransaction{Tx: hex.EncodeToString(txBytes)})
+	require.NoError(t, err)
+	_, rerr = servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: string(withoutSignerData),
+		Signatures:          []*types.Signature{signerSig, feePayerSig},
+	})
+	require.NotNil(t, rerr)
+	assert.Equal(t, ErrInvalidTx.Code, rerr.Code)
+
+	// hex encoded unsigned transactions are combined without verifying sign bytes
+	response, rerr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: hex.EncodeToString(txBytes),
+		Signatures:          []*types.Signature{signerSig, feePayerSig},
+	})
+	require.Nil(t, rerr)
+	signedTx := mustDecodeTx(t, servicer, response.SignedTransaction).(authsigning.Tx)
+	sigsV2, err := signedTx.GetSignaturesV2()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(sigsV2))
+	assert.NoError(t, authsigning.VerifySignature(sigsV2[0].PubKey, signerData, sigsV2[0].Data, handler, signedTx))
+	assert.NoError(t, authsigning.VerifySignature(sigsV2[1].PubKey, feePayerData, sigsV2[1].Data, handler, signedTx))
+
+	_, rerr = servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: hex.EncodeToString(txBytes),
+		Signatures:          []*types.Signature{signerSig},
+	})
+	require.NotNil(t, rerr)
+	assert.Equal(t, ErrMissingSignature.Code, rerr.Code)
+}
+
+func TestConstructionCombine_Multisig(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	memberKeys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	memberPubKeys := []cryptotypes.PubKey{}
+	publicKeys := []*types.PublicKey{}
+	for _, key := range memberKeys {
+		memberPubKeys = append(memberPubKeys, key.PubKey())
+		publicKeys = append(publicKeys, &types.PublicKey{CurveType: types.Secp256k1, Bytes: key.PubKey().Bytes()})
+	}
+	multisigKey := kmultisig.NewLegacyAminoPubKey(2, memberPubKeys)
+	multisigAddr := sdk.AccAddress(multisigKey.Address())
+	encodedMultisig, err := encodeMultisig(multisigKey)
+	require.NoError(t, err)
+
+	networkIdentifier := &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"}
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			transferOp(0, multisigAddr.String(), "-5000000", "ukava"),
+			transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+		},
+		Metadata: map[string]interface{}{
+			"signers":    "[{\"account_number\":10,\"account_sequence\":11,\"multisig\":true}]",
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+			"multisig":   encodedMultisig,
+		},
+		PublicKeys: publicKeys,
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 3, len(payloadsResponse.Payloads))
+
+	unsignedTx := mustDecodeTx(t, servicer, payloadsResponse.UnsignedTransaction)
+	signerData := authsigning.SignerData{
+		Address:       multisigAddr.String(),
+		ChainID:       servicer.config.ChainID,
+		AccountNumber: 10,
+		Sequence:      11,
+	}
+	handler := servicer.encodingConfig.TxConfig.SignModeHandler()
+	signBytes, err := handler.GetSignBytes(signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signerData, unsignedTx)
+	require.NoError(t, err)
+
+	signatures := []*types.Signature{}
+	for i, payload := range payloadsResponse.Payloads {
+		if i == 1 {
+			continue
+		}
+		signature, err := memberKeys[i].Sign(signBytes)
+		require.NoError(t, err)
+		signatures = append(signatures, &types.Signature{
+			SigningPayload: payload,
+			PublicKey:      publicKeys[i],
+			SignatureType:  types.Ecdsa,
+			Bytes:          signature,
+		})
+	}
+
+	_, rerr = servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+		Signatures:          signatures[:1],
+	})
+	assert.Equal(t, ErrMissingSignature.Code, rerr.Code)
+
+	combineResponse, rerr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+		Signatures:          signatures,
+	})
+	require.Nil(t, rerr)
+
+	signedTx := mustDecodeTx(t, servicer, combineResponse.SignedTransaction)
+	sigsV2, err := signedTx.(authsigning.Tx).GetSignaturesV2()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(sigsV2))
+	assert.True(t, multisigKey.Equals(sigsV2[0].PubKey))
+	assert.NoError(t, authsigning.VerifySignature(sigsV2[0].PubKey, signerData, sigsV2[0].Data, handler, signedTx))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       combineResponse.SignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, []*types.AccountIdentifier{{Address: multisigAddr.String()}}, parseResponse.AccountIdentifierSigners)
+}
+
+func TestConstructionCombine_EthSecp256k1(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	privKey, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	signerAddr := sdk.AccAddress(privKey.PubKey().Address())
+	publicKey := &types.PublicKey{CurveType: types.Secp256k1, Bytes: privKey.PubKey().Bytes()}
+
+	networkIdentifier := &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"}
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			transferOp(0, signerAddr.String(), "-5000000", "ukava"),
+			transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+		},
+		Metadata: map[string]interface{}{
+			"signers":    "[{\"account_number\":10,\"account_sequence\":11}]",
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{publicKey},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+	payload := payloadsResponse.Payloads[0]
+
+	ecdsaKey, err := privKey.ToECDSA()
+	require.NoError(t, err)
+	signature, err := ethcrypto.Sign(payload.Bytes, ecdsaKey)
+	require.NoError(t, err)
+
+	combineResponse, rerr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+		Signatures: []*types.Signature{
+			{
+				SigningPayload: payload,
+				PublicKey:      publicKey,
+				SignatureType:  types.EcdsaRecovery,
+				Bytes:          signature,
+			},
+		},
+	})
+	require.Nil(t, rerr)
+
+	signerData := authsigning.SignerData{
+		Address:       signerAddr.String(),
+		ChainID:       servicer.config.ChainID,
+		AccountNumber: 10,
+		Sequence:      11,
+	}
+	handler := servicer.encodingConfig.TxConfig.SignModeHandler()
+	signedTx := mustDecodeTx(t, servicer, combineResponse.SignedTransaction)
+	sigsV2, err := signedTx.(authsigning.Tx).GetSignaturesV2()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(sigsV2))
+	assert.IsType(t, &ethsecp256k1.PubKey{}, sigsV2[0].PubKey)
+	assert.NoError(t, authsigning.VerifySignature(sigsV2[0].PubKey, signerData, sigsV2[0].Data, handler, signedTx))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       combineResponse.SignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, []*types.AccountIdentifier{{Address: signerAddr.String()}}, parseResponse.AccountIdentifierSigners)
+}
+
+// testKeyStorage stores keys by the hash of the account identifier of the derived account and signs
+// payloads with the key of their account identifier, as the key storage of rosetta-cli does
+type testKeyStorage map[string]*ecdsa.PrivateKey
+
+func (k testKeyStorage) sign(t *testing.T, payloads []*types.SigningPayload) []*types.Signature {
+	signatures := []*types.Signature{}
+	for _, payload := range payloads {
+		key, ok := k[types.Hash(payload.AccountIdentifier)]
+		require.True(t, ok, "no key for account identifier %s", types.PrintStruct(payload.AccountIdentifier))
+
+		signature, err := ethcrypto.Sign(payload.Bytes, key)
+		require.NoError(t, err)
+		if payload.SignatureType == types.Ecdsa {
+			signature = signature[:64]
+		}
+
+		signatures = append(signatures, &types.Signature{
+			SigningPayload: payload,
+			PublicKey:      &types.PublicKey{CurveType: types.Secp256k1, Bytes: ethcrypto.CompressPubkey(&key.PublicKey)},
+			SignatureType:  payload.SignatureType,
+			Bytes:          signature,
+		})
+	}
+
+	return signatures
+}
+
+func TestConstructionCombine_SignWithDerivedAccounts(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	keyStorage := testKeyStorage{}
+	deriveAndStore := func(keyType string) (*types.AccountIdentifier, *types.PublicKey) {
+		key, err := ethcrypto.GenerateKey()
+		require.NoError(t, err)
+		publicKey := &types.PublicKey{CurveType: types.Secp256k1, Bytes: ethcrypto.CompressPubkey(&key.PublicKey)}
+
+		deriveResponse, rerr := servicer.ConstructionDerive(ctx, &types.ConstructionDeriveRequest{
+			PublicKey: publicKey,
+			Metadata:  map[string]interface{}{"key_type": keyType},
+		})
+		require.Nil(t, rerr)
+		keyStorage[types.Hash(deriveResponse.AccountIdentifier)] = key
+
+		return deriveResponse.AccountIdentifier, publicKey
+	}
+	signer, signerPublicKey := deriveAndStore("secp256k1")
+	feePayer, feePayerPublicKey := deriveAndStore("eth_secp256k1")
+
+	networkIdentifier := &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"}
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			transferOp(0, signer.Address, "-5000000", "ukava"),
+			transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+		},
+		Metadata: map[string]interface{}{
+			"signers":    "[{\"account_number\":10,\"account_sequence\":11},{\"account_number\":20,\"account_sequence\":21}]",
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+			"fee_payer":  feePayer.Address,
+		},
+		PublicKeys: []*types.PublicKey{signerPublicKey, feePayerPublicKey},
+	})
+	require.Nil(t, rerr)
+
+	combineResponse, rerr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+		Signatures:          keyStorage.sign(t, payloadsResponse.Payloads),
+	})
+	require.Nil(t, rerr)
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       combineResponse.SignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.NoError(t, parser.ExpectedSigners(payloadsResponse.Payloads, parseResponse.AccountIdentifierSigners))
+}
+
+func mustDecodeTx(t *testing.T, servicer *ConstructionAPIService, encoded string) sdk.Tx {
+	txBytes, err := decodeTxBytes(encoded)
+	require.NoError(t, err)
+	tx, err := servicer.encodingConfig.TxConfig.TxDecoder()(txBytes)
+	require.NoError(t, err)
+	return tx
+}
@@ -20,27 +20,70 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cometbft/cometbft/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
+)
+
+const (
+	// keyTypeMetadataKey is the derive metadata key for the type of the public key
+	keyTypeMetadataKey = "key_type"
+	// secp256k1KeyType is the key type of cosmos secp256k1 keys, with ripemd160 addresses
+	secp256k1KeyType = "secp256k1"
+	// ethSecp256k1KeyType is the key type of ethermint eth_secp256k1 keys, with keccak256 addresses
+	ethSecp256k1KeyType = "eth_secp256k1"
 )
 
 // ConstructionDerive implements the /construction/derive endpoint.
 func (s *ConstructionAPIService) ConstructionDerive(ctx context.Context, request *types.ConstructionDeriveRequest) (*types.ConstructionDeriveResponse, *types.Error) {
-	addr, err := getAddressFromPublicKey(request.PublicKey)
+	keyType, err := getKeyTypeFromMetadata(request.Metadata)
 	if err != nil {
-		return nil, err
+		return nil, wrapErr(ErrInvalidMetadata, err)
+	}
+
+	var addr sdk.AccAddress
+	var rerr *types.Error
+	if keyType == ethSecp256k1KeyType {
+		addr, rerr = getEthAddressFromPublicKey(request.PublicKey)
+	} else {
+		addr, rerr = getAddressFromPublicKey(request.PublicKey)
+	}
+	if rerr != nil {
+		return nil, rerr
 	}
 
 	return &types.ConstructionDeriveResponse{
 		AccountIdentifier: &types.AccountIdentifier{
 			Address: addr.String(),
 		},
+		Metadata: map[string]interface{}{
+			"hex_address": common.BytesToAddress(addr).Hex(),
+		},
 	}, nil
 }
 
+// getKeyTypeFromMetadata returns the optional key type of a public key, defaulting to secp256k1
+func getKeyTypeFromMetadata(metadata map[string]interface{}) (string, error) {
+	rawKeyType, ok := metadata[keyTypeMetadataKey]
+	if !ok {
+		return secp256k1KeyType, nil
+	}
+
+	keyType, ok := rawKeyType.(string)
+	if !ok || (keyType != secp256k1KeyType && keyType != ethSecp256k1KeyType) {
+		return "", fmt.Errorf("invalid value for %s", keyTypeMetadataKey)
+	}
+
+	return keyType, nil
+}
+
 // TODO: use cosmos-sdk/crypto/keys instead of tendermint?
 func parsePublicKey(pubKey *types.PublicKey) (secp256k1.PubKey, *types.Error) {
 	tmPubKey := make([]byte, secp256k1.PubKeySize)
@@ -71,3 +114,37 @@ func getAddressFromPublicKey(pubKey *types.PublicKey) (sdk.AccAddress, *types.Er
 
 	return sdk.AccAddress(tmPubKey.Address().Bytes()), nil
 }
+
+// getEthAddressFromPublicKey returns the address of an eth_secp256k1 key, the last 20 bytes of
+// the keccak256 hash of the uncompressed key
+func getEthAddressFromPublicKey(pubKey *types.PublicKey) (sdk.AccAddress, *types.Error) {
+	tmPubKey, err := parsePublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ethPubKey := ethsecp256k1.PubKey{Key: tmPubKey}
+	return sdk.AccAddress(ethPubKey.Address().Bytes()), nil
+}
+
+// getSignerPublicKey returns the public key of a transaction signer, an eth_secp256k1 key when
+// the signer address is derived from the key with keccak256 and a secp256k1 key when it is
+// derived with sha256 and ripemd160.  Keys that do not derive the signer address are invalid.
+func getSignerPublicKey(pubKey *types.PublicKey, signer sdk.AccAddress) (cryptotypes.PubKey, *types.Error) {
+	tmPubKey, err := parsePublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ethPubKey := &ethsecp256k1.PubKey{Key: tmPubKey}
+	if signer.Equals(sdk.AccAddress(ethPubKey.Address())) {
+		return ethPubKey, nil
+	}
+
+	secpPubKey := &sdksecp256k1.PubKey{Key: tmPubKey}
+	if signer.Equals(sdk.AccAddress(secpPubKey.Address())) {
+		return secpPubKey, nil
+	}
+
+	return nil, wrapErr(ErrInvalidPublicKey, fmt.Errorf("public key does not match signer %s", signer))
+}
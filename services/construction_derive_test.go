@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"testing"
 
@@ -142,3 +143,64 @@ func TestConstructionDerive_PublicKeyValid(t *testing.T) {
 		})
 	}
 }
+
+func TestConstructionDerive_EthSecp256k1(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	// public key of the private key 0x01
+	pubKeyBytes, err := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	require.NoError(t, err)
+	publicKey := &types.PublicKey{CurveType: types.Secp256k1, Bytes: pubKeyBytes}
+
+	testCases := []struct {
+		name       string
+		metadata   map[string]interface{}
+		address    string
+		hexAddress string
+		err        *types.Error
+	}{
+		{
+			name:       "default key type",
+			metadata:   nil,
+			address:    "kava1w508d6qejxtdg4y5r3zarvary0c5xw7kxgr8el",
+			hexAddress: "0x751e76E8199196d454941c45d1b3A323F1433bd6",
+		},
+		{
+			name:       "secp256k1 key type",
+			metadata:   map[string]interface{}{"key_type": "secp256k1"},
+			address:    "kava1w508d6qejxtdg4y5r3zarvary0c5xw7kxgr8el",
+			hexAddress: "0x751e76E8199196d454941c45d1b3A323F1433bd6",
+		},
+		{
+			name:       "eth_secp256k1 key type",
+			metadata:   map[string]interface{}{"key_type": "eth_secp256k1"},
+			address:    "kava10e0525sfrf53yh2aljmm3sn9jq5njk7lc4cmkk",
+			hexAddress: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		},
+		{
+			name:     "invalid key type",
+			metadata: map[string]interface{}{"key_type": "ed25519"},
+			err:      wrapErr(ErrInvalidMetadata, errors.New("invalid value for key_type")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, rerr := servicer.ConstructionDerive(ctx, &types.ConstructionDeriveRequest{
+				PublicKey: publicKey,
+				Metadata:  tc.metadata,
+			})
+
+			if tc.err != nil {
+				assert.Nil(t, response)
+				assert.Equal(t, tc.err, rerr)
+				return
+			}
+
+			require.Nil(t, rerr)
+			assert.Equal(t, tc.address, response.AccountIdentifier.Address)
+			assert.Equal(t, tc.hexAddress, response.Metadata["hex_address"])
+		})
+	}
+}
@@ -23,17 +23,21 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
-	"github.com/kava-labs/rosetta-kava/kava"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/cosmos/cosmos-sdk/codec"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
 )
 
 var simPubKey = make([]byte, secp256k1.PubKeySize)
@@ -49,11 +53,15 @@ type options struct {
 	gasAdjustment          float64
 	suggestedFeeMultiplier float64
 	maxFee                 sdk.Coins
+	feePayer               sdk.AccAddress
+	feeGranter             sdk.AccAddress
+	multisig               *kmultisig.LegacyAminoPubKey
 }
 
 type signerInfo struct {
 	AccountNumber   uint64 `json:"account_number"`
 	AccountSequence uint64 `json:"account_sequence"`
+	Multisig        bool   `json:"multisig,omitempty"`
 }
 
 // ConstructionMetadata implements the /construction/metadata endpoint.
@@ -83,41 +91,58 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		msgs = append(msgs, msg)
 	}
 
-	var signers []signerInfo
-	var sigsV2 []signing.SignatureV2
-	seenSigners := make(map[string]bool)
+	// ibc transfers without a timeout time out a configured duration after the latest block
+	var ibcTimeoutTimestamp uint64
 	for _, msg := range msgs {
-		for _, signerAddr := range msg.GetSigners() {
-			addr := signerAddr.String()
-
-			if !seenSigners[addr] {
-				seenSigners[addr] = true
-
-				acc, err := s.client.Account(ctx, signerAddr)
-				if err != nil {
-					return nil, wrapErr(ErrKava, err)
-				}
-
-				signers = append(signers, signerInfo{
-					AccountNumber:   acc.GetAccountNumber(),
-					AccountSequence: acc.GetSequence(),
-				})
-
-				sdkpubkey := secp256k1.PubKey{Key: simPubKey}
-
-				signatureData := signing.SingleSignatureData{
-					SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
-					Signature: nil,
-				}
-				sigV2 := signing.SignatureV2{
-					PubKey:   &sdkpubkey,
-					Data:     &signatureData,
-					Sequence: acc.GetSequence(),
-				}
-
-				sigsV2 = append(sigsV2, sigV2)
+		transfer, ok := msg.(*ibctransfertypes.MsgTransfer)
+		if !ok || !transfer.TimeoutHeight.IsZero() || transfer.TimeoutTimestamp != 0 {
+			continue
+		}
+
+		if ibcTimeoutTimestamp == 0 {
+			syncInfo, err := s.client.SyncInfo(ctx)
+			if err != nil {
+				return nil, wrapErr(ErrKava, err)
 			}
+
+			ibcTimeoutTimestamp = uint64(syncInfo.LatestBlockTime.Add(s.config.IBCTransferTimeout).UnixNano())
+		}
+
+		transfer.TimeoutTimestamp = ibcTimeoutTimestamp
+	}
+
+	var msgSigners []sdk.AccAddress
+	for _, msg := range msgs {
+		msgSigners = append(msgSigners, msg.GetSigners()...)
+	}
+
+	var signers []signerInfo
+	var sigsV2 []signing.SignatureV2
+	for _, signerAddr := range transactionSigners(msgSigners, options.feePayer) {
+		acc, err := s.client.Account(ctx, signerAddr)
+		if err != nil {
+			return nil, wrapErr(ErrKava, err)
+		}
+
+		// the multisig signer is simulated with empty signatures for its threshold of members
+		if options.multisig != nil && signerAddr.Equals(sdk.AccAddress(options.multisig.Address())) {
+			signers = append(signers, signerInfo{
+				AccountNumber:   acc.GetAccountNumber(),
+				AccountSequence: acc.GetSequence(),
+				Multisig:        true,
+			})
+
+			sigsV2 = append(sigsV2, multisigSimulationSignature(options.multisig, acc.GetSequence()))
+			continue
 		}
+
+		signers = append(signers, signerInfo{
+			AccountNumber:   acc.GetAccountNumber(),
+			AccountSequence: acc.GetSequence(),
+		})
+
+		pubKey := simulationPubKey(acc.GetPubKey(), signerAddr, request.PublicKeys)
+		sigsV2 = append(sigsV2, simulationSignature(pubKey, acc.GetSequence()))
 	}
 
 	encodedSigners, err := json.Marshal(signers)
@@ -131,6 +156,8 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		return nil, wrapErr(ErrInvalidTx, err)
 	}
 	txBuilder.SetMemo(options.txBody.Memo)
+	txBuilder.SetFeePayer(options.feePayer)
+	txBuilder.SetFeeGranter(options.feeGranter)
 	err = txBuilder.SetSignatures(sigsV2...)
 	if err != nil {
 		return nil, wrapErr(ErrInvalidTx, err)
@@ -143,7 +170,7 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		return nil, wrapErr(ErrKava, err)
 	}
 
-	gasPrice := gasPriceFromMultiplier(options.suggestedFeeMultiplier)
+	gasPrice := gasPriceFromMultiplier(s.config.GasPriceCurve, options.suggestedFeeMultiplier)
 	feeAmount := gasPrice * float64(gasWanted)
 	suggestedFeeAmount := sdkmath.NewInt(int64(math.Ceil(feeAmount)))
 
@@ -152,22 +179,82 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		gasPrice = float64(suggestedFeeAmount.Int64()) / float64(gasWanted)
 	}
 
+	metadata := map[string]interface{}{
+		"signers":    string(encodedSigners),
+		"gas_wanted": gasWanted,
+		"gas_price":  gasPrice,
+		"memo":       options.txBody.Memo,
+	}
+
+	if options.feePayer != nil {
+		metadata[feePayerMetadataKey] = options.feePayer.String()
+	}
+
+	if options.feeGranter != nil {
+		metadata[feeGranterMetadataKey] = options.feeGranter.String()
+	}
+
+	if options.multisig != nil {
+		encodedMultisig, err := encodeMultisig(options.multisig)
+		if err != nil {
+			return nil, wrapErr(ErrKava, err)
+		}
+		metadata[multisigMetadataKey] = encodedMultisig
+	}
+
+	if ibcTimeoutTimestamp != 0 {
+		metadata["ibc_timeout_timestamp"] = strconv.FormatUint(ibcTimeoutTimestamp, 10)
+	}
+
+	feeCurrency, _ := s.registry.Currency("ukava")
+
 	return &types.ConstructionMetadataResponse{
-		Metadata: map[string]interface{}{
-			"signers":    string(encodedSigners),
-			"gas_wanted": gasWanted,
-			"gas_price":  gasPrice,
-			"memo":       options.txBody.Memo,
-		},
+		Metadata: metadata,
 		SuggestedFee: []*types.Amount{
 			{
 				Value:    suggestedFeeAmount.String(),
-				Currency: kava.Currencies["ukava"],
+				Currency: feeCurrency,
 			},
 		},
 	}, nil
 }
 
+// simulationPubKey returns a placeholder public key of the type of the key of a signer, taken
+// from its account or else from the public keys of the request, since signatures of eth_secp256k1
+// keys consume more gas to verify than secp256k1 signatures
+func simulationPubKey(accPubKey cryptotypes.PubKey, signer sdk.AccAddress, publicKeys []*types.PublicKey) cryptotypes.PubKey {
+	if accPubKey == nil {
+		for _, publicKey := range publicKeys {
+			pubKey, rerr := getSignerPublicKey(publicKey, signer)
+			if rerr == nil {
+				accPubKey = pubKey
+				break
+			}
+		}
+	}
+
+	if _, ok := accPubKey.(*ethsecp256k1.PubKey); ok {
+		return &ethsecp256k1.PubKey{Key: simPubKey}
+	}
+
+	return &secp256k1.PubKey{Key: simPubKey}
+}
+
+// simulationSignature returns an empty signature with a placeholder public key, used to
+// simulate transactions before they are signed
+func simulationSignature(pubKey cryptotypes.PubKey, sequence uint64) signing.SignatureV2 {
+	signatureData := signing.SingleSignatureData{
+		SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
+		Signature: nil,
+	}
+
+	return signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signatureData,
+		Sequence: sequence,
+	}
+}
+
 func validateAndParseOptions(cdc codec.Codec, opts map[string]interface{}) (*options, error) {
 	for _, option := range requiredOptions {
 		if _, ok := opts[option]; !ok {
@@ -209,30 +296,67 @@ func validateAndParseOptions(cdc codec.Codec, opts map[string]interface{}) (*opt
 		}
 	}
 
+	feePayer, err := parseOptionalAddress(opts, feePayerMetadataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	feeGranter, err := parseOptionalAddress(opts, feeGranterMetadataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	multisig, err := getMultisigFromMetadata(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	return &options{
 		txBody:                 &txBody,
 		gasAdjustment:          gasAdjustment,
 		suggestedFeeMultiplier: suggestedFeeMultiplier,
 		maxFee:                 maxFee,
+		feePayer:               feePayer,
+		feeGranter:             feeGranter,
+		multisig:               multisig,
 	}, nil
 }
 
-func gasPriceFromMultiplier(multiplier float64) float64 {
-	if multiplier <= 0 {
-		return 0.001
+// parseOptionalAddress returns the address of an optional key, or nil when the key is not set
+func parseOptionalAddress(values map[string]interface{}, key string) (sdk.AccAddress, error) {
+	value, ok := values[key]
+	if !ok {
+		return nil, nil
+	}
+
+	rawAddress, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid value for %s", key)
+	}
+
+	addr, err := sdk.AccAddressFromBech32(rawAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s", key)
 	}
 
-	if multiplier < 1 {
-		return multiplier*0.004 + 0.001
+	return addr, nil
+}
+
+// gasPriceFromMultiplier interpolates between the gas prices of the curve at whole multipliers,
+// using the last gas price for multipliers beyond the end of the curve
+func gasPriceFromMultiplier(curve []float64, multiplier float64) float64 {
+	if len(curve) == 0 {
+		curve = configuration.DefaultGasPriceCurve
 	}
 
-	if multiplier < 2 {
-		return (multiplier-1)*0.045 + 0.005
+	if multiplier <= 0 {
+		return curve[0]
 	}
 
-	if multiplier < 3 {
-		return (multiplier-2)*0.2 + 0.05
+	i := int(multiplier)
+	if i >= len(curve)-1 {
+		return curve[len(curve)-1]
 	}
 
-	return 0.25
+	return (multiplier-float64(i))*(curve[i+1]-curve[i]) + curve[i]
 }
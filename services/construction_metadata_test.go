@@ -26,13 +26,17 @@ import (
 	sdkmath "cosmossdk.io/math"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
 	"github.com/kava-labs/kava/app"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -427,7 +431,7 @@ func TestConstructionMetadata_GasAndFee(t *testing.T) {
 			assert.InDelta(t, tc.expectedGasPrice, gasPrice, 0.000000000001)
 
 			require.Equal(t, 1, len(response.SuggestedFee))
-			coin, rerr := amountToCoin(response.SuggestedFee[0])
+			coin, rerr := amountToCoin(testRegistry, response.SuggestedFee[0])
 			require.Nil(t, rerr)
 
 			assert.Equal(t, "ukava", coin.Denom)
@@ -436,6 +440,120 @@ func TestConstructionMetadata_GasAndFee(t *testing.T) {
 	}
 }
 
+func TestConstructionMetadata_SimulationPublicKey(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	cdc := encodingConfig.Marshaler
+
+	ethKey, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	ethPubKey := ethKey.PubKey()
+	ethAddr := sdk.AccAddress(ethPubKey.Address())
+
+	secpPubKey := secp256k1.GenPrivKey().PubKey()
+	secpAddr := sdk.AccAddress(secpPubKey.Address())
+
+	testCases := []struct {
+		name            string
+		signer          sdk.AccAddress
+		accountPubKey   cryptotypes.PubKey
+		publicKeys      []*types.PublicKey
+		expectedEthType bool
+	}{
+		{
+			name:            "eth_secp256k1 account public key",
+			signer:          ethAddr,
+			accountPubKey:   ethPubKey,
+			expectedEthType: true,
+		},
+		{
+			name:            "eth_secp256k1 request public key",
+			signer:          ethAddr,
+			publicKeys:      []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: ethPubKey.Bytes()}},
+			expectedEthType: true,
+		},
+		{
+			name:            "secp256k1 account public key",
+			signer:          secpAddr,
+			accountPubKey:   secpPubKey,
+			expectedEthType: false,
+		},
+		{
+			name:            "secp256k1 request public key",
+			signer:          secpAddr,
+			publicKeys:      []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: secpPubKey.Bytes()}},
+			expectedEthType: false,
+		},
+		{
+			name:            "no public key",
+			signer:          ethAddr,
+			expectedEthType: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			servicer, mockClient := setupConstructionAPIServicer()
+			servicer.config.Mode = configuration.Online
+			ctx := context.Background()
+
+			anys, err := convertMsgsToAnys([]sdk.Msg{
+				&banktypes.MsgSend{
+					FromAddress: tc.signer.String(),
+					ToAddress:   "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w",
+					Amount:      sdk.NewCoins(sdk.NewCoin("ukava", sdkmath.NewInt(100))),
+				},
+			})
+			require.NoError(t, err)
+			encodedTxBody, err := cdc.MarshalJSON(&tx.TxBody{Messages: anys})
+			require.NoError(t, err)
+
+			account := &authtypes.BaseAccount{Address: tc.signer.String(), AccountNumber: 10, Sequence: 11}
+			if tc.accountPubKey != nil {
+				require.NoError(t, account.SetPubKey(tc.accountPubKey))
+			}
+			mockClient.On("Account", ctx, tc.signer).Return(account, nil).Once()
+
+			isSimulatedWithEthKey := mock.MatchedBy(func(tx authsigning.Tx) bool {
+				sigs, err := tx.GetSignaturesV2()
+				if err != nil || len(sigs) != 1 {
+					return false
+				}
+				_, isEthKey := sigs[0].PubKey.(*ethsecp256k1.PubKey)
+				return isEthKey == tc.expectedEthType
+			})
+			mockClient.On("EstimateGas", ctx, isSimulatedWithEthKey, float64(0.1)).Return(uint64(100000), nil).Once()
+
+			_, rerr := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+				Options: map[string]interface{}{
+					"tx_body":                  string(encodedTxBody),
+					"gas_adjustment":           float64(0.1),
+					"suggested_fee_multiplier": float64(1),
+				},
+				PublicKeys: tc.publicKeys,
+			})
+			require.Nil(t, rerr)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGasPriceFromMultiplier(t *testing.T) {
+	curve := []float64{0.002, 0.01, 0.1}
+
+	assert.Equal(t, 0.002, gasPriceFromMultiplier(curve, -1))
+	assert.Equal(t, 0.002, gasPriceFromMultiplier(curve, 0))
+	assert.InDelta(t, 0.006, gasPriceFromMultiplier(curve, 0.5), 0.000000000001)
+	assert.InDelta(t, 0.01, gasPriceFromMultiplier(curve, 1), 0.000000000001)
+	assert.InDelta(t, 0.055, gasPriceFromMultiplier(curve, 1.5), 0.000000000001)
+	assert.Equal(t, 0.1, gasPriceFromMultiplier(curve, 2))
+	assert.Equal(t, 0.1, gasPriceFromMultiplier(curve, 10))
+
+	// the default curve is used when none is configured
+	assert.Equal(t, 0.25, gasPriceFromMultiplier(nil, 3))
+	assert.InDelta(t, 0.005, gasPriceFromMultiplier(nil, 1), 0.000000000001)
+}
+
 func TestConstructionMetadata_SignerData(t *testing.T) {
 	t.Skip()
 	servicer, mockClient := setupConstructionAPIServicer()
@@ -0,0 +1,144 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// multisigSigner describes a legacy amino threshold multisig signer by its threshold and the
+// hex encoded compressed secp256k1 public keys of its members, in order
+type multisigSigner struct {
+	Threshold  uint32   `json:"threshold"`
+	PublicKeys []string `json:"public_keys"`
+}
+
+// getMultisigFromMetadata returns the public key of the optional multisig signer, given as an
+// object or its json encoding
+func getMultisigFromMetadata(metadata map[string]interface{}) (*kmultisig.LegacyAminoPubKey, error) {
+	rawMultisig, ok := metadata[multisigMetadataKey]
+	if !ok {
+		return nil, nil
+	}
+
+	encodedMultisig, ok := rawMultisig.(string)
+	if !ok {
+		encoded, err := json.Marshal(rawMultisig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", multisigMetadataKey, err)
+		}
+		encodedMultisig = string(encoded)
+	}
+
+	var signer multisigSigner
+	if err := json.Unmarshal([]byte(encodedMultisig), &signer); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", multisigMetadataKey, err)
+	}
+
+	if signer.Threshold == 0 || int(signer.Threshold) > len(signer.PublicKeys) {
+		return nil, fmt.Errorf("invalid multisig threshold %d of %d public keys", signer.Threshold, len(signer.PublicKeys))
+	}
+
+	pubKeys := make([]cryptotypes.PubKey, len(signer.PublicKeys))
+	for i, rawPubKey := range signer.PublicKeys {
+		pubKeyBytes, err := hex.DecodeString(rawPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multisig public key %s", rawPubKey)
+		}
+
+		pubKey, rerr := parsePublicKey(&types.PublicKey{Bytes: pubKeyBytes, CurveType: types.Secp256k1})
+		if rerr != nil {
+			return nil, fmt.Errorf("invalid multisig public key %s", rawPubKey)
+		}
+
+		pubKeys[i] = &secp256k1.PubKey{Key: pubKey}
+	}
+
+	return kmultisig.NewLegacyAminoPubKey(int(signer.Threshold), pubKeys), nil
+}
+
+// encodeMultisig returns the json encoding of a multisig signer passed between endpoints
+func encodeMultisig(pubKey *kmultisig.LegacyAminoPubKey) (string, error) {
+	signer := multisigSigner{Threshold: pubKey.Threshold}
+	for _, member := range pubKey.GetPubKeys() {
+		signer.PublicKeys = append(signer.PublicKeys, hex.EncodeToString(member.Bytes()))
+	}
+
+	encoded, err := json.Marshal(signer)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// expandMultisigSigner replaces the multisig in the required signers with its members, which
+// each sign a payload
+func expandMultisigSigner(
+	signers []*types.AccountIdentifier,
+	pubKey *kmultisig.LegacyAminoPubKey,
+) ([]*types.AccountIdentifier, *types.Error) {
+	address := sdk.AccAddress(pubKey.Address()).String()
+	expanded := []*types.AccountIdentifier{}
+	found := false
+
+	for _, signer := range signers {
+		if signer.Address != address {
+			expanded = append(expanded, signer)
+			continue
+		}
+
+		found = true
+		for _, member := range pubKey.GetPubKeys() {
+			expanded = append(expanded, &types.AccountIdentifier{
+				Address: sdk.AccAddress(member.Address()).String(),
+			})
+		}
+	}
+
+	if !found {
+		return nil, wrapErr(ErrInvalidMetadata, fmt.Errorf("multisig %s is not a signer of the transaction", address))
+	}
+
+	return expanded, nil
+}
+
+// multisigSimulationSignature returns empty member signatures for the threshold of a multisig,
+// used to simulate transactions before they are signed
+func multisigSimulationSignature(pubKey *kmultisig.LegacyAminoPubKey, sequence uint64) signing.SignatureV2 {
+	data := multisig.NewMultisig(len(pubKey.PubKeys))
+	for i := 0; i < int(pubKey.Threshold); i++ {
+		data.BitArray.SetIndex(i, true)
+		data.Signatures = append(data.Signatures, &signing.SingleSignatureData{
+			SignMode: signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+		})
+	}
+
+	return signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     data,
+		Sequence: sequence,
+	}
+}
@@ -0,0 +1,1053 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	evmutiltypes "github.com/kava-labs/kava/x/evmutil/types"
+)
+
+const (
+	// stakingDenom is the only denom that may be delegated
+	stakingDenom = "ukava"
+
+	validatorAddressMetadataKey    = "validator_address"
+	validatorSrcAddressMetadataKey = "validator_src_address"
+	validatorDstAddressMetadataKey = "validator_dst_address"
+	evmAddressMetadataKey          = "evm_address"
+	sourcePortMetadataKey          = "source_port"
+	sourceChannelMetadataKey       = "source_channel"
+	receiverMetadataKey            = "receiver"
+	timeoutHeightMetadataKey       = "timeout_height"
+	timeoutTimestampMetadataKey    = "timeout_timestamp"
+	granteeMetadataKey             = "grantee"
+	msgTypeURLMetadataKey          = "msg_type_url"
+	spendLimitMetadataKey          = "spend_limit"
+	expirationMetadataKey          = "expiration"
+	feePayerMetadataKey            = "fee_payer"
+	feeGranterMetadataKey          = "fee_granter"
+	multisigMetadataKey            = "multisig"
+)
+
+// resolvedValues are the values resolved by /construction/metadata for operations that
+// leave them to the node
+type resolvedValues struct {
+	ibcTimeoutTimestamp uint64
+}
+
+// parseOperationMsgs converts construction operations into the sdk messages they describe.
+// Ibc transfers without a timeout are only completed when resolved is not nil.
+func parseOperationMsgs(registry *kava.CurrencyRegistry, ops []*types.Operation, resolved *resolvedValues) ([]sdk.Msg, *types.Error) {
+	if len(ops) == 0 {
+		return nil, ErrNoOperations
+	}
+
+	switch ops[0].Type {
+	case kava.DelegateOpType, kava.UndelegateOpType, kava.RedelegateOpType, kava.WithdrawRewardsOpType:
+		return parseStakingOperations(registry, ops)
+	case kava.ConvertCoinToERC20OpType, kava.ConvertERC20ToCoinOpType:
+		return parseConversionOperations(registry, ops)
+	case kava.IBCTransferOpType:
+		return parseIBCTransferOperations(registry, ops, resolved)
+	case kava.AuthzGrantOpType, kava.AuthzRevokeOpType:
+		return parseAuthzOperations(ops)
+	}
+
+	msgs, rerr := parseTransferOperations(registry, ops)
+	if rerr != nil {
+		return nil, rerr
+	}
+
+	// transfers with a grantee are executed by the grantee on behalf of the senders
+	grantee, rerr := getExecGrantee(ops)
+	if rerr != nil {
+		return nil, rerr
+	}
+	if grantee != nil {
+		exec := authz.NewMsgExec(grantee, msgs)
+		return []sdk.Msg{&exec}, nil
+	}
+
+	return msgs, nil
+}
+
+// getExecGrantee returns the grantee executing transfers on behalf of their senders, or nil when
+// the operations do not have a grantee.  A grantee must be provided on every debit operation.
+func getExecGrantee(ops []*types.Operation) (sdk.AccAddress, *types.Error) {
+	var grantee string
+	debits := 0
+	granted := 0
+
+	for _, op := range ops {
+		debit := strings.HasPrefix(op.Amount.Value, "-")
+		if debit {
+			debits++
+		}
+
+		rawGrantee, ok := op.Metadata[granteeMetadataKey]
+		if !ok {
+			continue
+		}
+
+		value, ok := rawGrantee.(string)
+		if !ok || !debit || (grantee != "" && value != grantee) {
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("%s must be the same on every debit operation", granteeMetadataKey))
+		}
+
+		grantee = value
+		granted++
+	}
+
+	if granted == 0 {
+		return nil, nil
+	}
+
+	if granted != debits {
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("%s must be the same on every debit operation", granteeMetadataKey))
+	}
+
+	addr, err := sdk.AccAddressFromBech32(grantee)
+	if err != nil {
+		return nil, ErrInvalidAddress
+	}
+
+	return addr, nil
+}
+
+// transfer is a single validated transfer operation
+type transfer struct {
+	address sdk.AccAddress
+	coin    sdk.Coin
+	debit   bool
+}
+
+// transferGroup is a sender with the debits built into a single bank message and the outputs
+// they pay
+type transferGroup struct {
+	sender     sdk.AccAddress
+	debits     []int
+	recipients []sdk.AccAddress
+	outputs    []banktypes.Output
+}
+
+// parseTransferOperations converts a balanced set of transfer operations into bank messages, in
+// any order.  Credits are matched to the debits of their denom, preferring the nearest debit before
+// them that pays the full amount, then the smallest debit that does, and otherwise paid by several
+// debits.  The debits of a sender are built into one message with an output for each credit they
+// pay, and a further message when the sender has more than one debit of a denom.  A single debit
+// paying a single credit is built as a MsgSend and any other message as a MsgMultiSend.  Credits
+// paid by more than one debit parse back as a credit from each sender.
+func parseTransferOperations(registry *kava.CurrencyRegistry, ops []*types.Operation) ([]sdk.Msg, *types.Error) {
+	transfers := make([]transfer, 0, len(ops))
+	net := make(map[string]sdkmath.Int)
+
+	for _, op := range ops {
+		if op.Type != kava.TransferOpType {
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("invalid operation type, only '%s' allowed", kava.TransferOpType))
+		}
+
+		if op.Amount == nil {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		value, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		if value.Sign() == 0 {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		addr, rerr := getAddressFromAccount(op.Account)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		coin, rerr := amountToCoin(registry, &types.Amount{
+			Value:    new(big.Int).Abs(value).String(),
+			Currency: op.Amount.Currency,
+		})
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		transfers = append(transfers, transfer{address: addr, coin: coin, debit: value.Sign() < 0})
+
+		sum, ok := net[coin.Denom]
+		if !ok {
+			sum = sdkmath.ZeroInt()
+		}
+		net[coin.Denom] = sum.Add(sdkmath.NewIntFromBigInt(value))
+	}
+
+	for denom, sum := range net {
+		if !sum.IsZero() {
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("transfer operations for %s do not net to zero", denom))
+		}
+	}
+
+	groups := groupTransferDebits(transfers)
+	groupOf := make(map[int]*transferGroup)
+	for _, group := range groups {
+		for _, i := range group.debits {
+			groupOf[i] = group
+		}
+	}
+
+	remaining := make(map[int]sdkmath.Int)
+	for i, t := range transfers {
+		if t.debit {
+			remaining[i] = t.coin.Amount
+		}
+	}
+
+	for i, t := range transfers {
+		if t.debit {
+			continue
+		}
+
+		for _, payment := range matchCredit(transfers, remaining, i) {
+			group := groupOf[payment.debit]
+			group.recipients = append(group.recipients, t.address)
+			group.outputs = append(group.outputs, banktypes.NewOutput(t.address, sdk.NewCoins(payment.coin)))
+		}
+	}
+
+	msgs := []sdk.Msg{}
+	for _, group := range groups {
+		input := sdk.NewCoins()
+		for _, i := range group.debits {
+			input = input.Add(transfers[i].coin)
+		}
+
+		if len(group.debits) == 1 && len(group.outputs) == 1 {
+			msgs = append(msgs, banktypes.NewMsgSend(group.sender, group.recipients[0], input))
+			continue
+		}
+
+		msgs = append(msgs, banktypes.NewMsgMultiSend([]banktypes.Input{banktypes.NewInput(group.sender, input)}, group.outputs))
+	}
+
+	return msgs, nil
+}
+
+// groupTransferDebits returns the groups of debits built into one message, in the order of their
+// first debit.  Each debit joins the first group of its sender without a debit of its denom.
+func groupTransferDebits(transfers []transfer) []*transferGroup {
+	groups := []*transferGroup{}
+
+	for i, t := range transfers {
+		if !t.debit {
+			continue
+		}
+
+		var found *transferGroup
+		for _, group := range groups {
+			if group.sender.Equals(t.address) && !groupHasDenom(transfers, group, t.coin.Denom) {
+				found = group
+				break
+			}
+		}
+
+		if found == nil {
+			found = &transferGroup{sender: t.address}
+			groups = append(groups, found)
+		}
+		found.debits = append(found.debits, i)
+	}
+
+	return groups
+}
+
+func groupHasDenom(transfers []transfer, group *transferGroup, denom string) bool {
+	for _, i := range group.debits {
+		if transfers[i].coin.Denom == denom {
+			return true
+		}
+	}
+
+	return false
+}
+
+// payment is the part of a credit paid by a debit
+type payment struct {
+	debit int
+	coin  sdk.Coin
+}
+
+// matchCredit pays the credit at index i from the remaining amounts of the debits of its denom.
+// The nearest debit before the credit that pays the full amount is used, then the smallest debit
+// that does, and otherwise the debits in order until the credit is paid.
+func matchCredit(transfers []transfer, remaining map[int]sdkmath.Int, i int) []payment {
+	credit := transfers[i].coin
+	pays := func(j int) bool {
+		return transfers[j].debit && transfers[j].coin.Denom == credit.Denom && remaining[j].GTE(credit.Amount)
+	}
+
+	for j := i - 1; j >= 0; j-- {
+		if pays(j) {
+			remaining[j] = remaining[j].Sub(credit.Amount)
+			return []payment{{debit: j, coin: credit}}
+		}
+	}
+
+	best := -1
+	for j := range transfers {
+		if pays(j) && (best < 0 || remaining[j].LT(remaining[best])) {
+			best = j
+		}
+	}
+	if best >= 0 {
+		remaining[best] = remaining[best].Sub(credit.Amount)
+		return []payment{{debit: best, coin: credit}}
+	}
+
+	// the transfers net to zero, so the debits of the denom always pay the full credit
+	payments := []payment{}
+	unpaid := credit.Amount
+	for j, t := range transfers {
+		if unpaid.IsZero() {
+			break
+		}
+		if !t.debit || t.coin.Denom != credit.Denom || remaining[j].IsZero() {
+			continue
+		}
+
+		amount := sdkmath.MinInt(unpaid, remaining[j])
+		remaining[j] = remaining[j].Sub(amount)
+		unpaid = unpaid.Sub(amount)
+		payments = append(payments, payment{debit: j, coin: sdk.NewCoin(credit.Denom, amount)})
+	}
+
+	return payments
+}
+
+// parseStakingOperations returns one staking message for each operation.  Delegations
+// debit the delegator and are negative, while undelegations and redelegations are positive.
+// Reward withdraws do not have an amount and withdraw from a single validator, so they
+// parse back to the same operation.  Withdrawing from every validator is not expanded from
+// the delegations of the delegator, since the transaction would not parse back to the intent.
+func parseStakingOperations(registry *kava.CurrencyRegistry, ops []*types.Operation) ([]sdk.Msg, *types.Error) {
+	msgs := []sdk.Msg{}
+
+	for _, op := range ops {
+		delegator, rerr := getAddressFromAccount(op.Account)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		if op.Type == kava.WithdrawRewardsOpType {
+			if op.Amount != nil {
+				return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("%s operations must not have an amount", op.Type))
+			}
+
+			validator, rerr := getValidatorAddressFromMetadata(op.Metadata, validatorAddressMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			msgs = append(msgs, distrtypes.NewMsgWithdrawDelegatorReward(delegator, validator))
+			continue
+		}
+
+		coin, rerr := stakingAmountToCoin(registry, op)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		switch op.Type {
+		case kava.DelegateOpType:
+			validator, rerr := getValidatorAddressFromMetadata(op.Metadata, validatorAddressMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			msgs = append(msgs, stakingtypes.NewMsgDelegate(delegator, validator, coin))
+		case kava.UndelegateOpType:
+			validator, rerr := getValidatorAddressFromMetadata(op.Metadata, validatorAddressMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			msgs = append(msgs, stakingtypes.NewMsgUndelegate(delegator, validator, coin))
+		case kava.RedelegateOpType:
+			srcValidator, rerr := getValidatorAddressFromMetadata(op.Metadata, validatorSrcAddressMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			dstValidator, rerr := getValidatorAddressFromMetadata(op.Metadata, validatorDstAddressMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			msgs = append(msgs, stakingtypes.NewMsgBeginRedelegate(delegator, srcValidator, dstValidator, coin))
+		default:
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+				"invalid operation type '%s', staking operations can not be combined with other types", op.Type,
+			))
+		}
+	}
+
+	return msgs, nil
+}
+
+// parseConversionOperations returns one evmutil conversion message for each operation.  Coin to
+// erc20 conversions debit the initiator and are negative.  Erc20 to coin conversions credit the
+// receiver, are positive and use the currency of a registered erc20 contract.
+func parseConversionOperations(registry *kava.CurrencyRegistry, ops []*types.Operation) ([]sdk.Msg, *types.Error) {
+	msgs := []sdk.Msg{}
+
+	for _, op := range ops {
+		account, rerr := getAddressFromAccount(op.Account)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		evmAddress, rerr := getEVMAddressFromMetadata(op.Metadata)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		if op.Amount == nil {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		value, err := types.AmountValue(op.Amount)
+		if err != nil {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		switch op.Type {
+		case kava.ConvertCoinToERC20OpType:
+			if value.Sign() >= 0 {
+				return nil, ErrInvalidCurrencyAmount
+			}
+
+			coin, rerr := amountToCoin(registry, &types.Amount{
+				Value:    new(big.Int).Abs(value).String(),
+				Currency: op.Amount.Currency,
+			})
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			msg := evmutiltypes.NewMsgConvertCoinToERC20(account.String(), evmAddress.Hex(), coin)
+			msgs = append(msgs, &msg)
+		case kava.ConvertERC20ToCoinOpType:
+			if value.Sign() <= 0 {
+				return nil, ErrInvalidCurrencyAmount
+			}
+
+			contract, ok := registry.ERC20Contract(op.Amount.Currency)
+			if !ok {
+				return nil, ErrUnsupportedCurrency
+			}
+
+			msg := evmutiltypes.NewMsgConvertERC20ToCoin(
+				evmutiltypes.NewInternalEVMAddress(evmAddress),
+				account,
+				evmutiltypes.NewInternalEVMAddress(contract),
+				sdkmath.NewIntFromBigInt(value),
+			)
+			msgs = append(msgs, &msg)
+		default:
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+				"invalid operation type '%s', conversion operations can not be combined with other types", op.Type,
+			))
+		}
+	}
+
+	return msgs, nil
+}
+
+func getEVMAddressFromMetadata(metadata map[string]interface{}) (common.Address, *types.Error) {
+	rawAddress, ok := metadata[evmAddressMetadataKey].(string)
+	if !ok || rawAddress == "" {
+		return common.Address{}, wrapErr(ErrUnclearIntent, fmt.Errorf("no %s provided in operation metadata", evmAddressMetadataKey))
+	}
+
+	if !common.IsHexAddress(rawAddress) {
+		return common.Address{}, ErrInvalidAddress
+	}
+
+	return common.HexToAddress(rawAddress), nil
+}
+
+// parseIBCTransferOperations returns one ibc transfer message for each operation, debiting the
+// sender.  Transfers without a timeout height or timestamp use the timeout timestamp resolved
+// by /construction/metadata.
+func parseIBCTransferOperations(registry *kava.CurrencyRegistry, ops []*types.Operation, resolved *resolvedValues) ([]sdk.Msg, *types.Error) {
+	msgs := []sdk.Msg{}
+
+	for _, op := range ops {
+		if op.Type != kava.IBCTransferOpType {
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+				"invalid operation type '%s', ibc transfer operations can not be combined with other types", op.Type,
+			))
+		}
+
+		sender, rerr := getAddressFromAccount(op.Account)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		if op.Amount == nil {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		value, err := types.AmountValue(op.Amount)
+		if err != nil || value.Sign() >= 0 {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		coin, rerr := amountToCoin(registry, &types.Amount{
+			Value:    new(big.Int).Abs(value).String(),
+			Currency: op.Amount.Currency,
+		})
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		sourcePort := ibctransfertypes.PortID
+		if _, ok := op.Metadata[sourcePortMetadataKey]; ok {
+			sourcePort, rerr = getStringFromMetadata(op.Metadata, sourcePortMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+		}
+
+		sourceChannel, rerr := getStringFromMetadata(op.Metadata, sourceChannelMetadataKey)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		receiver, rerr := getStringFromMetadata(op.Metadata, receiverMetadataKey)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		timeoutHeight, timeoutTimestamp, rerr := getIBCTimeoutFromMetadata(op.Metadata)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		if resolved != nil && timeoutHeight.IsZero() && timeoutTimestamp == 0 {
+			if resolved.ibcTimeoutTimestamp == 0 {
+				return nil, wrapErr(ErrInvalidMetadata, errors.New("no ibc_timeout_timestamp provided"))
+			}
+
+			timeoutTimestamp = resolved.ibcTimeoutTimestamp
+		}
+
+		msg := ibctransfertypes.NewMsgTransfer(
+			sourcePort,
+			sourceChannel,
+			coin,
+			sender.String(),
+			receiver,
+			timeoutHeight,
+			timeoutTimestamp,
+			"",
+		)
+		if err := msg.ValidateBasic(); err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+func getStringFromMetadata(metadata map[string]interface{}, key string) (string, *types.Error) {
+	value, ok := metadata[key].(string)
+	if !ok || value == "" {
+		return "", wrapErr(ErrUnclearIntent, fmt.Errorf("no %s provided in operation metadata, each operation requires the address of the validator it acts on", key))
+	}
+
+	return value, nil
+}
+
+// getIBCTimeoutFromMetadata returns the optional timeout height, formatted as
+// {revision number}-{revision height}, and timeout timestamp in unix nanoseconds of an ibc transfer
+func getIBCTimeoutFromMetadata(metadata map[string]interface{}) (clienttypes.Height, uint64, *types.Error) {
+	timeoutHeight := clienttypes.ZeroHeight()
+	if _, ok := metadata[timeoutHeightMetadataKey]; ok {
+		rawHeight, rerr := getStringFromMetadata(metadata, timeoutHeightMetadataKey)
+		if rerr != nil {
+			return clienttypes.Height{}, 0, rerr
+		}
+
+		height, err := clienttypes.ParseHeight(rawHeight)
+		if err != nil {
+			return clienttypes.Height{}, 0, wrapErr(ErrUnclearIntent, fmt.Errorf("invalid %s in operation metadata: %w", timeoutHeightMetadataKey, err))
+		}
+		timeoutHeight = height
+	}
+
+	var timeoutTimestamp uint64
+	if _, ok := metadata[timeoutTimestampMetadataKey]; ok {
+		rawTimestamp, rerr := getStringFromMetadata(metadata, timeoutTimestampMetadataKey)
+		if rerr != nil {
+			return clienttypes.Height{}, 0, rerr
+		}
+
+		timestamp, err := strconv.ParseUint(rawTimestamp, 10, 64)
+		if err != nil {
+			return clienttypes.Height{}, 0, wrapErr(ErrUnclearIntent, fmt.Errorf("invalid %s in operation metadata: %w", timeoutTimestampMetadataKey, err))
+		}
+		timeoutTimestamp = timestamp
+	}
+
+	return timeoutHeight, timeoutTimestamp, nil
+}
+
+// parseAuthzOperations returns an authz grant or revoke message for each operation, granted or
+// revoked by the operation account.  Grants with a spend_limit are bank send authorizations, and
+// other grants and revokes apply to the msg_type_url, which defaults to bank sends.
+func parseAuthzOperations(ops []*types.Operation) ([]sdk.Msg, *types.Error) {
+	msgs := []sdk.Msg{}
+
+	for _, op := range ops {
+		if op.Type != kava.AuthzGrantOpType && op.Type != kava.AuthzRevokeOpType {
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+				"invalid operation type '%s', authz operations can not be combined with other types", op.Type,
+			))
+		}
+
+		if op.Amount != nil {
+			return nil, ErrInvalidCurrencyAmount
+		}
+
+		granter, rerr := getAddressFromAccount(op.Account)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		rawGrantee, rerr := getStringFromMetadata(op.Metadata, granteeMetadataKey)
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		grantee, err := sdk.AccAddressFromBech32(rawGrantee)
+		if err != nil {
+			return nil, ErrInvalidAddress
+		}
+
+		msgTypeURL := sdk.MsgTypeURL(&banktypes.MsgSend{})
+		if _, ok := op.Metadata[msgTypeURLMetadataKey]; ok {
+			msgTypeURL, rerr = getStringFromMetadata(op.Metadata, msgTypeURLMetadataKey)
+			if rerr != nil {
+				return nil, rerr
+			}
+		}
+
+		var msg sdk.Msg
+		if op.Type == kava.AuthzGrantOpType {
+			authorization, rerr := getAuthorizationFromMetadata(op.Metadata, msgTypeURL)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			expiration, rerr := getExpirationFromMetadata(op.Metadata)
+			if rerr != nil {
+				return nil, rerr
+			}
+
+			msg, err = authz.NewMsgGrant(granter, grantee, authorization, expiration)
+			if err != nil {
+				return nil, wrapErr(ErrUnclearIntent, err)
+			}
+		} else {
+			revoke := authz.NewMsgRevoke(granter, grantee, msgTypeURL)
+			msg = &revoke
+		}
+
+		if err := msg.ValidateBasic(); err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+func getAuthorizationFromMetadata(metadata map[string]interface{}, msgTypeURL string) (authz.Authorization, *types.Error) {
+	if _, ok := metadata[spendLimitMetadataKey]; !ok {
+		return authz.NewGenericAuthorization(msgTypeURL), nil
+	}
+
+	if msgTypeURL != sdk.MsgTypeURL(&banktypes.MsgSend{}) {
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("%s only applies to %s", spendLimitMetadataKey, sdk.MsgTypeURL(&banktypes.MsgSend{})))
+	}
+
+	rawSpendLimit, rerr := getStringFromMetadata(metadata, spendLimitMetadataKey)
+	if rerr != nil {
+		return nil, rerr
+	}
+
+	spendLimit, err := sdk.ParseCoinsNormalized(rawSpendLimit)
+	if err != nil {
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("invalid %s in operation metadata: %w", spendLimitMetadataKey, err))
+	}
+
+	return banktypes.NewSendAuthorization(spendLimit, nil), nil
+}
+
+// getExpirationFromMetadata returns the optional RFC3339 expiration of an authz grant
+func getExpirationFromMetadata(metadata map[string]interface{}) (*time.Time, *types.Error) {
+	if _, ok := metadata[expirationMetadataKey]; !ok {
+		return nil, nil
+	}
+
+	rawExpiration, rerr := getStringFromMetadata(metadata, expirationMetadataKey)
+	if rerr != nil {
+		return nil, rerr
+	}
+
+	expiration, err := time.Parse(time.RFC3339, rawExpiration)
+	if err != nil {
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("invalid %s in operation metadata: %w", expirationMetadataKey, err))
+	}
+
+	return &expiration, nil
+}
+
+func stakingAmountToCoin(registry *kava.CurrencyRegistry, op *types.Operation) (sdk.Coin, *types.Error) {
+	if op.Amount == nil {
+		return sdk.Coin{}, ErrInvalidCurrencyAmount
+	}
+
+	value, err := types.AmountValue(op.Amount)
+	if err != nil {
+		return sdk.Coin{}, ErrInvalidCurrencyAmount
+	}
+
+	expectedSign := 1
+	if op.Type == kava.DelegateOpType {
+		expectedSign = -1
+	}
+
+	if value.Sign() != expectedSign {
+		return sdk.Coin{}, ErrInvalidCurrencyAmount
+	}
+
+	coin, rerr := amountToCoin(registry, &types.Amount{
+		Value:    new(big.Int).Abs(value).String(),
+		Currency: op.Amount.Currency,
+	})
+	if rerr != nil {
+		return sdk.Coin{}, rerr
+	}
+
+	if coin.Denom != stakingDenom {
+		return sdk.Coin{}, ErrUnsupportedCurrency
+	}
+
+	return coin, nil
+}
+
+func getValidatorAddressFromMetadata(metadata map[string]interface{}, key string) (sdk.ValAddress, *types.Error) {
+	rawAddress, ok := metadata[key].(string)
+	if !ok || rawAddress == "" {
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("no %s provided in operation metadata, each operation requires the address of the validator it acts on", key))
+	}
+
+	addr, err := sdk.ValAddressFromBech32(rawAddress)
+	if err != nil {
+		return nil, ErrInvalidAddress
+	}
+
+	return addr, nil
+}
+
+// msgToOperations converts a sdk message built by the construction api back into
+// the operations used to construct it
+func msgToOperations(registry *kava.CurrencyRegistry, msg sdk.Msg, index int64) []*types.Operation {
+	switch m := msg.(type) {
+	case *banktypes.MsgSend:
+		return msgSendToOperations(registry, m, index)
+	case *banktypes.MsgMultiSend:
+		return msgMultiSendToOperations(registry, m, index)
+	case *stakingtypes.MsgDelegate:
+		return singleAccountOperation(registry, kava.DelegateOpType, m.DelegatorAddress, m.Amount, true, map[string]interface{}{
+			validatorAddressMetadataKey: m.ValidatorAddress,
+		}, index)
+	case *stakingtypes.MsgUndelegate:
+		return singleAccountOperation(registry, kava.UndelegateOpType, m.DelegatorAddress, m.Amount, false, map[string]interface{}{
+			validatorAddressMetadataKey: m.ValidatorAddress,
+		}, index)
+	case *stakingtypes.MsgBeginRedelegate:
+		return singleAccountOperation(registry, kava.RedelegateOpType, m.DelegatorAddress, m.Amount, false, map[string]interface{}{
+			validatorSrcAddressMetadataKey: m.ValidatorSrcAddress,
+			validatorDstAddressMetadataKey: m.ValidatorDstAddress,
+		}, index)
+	case *evmutiltypes.MsgConvertCoinToERC20:
+		if m.Amount == nil {
+			return []*types.Operation{}
+		}
+
+		return singleAccountOperation(registry, kava.ConvertCoinToERC20OpType, m.Initiator, *m.Amount, true, map[string]interface{}{
+			evmAddressMetadataKey: m.Receiver,
+		}, index)
+	case *evmutiltypes.MsgConvertERC20ToCoin:
+		return msgConvertERC20ToCoinToOperations(registry, m, index)
+	case *ibctransfertypes.MsgTransfer:
+		return singleAccountOperation(registry, kava.IBCTransferOpType, m.Sender, m.Token, true, kava.IBCTransferMetadata(m), index)
+	case *authz.MsgExec:
+		return msgExecToOperations(registry, m, index)
+	case *authz.MsgGrant:
+		return msgGrantToOperations(m, index)
+	case *authz.MsgRevoke:
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{Index: index},
+				Type:                kava.AuthzRevokeOpType,
+				Account:             &types.AccountIdentifier{Address: m.Granter},
+				Metadata: map[string]interface{}{
+					granteeMetadataKey:    m.Grantee,
+					msgTypeURLMetadataKey: m.MsgTypeUrl,
+				},
+			},
+		}
+	case *distrtypes.MsgWithdrawDelegatorReward:
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{Index: index},
+				Type:                kava.WithdrawRewardsOpType,
+				Account:             &types.AccountIdentifier{Address: m.DelegatorAddress},
+				Metadata: map[string]interface{}{
+					validatorAddressMetadataKey: m.ValidatorAddress,
+				},
+			},
+		}
+	}
+
+	return []*types.Operation{}
+}
+
+func msgSendToOperations(registry *kava.CurrencyRegistry, msgSend *banktypes.MsgSend, index int64) []*types.Operation {
+	ops := []*types.Operation{}
+
+	for _, coin := range msgSend.Amount {
+		currency, ok := registry.Currency(coin.Denom)
+		if !ok {
+			continue
+		}
+
+		ops = append(ops, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{Index: index},
+			Type:                kava.TransferOpType,
+			Account:             &types.AccountIdentifier{Address: msgSend.FromAddress},
+			Amount:              &types.Amount{Value: "-" + coin.Amount.String(), Currency: currency},
+		})
+
+		ops = append(ops, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{Index: index + 1},
+			RelatedOperations:   []*types.OperationIdentifier{&types.OperationIdentifier{Index: index}},
+			Type:                kava.TransferOpType,
+			Account:             &types.AccountIdentifier{Address: msgSend.ToAddress},
+			Amount:              &types.Amount{Value: coin.Amount.String(), Currency: currency},
+		})
+
+		index += 2
+	}
+
+	return ops
+}
+
+// msgMultiSendToOperations returns the input operations followed by an operation for each output
+// coin.  Output operations are related to the input operation of the same currency.
+func msgMultiSendToOperations(registry *kava.CurrencyRegistry, msg *banktypes.MsgMultiSend, index int64) []*types.Operation {
+	ops := []*types.Operation{}
+	inputIndexes := make(map[string]int64)
+
+	for _, input := range msg.Inputs {
+		for _, coin := range input.Coins {
+			currency, ok := registry.Currency(coin.Denom)
+			if !ok {
+				continue
+			}
+
+			inputIndexes[coin.Denom] = index
+			ops = append(ops, &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{Index: index},
+				Type:                kava.TransferOpType,
+				Account:             &types.AccountIdentifier{Address: input.Address},
+				Amount:              &types.Amount{Value: "-" + coin.Amount.String(), Currency: currency},
+			})
+			index++
+		}
+	}
+
+	for _, output := range msg.Outputs {
+		for _, coin := range output.Coins {
+			currency, ok := registry.Currency(coin.Denom)
+			if !ok {
+				continue
+			}
+
+			op := &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{Index: index},
+				Type:                kava.TransferOpType,
+				Account:             &types.AccountIdentifier{Address: output.Address},
+				Amount:              &types.Amount{Value: coin.Amount.String(), Currency: currency},
+			}
+			if inputIndex, ok := inputIndexes[coin.Denom]; ok {
+				op.RelatedOperations = []*types.OperationIdentifier{{Index: inputIndex}}
+			}
+
+			ops = append(ops, op)
+			index++
+		}
+	}
+
+	return ops
+}
+
+// msgConvertERC20ToCoinToOperations returns the conversion operation of the receiver, without an
+// amount when the erc20 contract is not registered
+func msgConvertERC20ToCoinToOperations(registry *kava.CurrencyRegistry, msg *evmutiltypes.MsgConvertERC20ToCoin, index int64) []*types.Operation {
+	op := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: index},
+		Type:                kava.ConvertERC20ToCoinOpType,
+		Account:             &types.AccountIdentifier{Address: msg.Receiver},
+		Metadata: map[string]interface{}{
+			evmAddressMetadataKey: msg.Initiator,
+		},
+	}
+
+	if common.IsHexAddress(msg.KavaERC20Address) {
+		if currency, ok := registry.ERC20Currency(common.HexToAddress(msg.KavaERC20Address)); ok {
+			op.Amount = &types.Amount{Value: msg.Amount.String(), Currency: currency}
+		}
+	}
+
+	return []*types.Operation{op}
+}
+
+// msgExecToOperations returns the operations of the executed messages, with the grantee in the
+// metadata of the debit operations
+func msgExecToOperations(registry *kava.CurrencyRegistry, msg *authz.MsgExec, index int64) []*types.Operation {
+	ops := []*types.Operation{}
+
+	msgs, err := msg.GetMessages()
+	if err != nil {
+		return ops
+	}
+
+	for _, execMsg := range msgs {
+		execOps := msgToOperations(registry, execMsg, index)
+		for _, op := range execOps {
+			if op.Amount == nil || !strings.HasPrefix(op.Amount.Value, "-") {
+				continue
+			}
+
+			if op.Metadata == nil {
+				op.Metadata = make(map[string]interface{})
+			}
+			op.Metadata[granteeMetadataKey] = msg.Grantee
+		}
+
+		ops = append(ops, execOps...)
+		index += int64(len(execOps))
+	}
+
+	return ops
+}
+
+// msgGrantToOperations returns the grant operation of the granter, with the spend limit of send
+// authorizations or the message type url of other authorizations
+func msgGrantToOperations(msg *authz.MsgGrant, index int64) []*types.Operation {
+	authorization, err := msg.GetAuthorization()
+	if err != nil {
+		return []*types.Operation{}
+	}
+
+	metadata := map[string]interface{}{
+		granteeMetadataKey: msg.Grantee,
+	}
+
+	if sendAuthorization, ok := authorization.(*banktypes.SendAuthorization); ok {
+		metadata[spendLimitMetadataKey] = sendAuthorization.SpendLimit.String()
+	} else {
+		metadata[msgTypeURLMetadataKey] = authorization.MsgTypeURL()
+	}
+
+	if msg.Grant.Expiration != nil {
+		metadata[expirationMetadataKey] = msg.Grant.Expiration.UTC().Format(time.RFC3339)
+	}
+
+	return []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: index},
+			Type:                kava.AuthzGrantOpType,
+			Account:             &types.AccountIdentifier{Address: msg.Granter},
+			Metadata:            metadata,
+		},
+	}
+}
+
+// singleAccountOperation returns the operation of a message that changes the balance of one account
+func singleAccountOperation(
+	registry *kava.CurrencyRegistry,
+	opType string,
+	address string,
+	coin sdk.Coin,
+	negative bool,
+	metadata map[string]interface{},
+	index int64,
+) []*types.Operation {
+	currency, ok := registry.Currency(coin.Denom)
+	if !ok {
+		return []*types.Operation{}
+	}
+
+	value := coin.Amount.String()
+	if negative {
+		value = "-" + value
+	}
+
+	return []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: index},
+			Type:                opType,
+			Account:             &types.AccountIdentifier{Address: address},
+			Amount:              &types.Amount{Value: value, Currency: currency},
+			Metadata:            metadata,
+		},
+	}
+}
@@ -0,0 +1,1368 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v7/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v7/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	evmutiltypes "github.com/kava-labs/kava/x/evmutil/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDelegatorAddress    = "kava1vlpsrmdyuywvaqrv7rx6xga224sqfwz3fyfhwq"
+	testValidatorAddress    = sdk.ValAddress([]byte("test validator one")).String()
+	testValidatorDstAddress = sdk.ValAddress([]byte("test validator two")).String()
+	testIBCReceiver         = "cosmos1vlpsrmdyuywvaqrv7rx6xga224sqfwz3t7gsv6"
+	testGranteeAddress      = "kava16g8lzm86f5wwf3x3t67qrpd46sjdpxpfazskwg"
+)
+
+func stakingOps() []*types.Operation {
+	return []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Type:                kava.DelegateOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Amount:              &types.Amount{Value: "-5000000", Currency: kava.Currencies["ukava"]},
+			Metadata: map[string]interface{}{
+				"validator_address": testValidatorAddress,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 1},
+			Type:                kava.UndelegateOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Amount:              &types.Amount{Value: "1000000", Currency: kava.Currencies["ukava"]},
+			Metadata: map[string]interface{}{
+				"validator_address": testValidatorAddress,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 2},
+			Type:                kava.RedelegateOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Amount:              &types.Amount{Value: "2000000", Currency: kava.Currencies["ukava"]},
+			Metadata: map[string]interface{}{
+				"validator_src_address": testValidatorAddress,
+				"validator_dst_address": testValidatorDstAddress,
+			},
+		},
+	}
+}
+
+func TestParseOperationMsgs_Staking(t *testing.T) {
+	msgs, rerr := parseOperationMsgs(testRegistry, stakingOps(), nil)
+	require.Nil(t, rerr)
+	require.Equal(t, 3, len(msgs))
+
+	delegator := mustAccAddressFromBech32(t, testDelegatorAddress)
+	validator, err := sdk.ValAddressFromBech32(testValidatorAddress)
+	require.NoError(t, err)
+	dstValidator, err := sdk.ValAddressFromBech32(testValidatorDstAddress)
+	require.NoError(t, err)
+
+	assert.Equal(t, stakingtypes.NewMsgDelegate(delegator, validator, sdk.NewCoin("ukava", sdkmath.NewInt(5000000))), msgs[0])
+	assert.Equal(t, stakingtypes.NewMsgUndelegate(delegator, validator, sdk.NewCoin("ukava", sdkmath.NewInt(1000000))), msgs[1])
+	assert.Equal(t, stakingtypes.NewMsgBeginRedelegate(delegator, validator, dstValidator, sdk.NewCoin("ukava", sdkmath.NewInt(2000000))), msgs[2])
+
+	ops := []*types.Operation{}
+	for _, msg := range msgs {
+		ops = append(ops, msgToOperations(testRegistry, msg, int64(len(ops)))...)
+	}
+	assert.Equal(t, stakingOps(), ops)
+}
+
+func TestParseOperationMsgs_InvalidStaking(t *testing.T) {
+	testCases := []struct {
+		name        string
+		modify      func(ops []*types.Operation) []*types.Operation
+		expectedErr *types.Error
+	}{
+		{
+			name: "positive delegation amount",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Amount.Value = "5000000"
+				return ops[:1]
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "negative undelegation amount",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Amount.Value = "-1000000"
+				return ops[1:2]
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "non staking denom",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Amount.Currency = kava.Currencies["hard"]
+				return ops[:1]
+			},
+			expectedErr: ErrUnsupportedCurrency,
+		},
+		{
+			name: "missing validator address",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata = nil
+				return ops[:1]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid validator address",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[2].Metadata["validator_dst_address"] = testDelegatorAddress
+				return ops[2:]
+			},
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name: "invalid delegator address",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Account.Address = testValidatorAddress
+				return ops[:1]
+			},
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name: "staking combined with transfer",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Type = kava.TransferOpType
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.modify(stakingOps()), nil)
+			assert.Nil(t, msgs)
+			require.NotNil(t, rerr)
+			assert.Equal(t, tc.expectedErr.Code, rerr.Code)
+			assert.Equal(t, tc.expectedErr.Message, rerr.Message)
+		})
+	}
+}
+
+func TestConstructionPayloadsAndParse_Staking(t *testing.T) {
+	signerPubKey, err := base64.StdEncoding.DecodeString("AsAbWjsqD1ntOiVZCNRdAm1nrSP8rwZoNNin85jPaeaY")
+	require.NoError(t, err)
+
+	encodedSigners, err := json.Marshal([]signerInfo{{AccountNumber: 10, AccountSequence: 11}})
+	require.NoError(t, err)
+
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: stakingOps(),
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(preprocessResponse.RequiredPublicKeys))
+	assert.Equal(t, testDelegatorAddress, preprocessResponse.RequiredPublicKeys[0].Address)
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        stakingOps(),
+		Metadata: map[string]interface{}{
+			"signers":    string(encodedSigners),
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: signerPubKey}},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+	assert.Equal(t, testDelegatorAddress, payloadsResponse.Payloads[0].AccountIdentifier.Address)
+
+	_, _, err = decodeUnsignedTx(payloadsResponse.UnsignedTransaction)
+	require.NoError(t, err)
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		Signed:      false,
+		Transaction: payloadsResponse.UnsignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, stakingOps(), parseResponse.Operations)
+}
+
+const testERC20Contract = "0x919C1c267BC06a7039e03fcc2eF738525769109c"
+
+var testERC20Currency = &types.Currency{
+	Symbol:   "USDt",
+	Decimals: 6,
+	Metadata: map[string]interface{}{"contract_address": testERC20Contract},
+}
+
+// testRegistry is the currency registry of the default currencies used by the operation tests
+var testRegistry = kava.NewCurrencyRegistry(kava.Currencies)
+
+// newTestERC20Registry returns a registry of the default currencies and the test erc20 contract
+func newTestERC20Registry(t *testing.T) *kava.CurrencyRegistry {
+	registry := kava.NewCurrencyRegistry(kava.Currencies)
+	require.NoError(t, registry.RegisterERC20(testERC20Contract, &types.Currency{Symbol: "USDt", Decimals: 6}))
+
+	return registry
+}
+
+func testDelegatorEVMAddress(t *testing.T) string {
+	return common.BytesToAddress(mustAccAddressFromBech32(t, testDelegatorAddress)).Hex()
+}
+
+func conversionOps(t *testing.T) []*types.Operation {
+	return []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Type:                kava.ConvertCoinToERC20OpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: &types.Currency{Symbol: "erc20/multichain/usdc", Decimals: 0},
+			},
+			Metadata: map[string]interface{}{
+				"evm_address": testDelegatorEVMAddress(t),
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 1},
+			Type:                kava.ConvertERC20ToCoinOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Amount:              &types.Amount{Value: "2000000", Currency: testERC20Currency},
+			Metadata: map[string]interface{}{
+				"evm_address": testDelegatorEVMAddress(t),
+			},
+		},
+	}
+}
+
+func TestParseOperationMsgs_Conversions(t *testing.T) {
+	registry := newTestERC20Registry(t)
+
+	msgs, rerr := parseOperationMsgs(registry, conversionOps(t), nil)
+	require.Nil(t, rerr)
+	require.Equal(t, 2, len(msgs))
+
+	delegator := mustAccAddressFromBech32(t, testDelegatorAddress)
+	evmAddress := evmutiltypes.NewInternalEVMAddress(common.BytesToAddress(delegator))
+
+	coinToERC20 := evmutiltypes.NewMsgConvertCoinToERC20(
+		testDelegatorAddress,
+		evmAddress.Hex(),
+		sdk.NewCoin("erc20/multichain/usdc", sdkmath.NewInt(1000000)),
+	)
+	assert.Equal(t, &coinToERC20, msgs[0])
+
+	erc20ToCoin := evmutiltypes.NewMsgConvertERC20ToCoin(
+		evmAddress,
+		delegator,
+		evmutiltypes.NewInternalEVMAddress(common.HexToAddress(testERC20Contract)),
+		sdkmath.NewInt(2000000),
+	)
+	assert.Equal(t, &erc20ToCoin, msgs[1])
+	assert.Equal(t, []sdk.AccAddress{delegator}, msgs[1].GetSigners())
+
+	ops := []*types.Operation{}
+	for _, msg := range msgs {
+		ops = append(ops, msgToOperations(registry, msg, int64(len(ops)))...)
+	}
+	assert.Equal(t, conversionOps(t), ops)
+}
+
+func TestParseOperationMsgs_InvalidConversions(t *testing.T) {
+	registry := newTestERC20Registry(t)
+
+	testCases := []struct {
+		name        string
+		modify      func(ops []*types.Operation) []*types.Operation
+		expectedErr *types.Error
+	}{
+		{
+			name: "positive coin to erc20 amount",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Amount.Value = "1000000"
+				return ops[:1]
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "negative erc20 to coin amount",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Amount.Value = "-2000000"
+				return ops[1:]
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "erc20 to coin with a denom currency",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Amount.Currency = kava.Currencies["usdx"]
+				return ops[1:]
+			},
+			expectedErr: ErrUnsupportedCurrency,
+		},
+		{
+			name: "coin to erc20 with an erc20 currency",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Amount.Currency = testERC20Currency
+				return ops[:1]
+			},
+			expectedErr: ErrUnsupportedCurrency,
+		},
+		{
+			name: "missing evm address",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata = nil
+				return ops[:1]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid evm address",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Metadata["evm_address"] = testDelegatorAddress
+				return ops[1:]
+			},
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name: "conversion combined with transfer",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Type = kava.TransferOpType
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(registry, tc.modify(conversionOps(t)), nil)
+			assert.Nil(t, msgs)
+			require.NotNil(t, rerr)
+			assert.Equal(t, tc.expectedErr.Code, rerr.Code)
+			assert.Equal(t, tc.expectedErr.Message, rerr.Message)
+		})
+	}
+}
+
+func TestConstructionPayloadsAndParse_Conversions(t *testing.T) {
+	registry := newTestERC20Registry(t)
+
+	signerPubKey, err := base64.StdEncoding.DecodeString("AsAbWjsqD1ntOiVZCNRdAm1nrSP8rwZoNNin85jPaeaY")
+	require.NoError(t, err)
+
+	encodedSigners, err := json.Marshal([]signerInfo{{AccountNumber: 10, AccountSequence: 11}})
+	require.NoError(t, err)
+
+	servicer, _ := setupConstructionAPIServicer()
+	servicer.registry = registry
+	ctx := context.Background()
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: conversionOps(t),
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(preprocessResponse.RequiredPublicKeys))
+	assert.Equal(t, testDelegatorAddress, preprocessResponse.RequiredPublicKeys[0].Address)
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        conversionOps(t),
+		Metadata: map[string]interface{}{
+			"signers":    string(encodedSigners),
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: signerPubKey}},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		Signed:      false,
+		Transaction: payloadsResponse.UnsignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, conversionOps(t), parseResponse.Operations)
+}
+
+func ibcTransferOps() []*types.Operation {
+	return []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Type:                kava.IBCTransferOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Amount:              &types.Amount{Value: "-1000000", Currency: kava.Currencies["ukava"]},
+			Metadata: map[string]interface{}{
+				"source_port":       "transfer",
+				"source_channel":    "channel-0",
+				"receiver":          testIBCReceiver,
+				"timeout_height":    "4-12000000",
+				"timeout_timestamp": "1700000000000000000",
+			},
+		},
+	}
+}
+
+func TestParseOperationMsgs_IBCTransfer(t *testing.T) {
+	msgs, rerr := parseOperationMsgs(testRegistry, ibcTransferOps(), nil)
+	require.Nil(t, rerr)
+	assert.Equal(t, []sdk.Msg{
+		ibctransfertypes.NewMsgTransfer(
+			"transfer",
+			"channel-0",
+			sdk.NewInt64Coin("ukava", 1000000),
+			testDelegatorAddress,
+			testIBCReceiver,
+			clienttypes.NewHeight(4, 12000000),
+			1700000000000000000,
+			"",
+		),
+	}, msgs)
+	assert.Equal(t, ibcTransferOps(), msgToOperations(testRegistry, msgs[0], 0))
+
+	ops := ibcTransferOps()
+	delete(ops[0].Metadata, "source_port")
+	delete(ops[0].Metadata, "timeout_height")
+	delete(ops[0].Metadata, "timeout_timestamp")
+
+	msgs, rerr = parseOperationMsgs(testRegistry, ops, nil)
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(msgs))
+	transfer := msgs[0].(*ibctransfertypes.MsgTransfer)
+	assert.Equal(t, ibctransfertypes.PortID, transfer.SourcePort)
+	assert.True(t, transfer.TimeoutHeight.IsZero())
+	assert.Equal(t, uint64(0), transfer.TimeoutTimestamp)
+
+	msgs, rerr = parseOperationMsgs(testRegistry, ops, &resolvedValues{ibcTimeoutTimestamp: 1800000000000000000})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(msgs))
+	assert.Equal(t, uint64(1800000000000000000), msgs[0].(*ibctransfertypes.MsgTransfer).TimeoutTimestamp)
+}
+
+func TestParseOperationMsgs_InvalidIBCTransfers(t *testing.T) {
+	testCases := []struct {
+		name        string
+		modify      func(ops []*types.Operation) []*types.Operation
+		resolved    *resolvedValues
+		expectedErr *types.Error
+	}{
+		{
+			name: "positive amount",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Amount.Value = "1000000"
+				return ops
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "missing source channel",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				delete(ops[0].Metadata, "source_channel")
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid source channel",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata["source_channel"] = "channel/0"
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "missing receiver",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				delete(ops[0].Metadata, "receiver")
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid timeout height",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata["timeout_height"] = "12000000"
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid timeout timestamp",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata["timeout_timestamp"] = float64(1700000000000000000)
+				return ops
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "timeout not resolved",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				delete(ops[0].Metadata, "timeout_height")
+				delete(ops[0].Metadata, "timeout_timestamp")
+				return ops
+			},
+			resolved:    &resolvedValues{},
+			expectedErr: ErrInvalidMetadata,
+		},
+		{
+			name: "ibc transfer combined with transfer",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				return append(ops, transferOp(1, testDelegatorAddress, "-1", "ukava"))
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.modify(ibcTransferOps()), tc.resolved)
+			assert.Nil(t, msgs)
+			require.NotNil(t, rerr)
+			assert.Equal(t, tc.expectedErr.Code, rerr.Code)
+			assert.Equal(t, tc.expectedErr.Message, rerr.Message)
+		})
+	}
+}
+
+func TestConstructionMetadata_IBCTransferTimeout(t *testing.T) {
+	servicer, mockClient := setupConstructionAPIServicer()
+	servicer.config.Mode = configuration.Online
+	servicer.config.IBCTransferTimeout = 10 * time.Minute
+	ctx := context.Background()
+
+	delegator := mustAccAddressFromBech32(t, testDelegatorAddress)
+	ops := ibcTransferOps()
+	delete(ops[0].Metadata, "timeout_height")
+	delete(ops[0].Metadata, "timeout_timestamp")
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(preprocessResponse.RequiredPublicKeys))
+
+	encodedOptions, err := json.Marshal(preprocessResponse.Options)
+	require.NoError(t, err)
+	var options map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedOptions, &options))
+
+	latestBlockTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedTimeout := uint64(latestBlockTime.Add(10 * time.Minute).UnixNano())
+
+	mockClient.On("SyncInfo", ctx).Return(&ctypes.SyncInfo{LatestBlockTime: latestBlockTime}, nil).Once()
+	mockClient.On("Account", ctx, delegator).Return(&authtypes.BaseAccount{AccountNumber: 10, Sequence: 11}, nil).Once()
+	mockClient.On("EstimateGas", ctx, mock.MatchedBy(func(tx sdk.Tx) bool {
+		msgs := tx.GetMsgs()
+		return len(msgs) == 1 && msgs[0].(*ibctransfertypes.MsgTransfer).TimeoutTimestamp == expectedTimeout
+	}), float64(0.5)).Return(uint64(120000), nil).Once()
+
+	response, rerr := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{Options: options})
+	require.Nil(t, rerr)
+	assert.Equal(t, strconv.FormatUint(expectedTimeout, 10), response.Metadata["ibc_timeout_timestamp"])
+
+	signerPubKey, err := base64.StdEncoding.DecodeString("AsAbWjsqD1ntOiVZCNRdAm1nrSP8rwZoNNin85jPaeaY")
+	require.NoError(t, err)
+
+	encodedMetadata, err := json.Marshal(response.Metadata)
+	require.NoError(t, err)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedMetadata, &metadata))
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        ops,
+		Metadata:          metadata,
+		PublicKeys:        []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: signerPubKey}},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		Signed:      false,
+		Transaction: payloadsResponse.UnsignedTransaction,
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(parseResponse.Operations))
+	assert.Equal(t, kava.IBCTransferOpType, parseResponse.Operations[0].Type)
+	assert.Equal(t, "-1000000", parseResponse.Operations[0].Amount.Value)
+	assert.Equal(t, strconv.FormatUint(expectedTimeout, 10), parseResponse.Operations[0].Metadata["timeout_timestamp"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestParseOperationMsgs_WithdrawRewards(t *testing.T) {
+	delegator := mustAccAddressFromBech32(t, testDelegatorAddress)
+	validator, err := sdk.ValAddressFromBech32(testValidatorAddress)
+	require.NoError(t, err)
+
+	withdrawOne := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: 0},
+		Type:                kava.WithdrawRewardsOpType,
+		Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+		Metadata: map[string]interface{}{
+			"validator_address": testValidatorAddress,
+		},
+	}
+
+	t.Run("single validator", func(t *testing.T) {
+		msgs, rerr := parseOperationMsgs(testRegistry, []*types.Operation{withdrawOne}, nil)
+		require.Nil(t, rerr)
+		require.Equal(t, []sdk.Msg{distrtypes.NewMsgWithdrawDelegatorReward(delegator, validator)}, msgs)
+
+		assert.Equal(t, []*types.Operation{withdrawOne}, msgToOperations(testRegistry, msgs[0], 0))
+	})
+
+	t.Run("validator is required", func(t *testing.T) {
+		invalidOp := *withdrawOne
+		invalidOp.Metadata = nil
+
+		msgs, rerr := parseOperationMsgs(testRegistry, []*types.Operation{&invalidOp}, nil)
+		assert.Nil(t, msgs)
+		require.NotNil(t, rerr)
+		assert.Equal(t, ErrUnclearIntent.Code, rerr.Code)
+		assert.Equal(t,
+			"no validator_address provided in operation metadata, each operation requires the address of the validator it acts on",
+			rerr.Details["context"],
+		)
+	})
+
+	t.Run("amount is not allowed", func(t *testing.T) {
+		invalidOp := *withdrawOne
+		invalidOp.Amount = &types.Amount{Value: "1000", Currency: kava.Currencies["ukava"]}
+
+		msgs, rerr := parseOperationMsgs(testRegistry, []*types.Operation{&invalidOp}, nil)
+		assert.Nil(t, msgs)
+		require.NotNil(t, rerr)
+		assert.Equal(t, ErrUnclearIntent.Code, rerr.Code)
+	})
+}
+
+func TestConstructionMetadataPayloadsAndParse_WithdrawRewards(t *testing.T) {
+	servicer, mockClient := setupConstructionAPIServicer()
+	servicer.config.Mode = configuration.Online
+	ctx := context.Background()
+
+	delegator := mustAccAddressFromBech32(t, testDelegatorAddress)
+	withdrawOp := func(index int64, validator string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{Index: index},
+			Type:                kava.WithdrawRewardsOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Metadata: map[string]interface{}{
+				"validator_address": validator,
+			},
+		}
+	}
+	delegateOp := stakingOps()[0]
+	delegateOp.OperationIdentifier = &types.OperationIdentifier{Index: 1}
+	ops := []*types.Operation{
+		withdrawOp(0, testValidatorAddress),
+		delegateOp,
+		withdrawOp(2, testValidatorDstAddress),
+	}
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(preprocessResponse.RequiredPublicKeys))
+	assert.Equal(t, testDelegatorAddress, preprocessResponse.RequiredPublicKeys[0].Address)
+
+	// options are passed through json by the rosetta client
+	encodedOptions, err := json.Marshal(preprocessResponse.Options)
+	require.NoError(t, err)
+	var options map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedOptions, &options))
+
+	mockClient.On("Account", ctx, delegator).Return(&authtypes.BaseAccount{AccountNumber: 10, Sequence: 11}, nil).Once()
+	mockClient.On("EstimateGas", ctx, mock.Anything, float64(0.5)).Return(uint64(200000), nil).Once()
+
+	response, rerr := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{Options: options})
+	require.Nil(t, rerr)
+
+	signerPubKey, err := base64.StdEncoding.DecodeString("AsAbWjsqD1ntOiVZCNRdAm1nrSP8rwZoNNin85jPaeaY")
+	require.NoError(t, err)
+
+	encodedMetadata, err := json.Marshal(response.Metadata)
+	require.NoError(t, err)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedMetadata, &metadata))
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        ops,
+		Metadata:          metadata,
+		PublicKeys:        []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: signerPubKey}},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		Signed:      false,
+		Transaction: payloadsResponse.UnsignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, ops, parseResponse.Operations)
+
+	mockClient.AssertExpectations(t)
+}
+
+func mustAccAddressFromBech32(t *testing.T, addr string) sdk.AccAddress {
+	accAddr, err := sdk.AccAddressFromBech32(addr)
+	require.NoError(t, err)
+	return accAddr
+}
+
+func transferOp(index int64, address string, value string, denom string, related ...int64) *types.Operation {
+	op := &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{Index: index},
+		Type:                kava.TransferOpType,
+		Account:             &types.AccountIdentifier{Address: address},
+		Amount:              &types.Amount{Value: value, Currency: kava.Currencies[denom]},
+	}
+
+	for _, relatedIndex := range related {
+		op.RelatedOperations = append(op.RelatedOperations, &types.OperationIdentifier{Index: relatedIndex})
+	}
+
+	return op
+}
+
+func TestParseOperationMsgs_Transfers(t *testing.T) {
+	sender := testDelegatorAddress
+	otherSender := "kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea"
+	recipient1 := "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w"
+	recipient2 := "kava16g8lzm86f5wwf3x3t67qrpd46sjdpxpfazskwg"
+
+	testCases := []struct {
+		name         string
+		ops          []*types.Operation
+		expectedMsgs []sdk.Msg
+	}{
+		{
+			name: "sender and recipient pairs",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, recipient1, "100", "ukava", 0),
+				transferOp(2, otherSender, "-200", "hard"),
+				transferOp(3, recipient2, "200", "hard", 2),
+			},
+			expectedMsgs: []sdk.Msg{
+				banktypes.NewMsgSend(
+					mustAccAddressFromBech32(t, sender),
+					mustAccAddressFromBech32(t, recipient1),
+					sdk.NewCoins(sdk.NewInt64Coin("ukava", 100)),
+				),
+				banktypes.NewMsgSend(
+					mustAccAddressFromBech32(t, otherSender),
+					mustAccAddressFromBech32(t, recipient2),
+					sdk.NewCoins(sdk.NewInt64Coin("hard", 200)),
+				),
+			},
+		},
+		{
+			name: "single sender with many recipients and currencies",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-300", "hard"),
+				transferOp(1, sender, "-150", "ukava"),
+				transferOp(2, recipient1, "100", "ukava", 1),
+				transferOp(3, recipient1, "300", "hard", 0),
+				transferOp(4, recipient2, "50", "ukava", 1),
+			},
+			expectedMsgs: []sdk.Msg{
+				banktypes.NewMsgMultiSend(
+					[]banktypes.Input{
+						banktypes.NewInput(mustAccAddressFromBech32(t, sender), sdk.NewCoins(
+							sdk.NewInt64Coin("hard", 300),
+							sdk.NewInt64Coin("ukava", 150),
+						)),
+					},
+					[]banktypes.Output{
+						banktypes.NewOutput(mustAccAddressFromBech32(t, recipient1), sdk.NewCoins(sdk.NewInt64Coin("ukava", 100))),
+						banktypes.NewOutput(mustAccAddressFromBech32(t, recipient1), sdk.NewCoins(sdk.NewInt64Coin("hard", 300))),
+						banktypes.NewOutput(mustAccAddressFromBech32(t, recipient2), sdk.NewCoins(sdk.NewInt64Coin("ukava", 50))),
+					},
+				),
+			},
+		},
+		{
+			name: "senders followed by their recipients with multiple denoms",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-300", "hard"),
+				transferOp(1, sender, "-150", "ukava"),
+				transferOp(2, recipient1, "300", "hard", 0),
+				transferOp(3, recipient2, "150", "ukava", 1),
+				transferOp(4, otherSender, "-200", "ukava"),
+				transferOp(5, recipient1, "200", "ukava", 4),
+				transferOp(6, sender, "-50", "hard"),
+				transferOp(7, recipient2, "50", "hard", 6),
+			},
+			expectedMsgs: []sdk.Msg{
+				banktypes.NewMsgMultiSend(
+					[]banktypes.Input{
+						banktypes.NewInput(mustAccAddressFromBech32(t, sender), sdk.NewCoins(
+							sdk.NewInt64Coin("hard", 300),
+							sdk.NewInt64Coin("ukava", 150),
+						)),
+					},
+					[]banktypes.Output{
+						banktypes.NewOutput(mustAccAddressFromBech32(t, recipient1), sdk.NewCoins(sdk.NewInt64Coin("hard", 300))),
+						banktypes.NewOutput(mustAccAddressFromBech32(t, recipient2), sdk.NewCoins(sdk.NewInt64Coin("ukava", 150))),
+					},
+				),
+				banktypes.NewMsgSend(
+					mustAccAddressFromBech32(t, otherSender),
+					mustAccAddressFromBech32(t, recipient1),
+					sdk.NewCoins(sdk.NewInt64Coin("ukava", 200)),
+				),
+				banktypes.NewMsgSend(
+					mustAccAddressFromBech32(t, sender),
+					mustAccAddressFromBech32(t, recipient2),
+					sdk.NewCoins(sdk.NewInt64Coin("hard", 50)),
+				),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.ops, nil)
+			require.Nil(t, rerr)
+			assert.Equal(t, tc.expectedMsgs, msgs)
+
+			parsedOps := []*types.Operation{}
+			for _, msg := range msgs {
+				parsedOps = append(parsedOps, msgToOperations(testRegistry, msg, int64(len(parsedOps)))...)
+			}
+			assert.Equal(t, tc.ops, parsedOps)
+		})
+	}
+}
+
+func TestParseOperationMsgs_UnorderedTransfers(t *testing.T) {
+	sender := testDelegatorAddress
+	otherSender := "kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea"
+	recipient := "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w"
+	otherRecipient := "kava16g8lzm86f5wwf3x3t67qrpd46sjdpxpfazskwg"
+
+	testCases := []struct {
+		name        string
+		ops         []*types.Operation
+		expectedLen int
+	}{
+		{
+			name: "many senders paying one recipient",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, otherSender, "-100", "ukava"),
+				transferOp(2, recipient, "200", "ukava"),
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "sender last",
+			ops: []*types.Operation{
+				transferOp(0, recipient, "100", "ukava"),
+				transferOp(1, otherSender, "200", "ukava"),
+				transferOp(2, sender, "-300", "ukava"),
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "recipient before sender",
+			ops: []*types.Operation{
+				transferOp(0, recipient, "100", "ukava"),
+				transferOp(1, sender, "-100", "ukava"),
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "sender denoms not in denom order",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, sender, "-100", "hard"),
+				transferOp(2, recipient, "100", "ukava"),
+				transferOp(3, recipient, "100", "hard"),
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "recipient paid by a later sender",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, recipient, "100", "ukava"),
+				transferOp(2, recipient, "200", "hard"),
+				transferOp(3, otherSender, "-200", "hard"),
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "duplicate sender currency",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, sender, "-100", "ukava"),
+				transferOp(2, recipient, "200", "ukava"),
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "credits split unevenly across senders",
+			ops: []*types.Operation{
+				transferOp(0, recipient, "150", "ukava"),
+				transferOp(1, otherRecipient, "150", "ukava"),
+				transferOp(2, sender, "-200", "ukava"),
+				transferOp(3, otherSender, "-100", "ukava"),
+				transferOp(4, otherSender, "-50", "hard"),
+				transferOp(5, sender, "50", "hard"),
+			},
+			expectedLen: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.ops, nil)
+			require.Nil(t, rerr)
+			assert.Len(t, msgs, tc.expectedLen)
+
+			parsedOps := []*types.Operation{}
+			for _, msg := range msgs {
+				require.NoError(t, msg.ValidateBasic())
+				parsedOps = append(parsedOps, msgToOperations(testRegistry, msg, int64(len(parsedOps)))...)
+			}
+			assert.Equal(t, netTransfers(t, tc.ops), netTransfers(t, parsedOps))
+		})
+	}
+}
+
+// netTransfers returns the net amount of each account and currency symbol in the transfer operations
+func netTransfers(t *testing.T, ops []*types.Operation) map[string]string {
+	net := make(map[string]*big.Int)
+	for _, op := range ops {
+		value, err := types.AmountValue(op.Amount)
+		require.NoError(t, err)
+
+		key := op.Account.Address + "/" + op.Amount.Currency.Symbol
+		if _, ok := net[key]; !ok {
+			net[key] = new(big.Int)
+		}
+		net[key].Add(net[key], value)
+	}
+
+	values := make(map[string]string)
+	for key, value := range net {
+		values[key] = value.String()
+	}
+
+	return values
+}
+
+func TestParseOperationMsgs_InvalidTransfers(t *testing.T) {
+	sender := testDelegatorAddress
+	recipient := "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w"
+
+	testCases := []struct {
+		name        string
+		ops         []*types.Operation
+		expectedErr *types.Error
+	}{
+		{
+			name: "does not net to zero",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, recipient, "99", "ukava"),
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "nets to zero across currencies only",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				transferOp(1, recipient, "100", "hard"),
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "zero amount",
+			ops: []*types.Operation{
+				transferOp(0, sender, "0", "ukava"),
+				transferOp(1, recipient, "0", "ukava"),
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "unsupported currency",
+			ops: []*types.Operation{
+				transferOp(0, sender, "-100", "ukava"),
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                kava.TransferOpType,
+					Account:             &types.AccountIdentifier{Address: recipient},
+					Amount:              &types.Amount{Value: "100", Currency: &types.Currency{Symbol: "BNB", Decimals: 8}},
+				},
+			},
+			expectedErr: ErrUnsupportedCurrency,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.ops, nil)
+			assert.Nil(t, msgs)
+			require.NotNil(t, rerr)
+			assert.Equal(t, tc.expectedErr.Code, rerr.Code)
+		})
+	}
+}
+
+func TestConstructionPayloadsAndParse_MultiSend(t *testing.T) {
+	signerPubKey, err := base64.StdEncoding.DecodeString("AsAbWjsqD1ntOiVZCNRdAm1nrSP8rwZoNNin85jPaeaY")
+	require.NoError(t, err)
+
+	encodedSigners, err := json.Marshal([]signerInfo{{AccountNumber: 10, AccountSequence: 11}})
+	require.NoError(t, err)
+
+	ops := []*types.Operation{
+		transferOp(0, testDelegatorAddress, "-300", "ukava"),
+		transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "100", "ukava", 0),
+		transferOp(2, "kava16g8lzm86f5wwf3x3t67qrpd46sjdpxpfazskwg", "200", "ukava", 0),
+	}
+
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(preprocessResponse.RequiredPublicKeys))
+	assert.Equal(t, testDelegatorAddress, preprocessResponse.RequiredPublicKeys[0].Address)
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        ops,
+		Metadata: map[string]interface{}{
+			"signers":    string(encodedSigners),
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: signerPubKey}},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		Signed:      false,
+		Transaction: payloadsResponse.UnsignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, ops, parseResponse.Operations)
+}
+
+func authzOps() []*types.Operation {
+	return []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Type:                kava.AuthzGrantOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Metadata: map[string]interface{}{
+				"grantee":     testGranteeAddress,
+				"spend_limit": "1000000000ukava",
+				"expiration":  "2030-01-01T00:00:00Z",
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 1},
+			Type:                kava.AuthzGrantOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Metadata: map[string]interface{}{
+				"grantee":      testGranteeAddress,
+				"msg_type_url": "/cosmos.staking.v1beta1.MsgDelegate",
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 2},
+			Type:                kava.AuthzRevokeOpType,
+			Account:             &types.AccountIdentifier{Address: testDelegatorAddress},
+			Metadata: map[string]interface{}{
+				"grantee":      testGranteeAddress,
+				"msg_type_url": "/cosmos.bank.v1beta1.MsgSend",
+			},
+		},
+	}
+}
+
+func TestParseOperationMsgs_Authz(t *testing.T) {
+	granter := mustAccAddressFromBech32(t, testDelegatorAddress)
+	grantee := mustAccAddressFromBech32(t, testGranteeAddress)
+	expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	msgs, rerr := parseOperationMsgs(testRegistry, authzOps(), nil)
+	require.Nil(t, rerr)
+	require.Equal(t, 3, len(msgs))
+
+	sendGrant, err := authz.NewMsgGrant(
+		granter,
+		grantee,
+		banktypes.NewSendAuthorization(sdk.NewCoins(sdk.NewInt64Coin("ukava", 1000000000)), nil),
+		&expiration,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, sendGrant, msgs[0])
+
+	delegateGrant, err := authz.NewMsgGrant(granter, grantee, authz.NewGenericAuthorization("/cosmos.staking.v1beta1.MsgDelegate"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, delegateGrant, msgs[1])
+
+	revoke := authz.NewMsgRevoke(granter, grantee, "/cosmos.bank.v1beta1.MsgSend")
+	assert.Equal(t, &revoke, msgs[2])
+
+	for _, msg := range msgs {
+		assert.Equal(t, []sdk.AccAddress{granter}, msg.GetSigners())
+	}
+
+	ops := []*types.Operation{}
+	for _, msg := range msgs {
+		ops = append(ops, msgToOperations(testRegistry, msg, int64(len(ops)))...)
+	}
+	assert.Equal(t, authzOps(), ops)
+}
+
+func TestParseOperationMsgs_InvalidAuthz(t *testing.T) {
+	testCases := []struct {
+		name        string
+		modify      func(ops []*types.Operation) []*types.Operation
+		expectedErr *types.Error
+	}{
+		{
+			name: "missing grantee",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				delete(ops[0].Metadata, "grantee")
+				return ops[:1]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid grantee",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[2].Metadata["grantee"] = testValidatorAddress
+				return ops[2:]
+			},
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name: "grantee is granter",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Metadata["grantee"] = testDelegatorAddress
+				return ops[1:2]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "grant with an amount",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Amount = &types.Amount{Value: "-1", Currency: kava.Currencies["ukava"]}
+				return ops[:1]
+			},
+			expectedErr: ErrInvalidCurrencyAmount,
+		},
+		{
+			name: "invalid spend limit",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata["spend_limit"] = "-1ukava"
+				return ops[:1]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "spend limit for another message type",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Metadata["spend_limit"] = "1000000ukava"
+				return ops[1:2]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "invalid expiration",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata["expiration"] = "2030-01-01"
+				return ops[:1]
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+		{
+			name: "authz combined with transfer",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				return append(ops, transferOp(3, testDelegatorAddress, "-1", "ukava"))
+			},
+			expectedErr: ErrUnclearIntent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.modify(authzOps()), nil)
+			assert.Nil(t, msgs)
+			require.NotNil(t, rerr)
+			assert.Equal(t, tc.expectedErr.Code, rerr.Code)
+			assert.Equal(t, tc.expectedErr.Message, rerr.Message)
+		})
+	}
+}
+
+func execOps() []*types.Operation {
+	recipient := "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w"
+
+	sender := transferOp(0, testDelegatorAddress, "-5000000", "ukava")
+	sender.Metadata = map[string]interface{}{"grantee": testGranteeAddress}
+
+	return []*types.Operation{sender, transferOp(1, recipient, "5000000", "ukava", 0)}
+}
+
+func TestParseOperationMsgs_AuthzExec(t *testing.T) {
+	msgs, rerr := parseOperationMsgs(testRegistry, execOps(), nil)
+	require.Nil(t, rerr)
+
+	send := banktypes.NewMsgSend(
+		mustAccAddressFromBech32(t, testDelegatorAddress),
+		mustAccAddressFromBech32(t, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w"),
+		sdk.NewCoins(sdk.NewInt64Coin("ukava", 5000000)),
+	)
+	exec := authz.NewMsgExec(mustAccAddressFromBech32(t, testGranteeAddress), []sdk.Msg{send})
+	assert.Equal(t, []sdk.Msg{&exec}, msgs)
+	assert.Equal(t, []sdk.AccAddress{mustAccAddressFromBech32(t, testGranteeAddress)}, msgs[0].GetSigners())
+
+	assert.Equal(t, execOps(), msgToOperations(testRegistry, msgs[0], 0))
+
+	testCases := []struct {
+		name   string
+		modify func(ops []*types.Operation) []*types.Operation
+	}{
+		{
+			name: "grantee on credit operation",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[1].Metadata = map[string]interface{}{"grantee": testGranteeAddress}
+				return ops
+			},
+		},
+		{
+			name: "grantee missing on a debit operation",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				return append(ops,
+					transferOp(2, "kava1esagqd83rhqdtpy5sxhklaxgn58k2m3s3mnpea", "-100", "ukava"),
+					transferOp(3, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "100", "ukava", 2),
+				)
+			},
+		},
+		{
+			name: "grantee not a string",
+			modify: func(ops []*types.Operation) []*types.Operation {
+				ops[0].Metadata["grantee"] = true
+				return ops
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgs, rerr := parseOperationMsgs(testRegistry, tc.modify(execOps()), nil)
+			assert.Nil(t, msgs)
+			require.NotNil(t, rerr)
+			assert.Equal(t, ErrUnclearIntent.Code, rerr.Code)
+		})
+	}
+}
+
+func TestConstructionPreprocessPayloadsAndParse_AuthzExec(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	// the grantee signs the exec with its own key
+	granteeKey := secp256k1.GenPrivKey().PubKey()
+	granteeAddress := sdk.AccAddress(granteeKey.Address()).String()
+	ops := execOps()
+	ops[0].Metadata["grantee"] = granteeAddress
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, []*types.AccountIdentifier{{Address: granteeAddress}}, preprocessResponse.RequiredPublicKeys)
+
+	options, err := validateAndParseOptions(servicer.encodingConfig.Marshaler, preprocessResponse.Options)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(options.txBody.Messages))
+	exec, ok := options.txBody.Messages[0].GetCachedValue().(*authz.MsgExec)
+	require.True(t, ok)
+	execMsgs, err := exec.GetMessages()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(execMsgs))
+	assert.Equal(t, []sdk.AccAddress{mustAccAddressFromBech32(t, testDelegatorAddress)}, execMsgs[0].GetSigners())
+
+	encodedSigners, err := json.Marshal([]signerInfo{{AccountNumber: 10, AccountSequence: 11}})
+	require.NoError(t, err)
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        ops,
+		Metadata: map[string]interface{}{
+			"signers":    string(encodedSigners),
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: granteeKey.Bytes()}},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+
+	parseResponse, rerr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		Signed:      false,
+		Transaction: payloadsResponse.UnsignedTransaction,
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, ops, parseResponse.Operations)
+}
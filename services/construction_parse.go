@@ -19,14 +19,10 @@ package services
 
 import (
 	"context"
-	"encoding/hex"
-
-	"github.com/kava-labs/rosetta-kava/kava"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
-	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 )
 
 // ConstructionParse implements the /construction/parse endpoint.
@@ -35,7 +31,7 @@ func (s *ConstructionAPIService) ConstructionParse(
 	ctx context.Context,
 	request *types.ConstructionParseRequest,
 ) (*types.ConstructionParseResponse, *types.Error) {
-	txBytes, err := hex.DecodeString(request.Transaction)
+	txBytes, err := decodeTxBytes(request.Transaction)
 	if err != nil {
 		return nil, wrapErr(ErrInvalidTx, err)
 	}
@@ -49,34 +45,9 @@ func (s *ConstructionAPIService) ConstructionParse(
 	ops := []*types.Operation{}
 
 	for _, msg := range tx.GetMsgs() {
-		msgSend, ok := msg.(*banktypes.MsgSend)
-		if !ok {
-			continue
-		}
-
-		for _, coin := range msgSend.Amount {
-			currency, ok := kava.Currencies[coin.Denom]
-			if !ok {
-				continue
-			}
-
-			ops = append(ops, &types.Operation{
-				OperationIdentifier: &types.OperationIdentifier{Index: index},
-				Type:                kava.TransferOpType,
-				Account:             &types.AccountIdentifier{Address: msgSend.FromAddress},
-				Amount:              &types.Amount{Value: "-" + coin.Amount.String(), Currency: currency},
-			})
-
-			ops = append(ops, &types.Operation{
-				OperationIdentifier: &types.OperationIdentifier{Index: index + 1},
-				RelatedOperations:   []*types.OperationIdentifier{&types.OperationIdentifier{Index: index}},
-				Type:                kava.TransferOpType,
-				Account:             &types.AccountIdentifier{Address: msgSend.ToAddress},
-				Amount:              &types.Amount{Value: coin.Amount.String(), Currency: currency},
-			})
-
-			index += 2
-		}
+		msgOps := msgToOperations(s.registry, msg, index)
+		ops = append(ops, msgOps...)
+		index += int64(len(msgOps))
 	}
 
 	signers := []*types.AccountIdentifier{}
@@ -18,19 +18,27 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/cometbft/cometbft/crypto"
-	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
 )
 
 var requiredMetadata = []string{
@@ -45,6 +53,12 @@ type metadata struct {
 	gasWanted uint64
 	gasPrice  float64
 	memo      string
+
+	feePayer   sdk.AccAddress
+	feeGranter sdk.AccAddress
+	multisig   *kmultisig.LegacyAminoPubKey
+
+	ibcTimeoutTimestamp uint64
 }
 
 // ConstructionPayloads implements the /construction/payloads endpoint.
@@ -59,7 +73,9 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 
 	txBuilder := s.encodingConfig.TxConfig.NewTxBuilder()
 
-	msgs, rerr := parseOperationMsgs(request.Operations)
+	msgs, rerr := parseOperationMsgs(s.registry, request.Operations, &resolvedValues{
+		ibcTimeoutTimestamp: metadata.ibcTimeoutTimestamp,
+	})
 	if rerr != nil {
 		return nil, rerr
 	}
@@ -72,23 +88,71 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewCoin("ukava", feeAmount)))
 	txBuilder.SetGasLimit(metadata.gasWanted)
 	txBuilder.SetMemo(metadata.memo)
+	txBuilder.SetFeePayer(metadata.feePayer)
+	txBuilder.SetFeeGranter(metadata.feeGranter)
 
 	tx := txBuilder.GetTx()
 
+	// multisig members can not sign over the signer infos of the other members, so every signer of
+	// a transaction with a multisig signs amino json
+	signMode := signing.SignMode_SIGN_MODE_DIRECT
+	if metadata.multisig != nil {
+		signMode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	}
+
+	signerAddrs := tx.GetSigners()
+	if len(signerAddrs) != len(metadata.signers) {
+		return nil, wrapErr(ErrInvalidMetadata, fmt.Errorf("expected %d signers, got %d", len(signerAddrs), len(metadata.signers)))
+	}
+
 	var sigsV2 []signing.SignatureV2
+	keyIndex := 0
 	for i, signer := range metadata.signers {
-		if i >= len(request.PublicKeys) {
+		if signer.Multisig {
+			if metadata.multisig == nil {
+				return nil, wrapErr(ErrInvalidMetadata, errors.New("no multisig provided"))
+			}
+
+			multisigAddr := sdk.AccAddress(metadata.multisig.Address())
+			if !multisigAddr.Equals(signerAddrs[i]) {
+				return nil, wrapErr(ErrInvalidMetadata, fmt.Errorf("multisig %s does not match signer %s", multisigAddr, signerAddrs[i]))
+			}
+
+			members := metadata.multisig.GetPubKeys()
+			if keyIndex+len(members) > len(request.PublicKeys) {
+				return nil, ErrMissingPublicKey
+			}
+
+			for j, member := range members {
+				if !bytes.Equal(request.PublicKeys[keyIndex+j].Bytes, member.Bytes()) {
+					return nil, wrapErr(ErrInvalidPublicKey, fmt.Errorf("public key %d does not match multisig member %d", keyIndex+j, j))
+				}
+			}
+			keyIndex += len(members)
+
+			sigsV2 = append(sigsV2, signing.SignatureV2{
+				PubKey:   metadata.multisig,
+				Data:     multisig.NewMultisig(len(members)),
+				Sequence: signer.AccountSequence,
+			})
+			continue
+		}
+
+		if keyIndex >= len(request.PublicKeys) {
 			return nil, ErrMissingPublicKey
 		}
-		// TODO: validate curve type
-		pubKey := secp256k1.PubKey{Key: request.PublicKeys[i].Bytes}
+		pubKey, rerr := getSignerPublicKey(request.PublicKeys[keyIndex], signerAddrs[i])
+		if rerr != nil {
+			return nil, rerr
+		}
+		keyIndex++
 
 		signatureData := signing.SingleSignatureData{
-			SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
+			SignMode:  signMode,
 			Signature: nil,
 		}
 		sigV2 := signing.SignatureV2{
-			PubKey:   &pubKey,
+			PubKey:   pubKey,
 			Data:     &signatureData,
 			Sequence: signer.AccountSequence,
 		}
@@ -105,41 +169,36 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 	}
 	payloads := []*types.SigningPayload{}
 	for i, signer := range metadata.signers {
-		addr, rerr := getAddressFromPublicKey(request.PublicKeys[i])
-		if rerr != nil {
-			return nil, rerr
-		}
-
-		chainID := request.NetworkIdentifier.Network
-		switch chainID {
-		case "kava-mainnet":
-			chainID = "kava_2222-10"
-		case "kava-testnet":
-			chainID = "kava_2221-16000"
-		case "kava-localnet":
-			chainID = "kavalocalnet_8888-1"
-		}
-
 		signerData := authsigning.SignerData{
-			ChainID:       chainID,
+			Address:       signerAddrs[i].String(),
+			ChainID:       s.config.ChainID,
 			AccountNumber: signer.AccountNumber,
 			Sequence:      signer.AccountSequence,
 		}
 
-		signBytes, err := s.encodingConfig.TxConfig.SignModeHandler().GetSignBytes(signing.SignMode_SIGN_MODE_DIRECT, signerData, tx)
+		signBytes, err := s.encodingConfig.TxConfig.SignModeHandler().GetSignBytes(signMode, signerData, tx)
 		if err != nil {
 			return nil, wrapErr(ErrInvalidTx, err)
 		}
 
-		payloads = append(payloads, &types.SigningPayload{
-			AccountIdentifier: &types.AccountIdentifier{Address: addr.String()},
-			Bytes:             crypto.Sha256(signBytes),
-			SignatureType:     types.Ecdsa,
-		})
+		// each member of a multisig signs its own payload
+		if multisigKey, ok := sigsV2[i].PubKey.(*kmultisig.LegacyAminoPubKey); ok {
+			for _, member := range multisigKey.GetPubKeys() {
+				payloads = append(payloads, signingPayload(member, signBytes))
+			}
+			continue
+		}
+
+		payloads = append(payloads, signingPayload(sigsV2[i].PubKey, signBytes))
+	}
+
+	encodedTx, err := encodeUnsignedTx(txBytes, metadata.signers)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidTx, err)
 	}
 
 	return &types.ConstructionPayloadsResponse{
-		UnsignedTransaction: hex.EncodeToString(txBytes),
+		UnsignedTransaction: encodedTx,
 		Payloads:            payloads,
 	}, nil
 }
@@ -176,10 +235,127 @@ func validateAndParseMetadata(meta map[string]interface{}) (*metadata, error) {
 		return nil, fmt.Errorf("invalid value for %s", "memo")
 	}
 
+	var ibcTimeoutTimestamp uint64
+	if timeoutMeta, ok := meta["ibc_timeout_timestamp"]; ok {
+		rawTimeout, ok := timeoutMeta.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s", "ibc_timeout_timestamp")
+		}
+
+		ibcTimeoutTimestamp, err = strconv.ParseUint(rawTimeout, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for ibc_timeout_timestamp: %w", err)
+		}
+	}
+
+	feePayer, err := parseOptionalAddress(meta, feePayerMetadataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	feeGranter, err := parseOptionalAddress(meta, feeGranterMetadataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	multisig, err := getMultisigFromMetadata(meta)
+	if err != nil {
+		return nil, err
+	}
+
 	return &metadata{
-		signers:   signers,
-		gasPrice:  gasPrice,
-		gasWanted: uint64(gasWanted),
-		memo:      memo,
+		signers:             signers,
+		gasPrice:            gasPrice,
+		gasWanted:           uint64(gasWanted),
+		memo:                memo,
+		feePayer:            feePayer,
+		feeGranter:          feeGranter,
+		multisig:            multisig,
+		ibcTimeoutTimestamp: ibcTimeoutTimestamp,
 	}, nil
 }
+
+// signingPayload returns the payload signed by a public key, the keccak256 hash of the sign bytes
+// for eth_secp256k1 keys and the sha256 hash otherwise
+func signingPayload(pubKey cryptotypes.PubKey, signBytes []byte) *types.SigningPayload {
+	payloadBytes := crypto.Sha256(signBytes)
+	if _, ok := pubKey.(*ethsecp256k1.PubKey); ok {
+		payloadBytes = ethcrypto.Keccak256(signBytes)
+	}
+
+	return &types.SigningPayload{
+		AccountIdentifier: &types.AccountIdentifier{Address: sdk.AccAddress(pubKey.Address()).String()},
+		Bytes:             payloadBytes,
+		SignatureType:     signatureType(pubKey),
+	}
+}
+
+// signatureType returns the signature type of a public key, ecdsa_recovery for eth_secp256k1 keys
+// and ecdsa otherwise
+func signatureType(pubKey cryptotypes.PubKey) types.SignatureType {
+	if _, ok := pubKey.(*ethsecp256k1.PubKey); ok {
+		return types.EcdsaRecovery
+	}
+
+	return types.Ecdsa
+}
+
+// unsignedTransaction is the unsigned transaction returned by /construction/payloads, the hex encoded
+// transaction with the account number of each signer, in signer info order.  Account numbers are
+// not part of the transaction and are required to verify signatures in /construction/combine.
+type unsignedTransaction struct {
+	Tx         string               `json:"tx"`
+	SignerData []unsignedSignerData `json:"signer_data"`
+}
+
+type unsignedSignerData struct {
+	AccountNumber uint64 `json:"account_number"`
+}
+
+// encodeUnsignedTx returns the json encoding of an unsigned transaction and its signers
+func encodeUnsignedTx(txBytes []byte, signers []signerInfo) (string, error) {
+	unsigned := unsignedTransaction{Tx: hex.EncodeToString(txBytes)}
+	for _, signer := range signers {
+		unsigned.SignerData = append(unsigned.SignerData, unsignedSignerData{AccountNumber: signer.AccountNumber})
+	}
+
+	encoded, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// decodeUnsignedTx returns the transaction bytes and signers of an unsigned transaction returned
+// by /construction/payloads.  Hex encoded transactions, returned by /construction/payloads before
+// the account numbers were included, are decoded without signers.
+func decodeUnsignedTx(encoded string) ([]byte, []unsignedSignerData, error) {
+	if !strings.HasPrefix(strings.TrimSpace(encoded), "{") {
+		txBytes, err := hex.DecodeString(encoded)
+		return txBytes, nil, err
+	}
+
+	var unsigned unsignedTransaction
+	if err := json.Unmarshal([]byte(encoded), &unsigned); err != nil {
+		return nil, nil, fmt.Errorf("invalid unsigned transaction: %w", err)
+	}
+
+	if unsigned.SignerData == nil {
+		return nil, nil, errors.New("invalid unsigned transaction: no signer_data")
+	}
+
+	txBytes, err := hex.DecodeString(unsigned.Tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid unsigned transaction: %w", err)
+	}
+
+	return txBytes, unsigned.SignerData, nil
+}
+
+// decodeTxBytes returns the bytes of a hex encoded transaction, or of an unsigned transaction
+// returned by /construction/payloads
+func decodeTxBytes(encoded string) ([]byte, error) {
+	txBytes, _, err := decodeUnsignedTx(encoded)
+	return txBytes, err
+}
@@ -19,17 +19,28 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"testing"
 
+	"github.com/kava-labs/rosetta-kava/configuration"
 	"github.com/kava-labs/rosetta-kava/kava"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/cometbft/cometbft/crypto"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
 	"github.com/kava-labs/kava/app"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -96,7 +107,7 @@ func TestConstructionPayloads(t *testing.T) {
 
 	encodingConfig := app.MakeEncodingConfig()
 
-	txBytes, err := hex.DecodeString(response.UnsignedTransaction)
+	txBytes, _, err := decodeUnsignedTx(response.UnsignedTransaction)
 	require.NoError(t, err)
 
 	sdkTx, err := encodingConfig.TxConfig.TxDecoder()(txBytes)
@@ -121,3 +132,295 @@ func TestConstructionPayloads(t *testing.T) {
 
 	// TODO: improve testing -- check unsigned transaction signature settings & sign bytes
 }
+
+func TestConstructionPayloads_FeePayerAndGranter(t *testing.T) {
+	servicer, mockClient := setupConstructionAPIServicer()
+	servicer.config.Mode = configuration.Online
+	ctx := context.Background()
+
+	signerAddr := mustAccAddressFromBech32(t, "kava1vlpsrmdyuywvaqrv7rx6xga224sqfwz3fyfhwq")
+	signerPubKey, err := base64.StdEncoding.DecodeString("AsAbWjsqD1ntOiVZCNRdAm1nrSP8rwZoNNin85jPaeaY")
+	require.NoError(t, err)
+
+	feePayerKey := secp256k1.GenPrivKey().PubKey()
+	feePayerAddr := sdk.AccAddress(feePayerKey.Address())
+	feeGranterAddr := mustAccAddressFromBech32(t, "kava16g8lzm86f5wwf3x3t67qrpd46sjdpxpfazskwg")
+
+	ops := []*types.Operation{
+		transferOp(0, signerAddr.String(), "-5000000", "ukava"),
+		transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+	}
+
+	_, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+		Metadata:   map[string]interface{}{"fee_payer": "kava1invalid"},
+	})
+	require.NotNil(t, rerr)
+	assert.Equal(t, ErrInvalidAddress.Code, rerr.Code)
+	assert.Equal(t, "invalid value for fee_payer", rerr.Details["context"])
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+		Metadata: map[string]interface{}{
+			"fee_payer":   feePayerAddr.String(),
+			"fee_granter": feeGranterAddr.String(),
+		},
+	})
+	require.Nil(t, rerr)
+	assert.Equal(t, []*types.AccountIdentifier{
+		{Address: signerAddr.String()},
+		{Address: feePayerAddr.String()},
+	}, preprocessResponse.RequiredPublicKeys)
+	assert.Equal(t, feePayerAddr.String(), preprocessResponse.Options["fee_payer"])
+	assert.Equal(t, feeGranterAddr.String(), preprocessResponse.Options["fee_granter"])
+
+	encodedOptions, err := json.Marshal(preprocessResponse.Options)
+	require.NoError(t, err)
+	var options map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedOptions, &options))
+
+	mockClient.On("Account", ctx, signerAddr).Return(&authtypes.BaseAccount{AccountNumber: 10, Sequence: 11}, nil).Once()
+	mockClient.On("Account", ctx, feePayerAddr).Return(&authtypes.BaseAccount{AccountNumber: 20, Sequence: 21}, nil).Once()
+	mockClient.On("EstimateGas", ctx, mock.MatchedBy(func(tx sdk.Tx) bool {
+		feeTx, ok := tx.(sdk.FeeTx)
+		return ok && feeTx.FeePayer().Equals(feePayerAddr) && feeTx.FeeGranter().Equals(feeGranterAddr)
+	}), float64(0.5)).Return(uint64(100000), nil).Once()
+
+	metadataResponse, rerr := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{Options: options})
+	require.Nil(t, rerr)
+	assert.Equal(t, "[{\"account_number\":10,\"account_sequence\":11},{\"account_number\":20,\"account_sequence\":21}]", metadataResponse.Metadata["signers"])
+	assert.Equal(t, feePayerAddr.String(), metadataResponse.Metadata["fee_payer"])
+	assert.Equal(t, feeGranterAddr.String(), metadataResponse.Metadata["fee_granter"])
+
+	encodedMetadata, err := json.Marshal(metadataResponse.Metadata)
+	require.NoError(t, err)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedMetadata, &metadata))
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        ops,
+		Metadata:          metadata,
+		PublicKeys: []*types.PublicKey{
+			{CurveType: types.Secp256k1, Bytes: signerPubKey},
+			{CurveType: types.Secp256k1, Bytes: feePayerKey.Bytes()},
+		},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 2, len(payloadsResponse.Payloads))
+	assert.Equal(t, signerAddr.String(), payloadsResponse.Payloads[0].AccountIdentifier.Address)
+	assert.Equal(t, feePayerAddr.String(), payloadsResponse.Payloads[1].AccountIdentifier.Address)
+
+	txBytes, _, err := decodeUnsignedTx(payloadsResponse.UnsignedTransaction)
+	require.NoError(t, err)
+	tx, err := servicer.encodingConfig.TxConfig.TxDecoder()(txBytes)
+	require.NoError(t, err)
+	feeTx, ok := tx.(sdk.FeeTx)
+	require.True(t, ok)
+	assert.Equal(t, feePayerAddr, feeTx.FeePayer())
+	assert.Equal(t, feeGranterAddr, feeTx.FeeGranter())
+	assert.Equal(t, []sdk.AccAddress{signerAddr, feePayerAddr}, tx.(authsigning.Tx).GetSigners())
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestConstructionPayloads_Multisig(t *testing.T) {
+	servicer, mockClient := setupConstructionAPIServicer()
+	servicer.config.Mode = configuration.Online
+	ctx := context.Background()
+
+	memberKeys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	memberPubKeys := []cryptotypes.PubKey{}
+	encodedPubKeys := []interface{}{}
+	publicKeys := []*types.PublicKey{}
+	for _, key := range memberKeys {
+		memberPubKeys = append(memberPubKeys, key.PubKey())
+		encodedPubKeys = append(encodedPubKeys, hex.EncodeToString(key.PubKey().Bytes()))
+		publicKeys = append(publicKeys, &types.PublicKey{CurveType: types.Secp256k1, Bytes: key.PubKey().Bytes()})
+	}
+	multisigKey := kmultisig.NewLegacyAminoPubKey(2, memberPubKeys)
+	multisigAddr := sdk.AccAddress(multisigKey.Address())
+
+	ops := []*types.Operation{
+		transferOp(0, multisigAddr.String(), "-5000000", "ukava"),
+		transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+	}
+
+	_, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+		Metadata: map[string]interface{}{
+			"multisig": map[string]interface{}{"threshold": float64(4), "public_keys": encodedPubKeys},
+		},
+	})
+	assert.Equal(t, ErrInvalidMetadata.Code, rerr.Code)
+
+	_, rerr = servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: []*types.Operation{
+			transferOp(0, "kava1vlpsrmdyuywvaqrv7rx6xga224sqfwz3fyfhwq", "-5000000", "ukava"),
+			transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+		},
+		Metadata: map[string]interface{}{
+			"multisig": map[string]interface{}{"threshold": float64(2), "public_keys": encodedPubKeys},
+		},
+	})
+	assert.Equal(t, ErrInvalidMetadata.Code, rerr.Code)
+
+	preprocessResponse, rerr := servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+		Operations: ops,
+		Metadata: map[string]interface{}{
+			"multisig": map[string]interface{}{"threshold": float64(2), "public_keys": encodedPubKeys},
+		},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 3, len(preprocessResponse.RequiredPublicKeys))
+	for i, key := range memberPubKeys {
+		assert.Equal(t, sdk.AccAddress(key.Address()).String(), preprocessResponse.RequiredPublicKeys[i].Address)
+	}
+
+	encodedOptions, err := json.Marshal(preprocessResponse.Options)
+	require.NoError(t, err)
+	var options map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedOptions, &options))
+
+	mockClient.On("Account", ctx, multisigAddr).Return(&authtypes.BaseAccount{AccountNumber: 10, Sequence: 11}, nil).Once()
+	mockClient.On("EstimateGas", ctx, mock.Anything, float64(0.5)).Return(uint64(100000), nil).Once()
+
+	metadataResponse, rerr := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{Options: options})
+	require.Nil(t, rerr)
+	assert.Equal(t, "[{\"account_number\":10,\"account_sequence\":11,\"multisig\":true}]", metadataResponse.Metadata["signers"])
+
+	encodedMetadata, err := json.Marshal(metadataResponse.Metadata)
+	require.NoError(t, err)
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(encodedMetadata, &metadata))
+
+	networkIdentifier := &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"}
+	_, rerr = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          metadata,
+		PublicKeys:        []*types.PublicKey{publicKeys[1], publicKeys[0], publicKeys[2]},
+	})
+	assert.Equal(t, ErrInvalidPublicKey.Code, rerr.Code)
+
+	otherMultisigMetadata := map[string]interface{}{}
+	for k, v := range metadata {
+		otherMultisigMetadata[k] = v
+	}
+	otherMultisigMetadata["multisig"] = map[string]interface{}{"threshold": float64(3), "public_keys": encodedPubKeys}
+	_, rerr = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          otherMultisigMetadata,
+		PublicKeys:        publicKeys,
+	})
+	assert.Equal(t, ErrInvalidMetadata.Code, rerr.Code)
+
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          metadata,
+		PublicKeys:        publicKeys,
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 3, len(payloadsResponse.Payloads))
+
+	txBytes, _, err := decodeUnsignedTx(payloadsResponse.UnsignedTransaction)
+	require.NoError(t, err)
+	unsignedTx, err := servicer.encodingConfig.TxConfig.TxDecoder()(txBytes)
+	require.NoError(t, err)
+
+	signerData := authsigning.SignerData{
+		Address:       multisigAddr.String(),
+		ChainID:       servicer.config.ChainID,
+		AccountNumber: 10,
+		Sequence:      11,
+	}
+	handler := servicer.encodingConfig.TxConfig.SignModeHandler()
+	signBytes, err := handler.GetSignBytes(signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signerData, unsignedTx)
+	require.NoError(t, err)
+
+	for i, payload := range payloadsResponse.Payloads {
+		assert.Equal(t, sdk.AccAddress(memberPubKeys[i].Address()).String(), payload.AccountIdentifier.Address)
+		assert.Equal(t, crypto.Sha256(signBytes), payload.Bytes)
+	}
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestConstructionPayloads_EthSecp256k1(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	privKey, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	signerAddr := sdk.AccAddress(privKey.PubKey().Address())
+	publicKey := &types.PublicKey{CurveType: types.Secp256k1, Bytes: privKey.PubKey().Bytes()}
+
+	ops := []*types.Operation{
+		transferOp(0, signerAddr.String(), "-5000000", "ukava"),
+		transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+	}
+
+	networkIdentifier := &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"}
+	payloadsResponse, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata: map[string]interface{}{
+			"signers":    "[{\"account_number\":10,\"account_sequence\":11}]",
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{publicKey},
+	})
+	require.Nil(t, rerr)
+	require.Equal(t, 1, len(payloadsResponse.Payloads))
+	payload := payloadsResponse.Payloads[0]
+	assert.Equal(t, signerAddr.String(), payload.AccountIdentifier.Address)
+	assert.Equal(t, types.EcdsaRecovery, payload.SignatureType)
+
+	txBytes, _, err := decodeUnsignedTx(payloadsResponse.UnsignedTransaction)
+	require.NoError(t, err)
+	unsignedTx, err := servicer.encodingConfig.TxConfig.TxDecoder()(txBytes)
+	require.NoError(t, err)
+
+	signerData := authsigning.SignerData{
+		Address:       signerAddr.String(),
+		ChainID:       servicer.config.ChainID,
+		AccountNumber: 10,
+		Sequence:      11,
+	}
+	handler := servicer.encodingConfig.TxConfig.SignModeHandler()
+	signBytes, err := handler.GetSignBytes(signing.SignMode_SIGN_MODE_DIRECT, signerData, unsignedTx)
+	require.NoError(t, err)
+	assert.Equal(t, ethcrypto.Keccak256(signBytes), payload.Bytes)
+}
+
+func TestConstructionPayloads_PublicKeyDoesNotMatchSigner(t *testing.T) {
+	servicer, _ := setupConstructionAPIServicer()
+	ctx := context.Background()
+
+	signerAddr := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	otherKey := secp256k1.GenPrivKey().PubKey()
+
+	ops := []*types.Operation{
+		transferOp(0, signerAddr.String(), "-5000000", "ukava"),
+		transferOp(1, "kava1mq9qxlhze029lm0frzw2xr6hem8c3k9ts54w0w", "5000000", "ukava", 0),
+	}
+
+	_, rerr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "Kava", Network: "kava-testnet"},
+		Operations:        ops,
+		Metadata: map[string]interface{}{
+			"signers":    "[{\"account_number\":10,\"account_sequence\":11}]",
+			"gas_wanted": float64(250001),
+			"gas_price":  float64(0.25),
+			"memo":       "",
+		},
+		PublicKeys: []*types.PublicKey{{CurveType: types.Secp256k1, Bytes: otherKey.Bytes()}},
+	})
+	require.NotNil(t, rerr)
+	assert.Equal(t, ErrInvalidPublicKey.Code, rerr.Code)
+	assert.Equal(t, fmt.Sprintf("public key does not match signer %s", signerAddr), rerr.Details["context"])
+}
@@ -20,8 +20,6 @@ package services
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 
 	"github.com/kava-labs/rosetta-kava/kava"
 
@@ -30,7 +28,6 @@ import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
-	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 )
 
 const (
@@ -47,15 +44,9 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 		return nil, ErrNoOperations
 	}
 
-	// TODO: improve operation parsing -- very basic for first pass
-	//
-	// currently, only supports a single transfer with one currency
-	// should support multiple transfers, multiple currencies, and
-	// staking operations
-	//
-	// in addition, parsing logic needs to be refactored with improved
-	// testing around invalid cases, and related operations
-	msgs, rerr := parseOperationMsgs(request.Operations)
+	// transfers must net to zero per currency and are built as a MsgSend
+	// or a single sender MsgMultiSend for the debits of each sender
+	msgs, rerr := parseOperationMsgs(s.registry, request.Operations, nil)
 	if rerr != nil {
 		return nil, rerr
 	}
@@ -79,8 +70,7 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 		"suggested_fee_multiplier": suggestedMultiplerOrDefault(request.SuggestedFeeMultiplier),
 	}
 
-	// TODO: can improve to include other fee options such as payer
-	encodedMaxFee, rerr := getMaxFeeAndEncodeOption(request.MaxFee)
+	encodedMaxFee, rerr := getMaxFeeAndEncodeOption(s.registry, request.MaxFee)
 	if rerr != nil {
 		return nil, rerr
 	}
@@ -88,23 +78,51 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 		options["max_fee"] = *encodedMaxFee
 	}
 
-	requiredPublicKeys := []*types.AccountIdentifier{}
+	// fees are paid by the fee payer, or deducted from the fee granter's allowance to the fee payer
+	feePayer, err := parseOptionalAddress(request.Metadata, feePayerMetadataKey)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
+	if feePayer != nil {
+		options[feePayerMetadataKey] = feePayer.String()
+	}
 
-	for _, msg := range msgs {
-		signers := msg.GetSigners()
-		seenSigners := make(map[string]bool)
+	feeGranter, err := parseOptionalAddress(request.Metadata, feeGranterMetadataKey)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
+	if feeGranter != nil {
+		options[feeGranterMetadataKey] = feeGranter.String()
+	}
 
-		// TODO: add test cases for multiple signers
-		for _, signer := range signers {
-			addr := signer.String()
+	multisigPubKey, err := getMultisigFromMetadata(request.Metadata)
+	if err != nil {
+		return nil, wrapErr(ErrInvalidMetadata, err)
+	}
+	if multisigPubKey != nil {
+		encodedMultisig, err := encodeMultisig(multisigPubKey)
+		if err != nil {
+			return nil, wrapErr(ErrKava, err)
+		}
+		options[multisigMetadataKey] = encodedMultisig
+	}
 
-			if !seenSigners[addr] {
-				requiredPublicKeys = append(requiredPublicKeys, &types.AccountIdentifier{
-					Address: addr,
-				})
+	var msgSigners []sdk.AccAddress
+	for _, msg := range msgs {
+		msgSigners = append(msgSigners, msg.GetSigners()...)
+	}
 
-				seenSigners[addr] = true
-			}
+	requiredPublicKeys := []*types.AccountIdentifier{}
+	for _, signer := range transactionSigners(msgSigners, feePayer) {
+		requiredPublicKeys = append(requiredPublicKeys, &types.AccountIdentifier{
+			Address: signer.String(),
+		})
+	}
+
+	if multisigPubKey != nil {
+		requiredPublicKeys, rerr = expandMultisigSigner(requiredPublicKeys, multisigPubKey)
+		if rerr != nil {
+			return nil, rerr
 		}
 	}
 
@@ -114,53 +132,26 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 	}, nil
 }
 
-func parseOperationMsgs(ops []*types.Operation) ([]sdk.Msg, *types.Error) {
-	if len(ops) != 2 {
-		return nil, wrapErr(ErrUnclearIntent, errors.New("invalid number of operations, expected 2"))
+// transactionSigners returns the unique signers of a transaction in the order they sign it.  Message
+// signers sign in the order of their first message, and a fee payer that does not sign a message signs
+// the transaction after all message signers.
+func transactionSigners(msgSigners []sdk.AccAddress, feePayer sdk.AccAddress) []sdk.AccAddress {
+	if feePayer != nil {
+		msgSigners = append(msgSigners, feePayer)
 	}
 
-	sendMsg := banktypes.MsgSend{}
-
-	for _, op := range ops {
-		if op.Type != kava.TransferOpType {
-			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("invalid opeartion type, only '%s' allowed", kava.TransferOpType))
+	signers := []sdk.AccAddress{}
+	seen := make(map[string]bool)
+	for _, signer := range msgSigners {
+		if seen[signer.String()] {
+			continue
 		}
 
-		value, err := types.AmountValue(op.Amount)
-		if err != nil {
-			return nil, ErrInvalidCurrencyAmount
-		}
-
-		if value.Sign() == 0 {
-			return nil, ErrInvalidCurrencyAmount
-		}
-
-		if value.Sign() > 0 {
-			to, err := getAddressFromAccount(op.Account)
-			if err != nil {
-				return nil, err
-			}
-
-			sendMsg.ToAddress = to.String()
-
-			coin, err := amountToCoin(op.Amount)
-			if err != nil {
-				return nil, ErrInvalidCurrencyAmount
-			}
-			sendMsg.Amount = sdk.NewCoins(coin)
-		}
-
-		if value.Sign() < 0 {
-			from, err := getAddressFromAccount(op.Account)
-			if err != nil {
-				return nil, err
-			}
-
-			sendMsg.FromAddress = from.String()
-		}
+		seen[signer.String()] = true
+		signers = append(signers, signer)
 	}
 
-	return []sdk.Msg{&sendMsg}, nil
+	return signers
 }
 
 func suggestedMultiplerOrDefault(multiplier *float64) float64 {
@@ -191,14 +182,14 @@ func getGasAdjustmentFromMetadata(metadata map[string]interface{}) float64 {
 	return defaultGasAdjustment
 }
 
-func getMaxFeeAndEncodeOption(amounts []*types.Amount) (*string, *types.Error) {
+func getMaxFeeAndEncodeOption(registry *kava.CurrencyRegistry, amounts []*types.Amount) (*string, *types.Error) {
 	if len(amounts) == 0 {
 		return nil, nil
 	}
 
 	var maxFee sdk.Coins
 	for _, feeAmount := range amounts {
-		coin, err := amountToCoin(feeAmount)
+		coin, err := amountToCoin(registry, feeAmount)
 		if err != nil {
 			return nil, err
 		}
@@ -214,27 +205,17 @@ func getMaxFeeAndEncodeOption(amounts []*types.Amount) (*string, *types.Error) {
 	return &encodedMaxFee, nil
 }
 
-func amountToCoin(amount *types.Amount) (sdk.Coin, *types.Error) {
+func amountToCoin(registry *kava.CurrencyRegistry, amount *types.Amount) (sdk.Coin, *types.Error) {
 	value, ok := sdkmath.NewIntFromString(amount.Value)
 	if !ok {
 		return sdk.Coin{}, ErrInvalidCurrencyAmount
 	}
 
-	denom, ok := kava.Denoms[amount.Currency.Symbol]
+	denom, ok := registry.Denom(amount.Currency)
 	if !ok {
 		return sdk.Coin{}, ErrUnsupportedCurrency
 	}
 
-	currency, ok := kava.Currencies[denom]
-	if !ok {
-		return sdk.Coin{}, ErrUnsupportedCurrency
-	}
-
-	if currency.Symbol != amount.Currency.Symbol ||
-		currency.Decimals != amount.Currency.Decimals {
-		return sdk.Coin{}, ErrUnsupportedCurrency
-	}
-
 	return sdk.NewCoin(denom, value), nil
 }
 
@@ -452,3 +452,40 @@ func TestConstructionPreprocess_GasAdjustment(t *testing.T) {
 		assert.InDelta(t, tc.expectedGasAdjustment, actualGasAdjustment, 0.0000001)
 	}
 }
+
+func TestTransactionSigners(t *testing.T) {
+	delegator := mustAccAddressFromBech32(t, testDelegatorAddress)
+	grantee := mustAccAddressFromBech32(t, testGranteeAddress)
+	feePayer := sdk.AccAddress([]byte("fee_payer___________"))
+
+	testCases := []struct {
+		name       string
+		msgSigners []sdk.AccAddress
+		feePayer   sdk.AccAddress
+		expected   []sdk.AccAddress
+	}{
+		{
+			name:       "message signers in order",
+			msgSigners: []sdk.AccAddress{grantee, delegator, grantee},
+			expected:   []sdk.AccAddress{grantee, delegator},
+		},
+		{
+			name:       "fee payer signs after message signers",
+			msgSigners: []sdk.AccAddress{delegator, grantee},
+			feePayer:   feePayer,
+			expected:   []sdk.AccAddress{delegator, grantee, feePayer},
+		},
+		{
+			name:       "fee payer that signs a message",
+			msgSigners: []sdk.AccAddress{delegator, grantee},
+			feePayer:   delegator,
+			expected:   []sdk.AccAddress{delegator, grantee},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, transactionSigners(tc.msgSigners, tc.feePayer))
+		})
+	}
+}
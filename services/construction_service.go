@@ -19,6 +19,7 @@ package services
 
 import (
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 
 	"github.com/kava-labs/kava/app/params"
 )
@@ -27,6 +28,7 @@ import (
 type ConstructionAPIService struct {
 	config         *configuration.Configuration
 	client         Client
+	registry       *kava.CurrencyRegistry
 	encodingConfig params.EncodingConfig
 }
 
@@ -34,11 +36,13 @@ type ConstructionAPIService struct {
 func NewConstructionAPIService(
 	cfg *configuration.Configuration,
 	client Client,
+	registry *kava.CurrencyRegistry,
 	encodingConfig params.EncodingConfig,
 ) *ConstructionAPIService {
 	return &ConstructionAPIService{
 		config:         cfg,
 		client:         client,
+		registry:       registry,
 		encodingConfig: encodingConfig,
 	}
 }
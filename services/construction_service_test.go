@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 	mocks "github.com/kava-labs/rosetta-kava/mocks/services"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -32,19 +33,21 @@ import (
 
 func setupConstructionAPIServicer() (*ConstructionAPIService, *mocks.Client) {
 	cfg := &configuration.Configuration{
-		Mode: configuration.Offline,
+		Mode:    configuration.Offline,
+		ChainID: "kava_2221-16000",
 	}
 	mockClient := &mocks.Client{}
 	encodingConfig := app.MakeEncodingConfig()
-	return NewConstructionAPIService(cfg, mockClient, encodingConfig), mockClient
+	return NewConstructionAPIService(cfg, mockClient, kava.NewCurrencyRegistry(kava.Currencies), encodingConfig), mockClient
 }
 
 func setupConstructionAPIServicerWithEncodingConfig(encodingConfig params.EncodingConfig) (*ConstructionAPIService, *mocks.Client) {
 	cfg := &configuration.Configuration{
-		Mode: configuration.Offline,
+		Mode:    configuration.Offline,
+		ChainID: "kava_2221-16000",
 	}
 	mockClient := &mocks.Client{}
-	return NewConstructionAPIService(cfg, mockClient, encodingConfig), mockClient
+	return NewConstructionAPIService(cfg, mockClient, kava.NewCurrencyRegistry(kava.Currencies), encodingConfig), mockClient
 }
 
 func TestConstructionService_Offline(t *testing.T) {
@@ -54,7 +57,7 @@ func TestConstructionService_Offline(t *testing.T) {
 
 	mockClient := &mocks.Client{}
 	encodingConfig := app.MakeEncodingConfig()
-	servicer := NewConstructionAPIService(cfg, mockClient, encodingConfig)
+	servicer := NewConstructionAPIService(cfg, mockClient, kava.NewCurrencyRegistry(kava.Currencies), encodingConfig)
 	ctx := context.Background()
 
 	// Test Metadata
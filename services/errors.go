@@ -18,6 +18,10 @@
 package services
 
 import (
+	"errors"
+
+	"github.com/kava-labs/rosetta-kava/kava"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -42,6 +46,11 @@ var (
 		ErrMissingPublicKey,
 		ErrInvalidPublicKey,
 		ErrInvalidTx,
+		ErrMissingSignature,
+		ErrInvalidSignature,
+
+		ErrTxParse,
+		ErrInvalidCallParameters,
 	}
 
 	// ErrUnimplemented is returned when an endpoint
@@ -145,6 +154,26 @@ var (
 		Code:    15,
 		Message: "Missing Signature",
 	}
+
+	// ErrTxParse is returned when a transaction in a block
+	// could not be converted to operations.
+	ErrTxParse = &types.Error{
+		Code:    16,
+		Message: "Unable to parse block transaction",
+	}
+
+	// ErrInvalidCallParameters is returned by the call endpoint with invalid parameters
+	ErrInvalidCallParameters = &types.Error{
+		Code:    17,
+		Message: "Invalid call parameters",
+	}
+
+	// ErrInvalidSignature is returned when a signature does not match a signer or
+	// does not verify against the sign bytes of the transaction
+	ErrInvalidSignature = &types.Error{
+		Code:    18,
+		Message: "Invalid Signature",
+	}
 )
 
 // wrapErr adds details to the types.Error provided. We use a function
@@ -164,3 +193,25 @@ func wrapErr(rErr *types.Error, err error) *types.Error {
 
 	return newErr
 }
+
+// wrapClientErr maps an error returned by the client when fetching blocks
+// or transactions to a types.Error.  Parse errors include the block height
+// and index of the transaction that failed.
+func wrapClientErr(err error) *types.Error {
+	var parseErr *kava.TxParseError
+	if errors.As(err, &parseErr) {
+		rErr := wrapErr(ErrTxParse, err)
+		rErr.Details["block_height"] = parseErr.Height
+		rErr.Details["tx_index"] = parseErr.TxIndex
+		rErr.Details["tx_hash"] = parseErr.TxHash
+
+		return rErr
+	}
+
+	rErr := wrapErr(ErrKava, err)
+	if kava.IsRetriableError(err) {
+		rErr.Retriable = true
+	}
+
+	return rErr
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kava-labs/rosetta-kava/configuration"
+)
+
+// HealthResponse is the body returned by the /healthz and /readyz endpoints
+type HealthResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HealthService implements liveness and readiness probes
+type HealthService struct {
+	config  *configuration.Configuration
+	clients map[string]Client
+}
+
+// NewHealthService creates a new instance of a HealthService using
+// the client of each network served, keyed by network.
+func NewHealthService(
+	cfg *configuration.Configuration,
+	clients map[string]Client,
+) *HealthService {
+	return &HealthService{
+		config:  cfg,
+		clients: clients,
+	}
+}
+
+// Healthz implements the /healthz endpoint, reporting that the process is up
+func (s *HealthService) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealthResponse(w, http.StatusOK, &HealthResponse{Status: "ok"})
+}
+
+// Readyz implements the /readyz endpoint, reporting if the node is able to serve requests
+func (s *HealthService) Readyz(w http.ResponseWriter, r *http.Request) {
+	if err := s.Ready(r.Context()); err != nil {
+		writeHealthResponse(w, http.StatusServiceUnavailable, &HealthResponse{Status: "unavailable", Reason: err.Error()})
+		return
+	}
+
+	writeHealthResponse(w, http.StatusOK, &HealthResponse{Status: "ok"})
+}
+
+// Ready returns an error if the node of any network does not respond, is catching up or its
+// latest block is older than the configured maximum block age.  Offline services are always ready.
+func (s *HealthService) Ready(ctx context.Context) error {
+	if s.config.Mode != configuration.Online {
+		return nil
+	}
+
+	for _, networkIdentifier := range s.config.NetworkIdentifiers() {
+		client, ok := s.clients[networkIdentifier.Network]
+		if !ok {
+			return fmt.Errorf("%s: no client configured", networkIdentifier.Network)
+		}
+
+		if err := s.nodeReady(ctx, client); err != nil {
+			return fmt.Errorf("%s: %w", networkIdentifier.Network, err)
+		}
+	}
+
+	return nil
+}
+
+// nodeReady returns an error if the node is not able to serve requests
+func (s *HealthService) nodeReady(ctx context.Context, client Client) error {
+	syncInfo, err := client.SyncInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("node status unavailable: %w", err)
+	}
+
+	if syncInfo.CatchingUp {
+		return errors.New("node is catching up")
+	}
+
+	age := time.Since(syncInfo.LatestBlockTime)
+	if age > s.config.ReadinessMaxBlockAge {
+		return fmt.Errorf(
+			"latest block %d is %s old, exceeding %s",
+			syncInfo.LatestBlockHeight,
+			age.Truncate(time.Second),
+			s.config.ReadinessMaxBlockAge,
+		)
+	}
+
+	return nil
+}
+
+func writeHealthResponse(w http.ResponseWriter, status int, response *HealthResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
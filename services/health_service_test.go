@@ -0,0 +1,147 @@
+// Copyright 2021 Kava Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
+	mocks "github.com/kava-labs/rosetta-kava/mocks/services"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func serveHealth(t *testing.T, handler http.HandlerFunc) (int, *HealthResponse) {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var response HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	return rec.Code, &response
+}
+
+func TestHealthService_Offline(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:              configuration.Offline,
+		NetworkIdentifier: networkIdentifier,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewHealthService(cfg, map[string]Client{networkIdentifier.Network: mockClient})
+
+	code, response := serveHealth(t, servicer.Healthz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &HealthResponse{Status: "ok"}, response)
+
+	code, response = serveHealth(t, servicer.Readyz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &HealthResponse{Status: "ok"}, response)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHealthService_Online(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:                 configuration.Online,
+		NetworkIdentifier:    networkIdentifier,
+		ReadinessMaxBlockAge: time.Minute,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewHealthService(cfg, map[string]Client{networkIdentifier.Network: mockClient})
+
+	code, _ := serveHealth(t, servicer.Healthz)
+	assert.Equal(t, http.StatusOK, code)
+
+	mockClient.On("SyncInfo", mock.Anything).Return(&ctypes.SyncInfo{
+		LatestBlockHeight: 100,
+		LatestBlockTime:   time.Now().Add(-10 * time.Second),
+	}, nil).Once()
+
+	code, response := serveHealth(t, servicer.Readyz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &HealthResponse{Status: "ok"}, response)
+
+	mockClient.On("SyncInfo", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+	code, response = serveHealth(t, servicer.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, &HealthResponse{Status: "unavailable", Reason: "kava-testnet-1: node status unavailable: connection refused"}, response)
+
+	mockClient.On("SyncInfo", mock.Anything).Return(&ctypes.SyncInfo{
+		LatestBlockHeight: 100,
+		LatestBlockTime:   time.Now(),
+		CatchingUp:        true,
+	}, nil).Once()
+
+	code, response = serveHealth(t, servicer.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "kava-testnet-1: node is catching up", response.Reason)
+
+	mockClient.On("SyncInfo", mock.Anything).Return(&ctypes.SyncInfo{
+		LatestBlockHeight: 100,
+		LatestBlockTime:   time.Now().Add(-5 * time.Minute),
+	}, nil).Once()
+
+	code, response = serveHealth(t, servicer.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "kava-testnet-1: latest block 100 is 5m0s old, exceeding 1m0s", response.Reason)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestHealthService_AdditionalNetworks(t *testing.T) {
+	testnetIdentifier := &types.NetworkIdentifier{
+		Blockchain: kava.Blockchain,
+		Network:    "kava-testnet-2",
+	}
+	cfg := &configuration.Configuration{
+		Mode:                 configuration.Online,
+		NetworkIdentifier:    networkIdentifier,
+		ReadinessMaxBlockAge: time.Minute,
+		AdditionalNetworks: []*configuration.Configuration{
+			{Mode: configuration.Online, NetworkIdentifier: testnetIdentifier},
+		},
+	}
+	mainnetClient := &mocks.Client{}
+	testnetClient := &mocks.Client{}
+	servicer := NewHealthService(cfg, map[string]Client{
+		networkIdentifier.Network: mainnetClient,
+		testnetIdentifier.Network: testnetClient,
+	})
+
+	synced := &ctypes.SyncInfo{LatestBlockHeight: 100, LatestBlockTime: time.Now()}
+	mainnetClient.On("SyncInfo", mock.Anything).Return(synced, nil).Twice()
+	testnetClient.On("SyncInfo", mock.Anything).Return(synced, nil).Once()
+
+	require.NoError(t, servicer.Ready(context.Background()))
+
+	// the service is not ready while any network is not ready
+	testnetClient.On("SyncInfo", mock.Anything).Return(&ctypes.SyncInfo{CatchingUp: true}, nil).Once()
+	assert.EqualError(t, servicer.Ready(context.Background()), "kava-testnet-2: node is catching up")
+
+	mainnetClient.AssertExpectations(t)
+	testnetClient.AssertExpectations(t)
+}
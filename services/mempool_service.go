@@ -20,17 +20,27 @@ package services
 import (
 	"context"
 
-	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // MempoolAPIService implements the server.MempoolAPIServicer interface.
 type MempoolAPIService struct {
+	config *configuration.Configuration
+	client Client
 }
 
 // NewMempoolAPIService creates a new instance of a MempoolAPIService.
-func NewMempoolAPIService() server.MempoolAPIServicer {
-	return &MempoolAPIService{}
+func NewMempoolAPIService(
+	cfg *configuration.Configuration,
+	client Client,
+) *MempoolAPIService {
+	return &MempoolAPIService{
+		config: cfg,
+		client: client,
+	}
 }
 
 // Mempool implements the /mempool endpoint.
@@ -38,7 +48,18 @@ func (s *MempoolAPIService) Mempool(
 	ctx context.Context,
 	request *types.NetworkRequest,
 ) (*types.MempoolResponse, *types.Error) {
-	return nil, ErrUnimplemented
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	transactionIdentifiers, err := s.client.Mempool(ctx)
+	if err != nil {
+		return nil, wrapErr(ErrKava, err)
+	}
+
+	return &types.MempoolResponse{
+		TransactionIdentifiers: transactionIdentifiers,
+	}, nil
 }
 
 // MempoolTransaction implements the /mempool/transaction endpoint.
@@ -46,5 +67,22 @@ func (s *MempoolAPIService) MempoolTransaction(
 	ctx context.Context,
 	request *types.MempoolTransactionRequest,
 ) (*types.MempoolTransactionResponse, *types.Error) {
-	return nil, ErrUnimplemented
+	if s.config.Mode != configuration.Online {
+		return nil, ErrUnavailableOffline
+	}
+
+	transaction, err := s.client.MempoolTransaction(ctx, request.TransactionIdentifier)
+	if err != nil {
+		rErr := wrapErr(ErrKava, err)
+
+		if kava.IsRetriableError(err) {
+			rErr.Retriable = true
+		}
+
+		return nil, rErr
+	}
+
+	return &types.MempoolTransactionResponse{
+		Transaction: transaction,
+	}, nil
 }
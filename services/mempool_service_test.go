@@ -19,22 +19,88 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/kava-labs/rosetta-kava/configuration"
+	mocks "github.com/kava-labs/rosetta-kava/mocks/services"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestMempoolEndpoints(t *testing.T) {
-	servicer := NewMempoolAPIService()
+func TestMempoolService_Offline(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode: configuration.Offline,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewMempoolAPIService(cfg, mockClient)
 	ctx := context.Background()
 
-	mem, err := servicer.Mempool(ctx, nil)
+	mem, err := servicer.Mempool(ctx, &types.NetworkRequest{})
 	assert.Nil(t, mem)
-	assert.Equal(t, ErrUnimplemented.Code, err.Code)
-	assert.Equal(t, ErrUnimplemented.Message, err.Message)
+	assert.Equal(t, ErrUnavailableOffline.Code, err.Code)
+	assert.Equal(t, ErrUnavailableOffline.Message, err.Message)
 
-	memTransaction, err := servicer.MempoolTransaction(ctx, nil)
+	memTransaction, err := servicer.MempoolTransaction(ctx, &types.MempoolTransactionRequest{})
 	assert.Nil(t, memTransaction)
-	assert.Equal(t, ErrUnimplemented.Code, err.Code)
-	assert.Equal(t, ErrUnimplemented.Message, err.Message)
+	assert.Equal(t, ErrUnavailableOffline.Code, err.Code)
+	assert.Equal(t, ErrUnavailableOffline.Message, err.Message)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestMempoolService_Online(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode: configuration.Online,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewMempoolAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	transactionIdentifier := &types.TransactionIdentifier{
+		Hash: "6E4B4A1B4D5E0BB9BB4E5DCA0D3A46A20A5BE3EB0E1A18F4F3B9F4C3A0E3F3A6",
+	}
+
+	mockClient.On("Mempool", ctx).Return([]*types.TransactionIdentifier{transactionIdentifier}, nil).Once()
+
+	mem, err := servicer.Mempool(ctx, &types.NetworkRequest{NetworkIdentifier: networkIdentifier})
+	require.Nil(t, err)
+	assert.Equal(t, []*types.TransactionIdentifier{transactionIdentifier}, mem.TransactionIdentifiers)
+
+	kavaErr := errors.New("some client error")
+	mockClient.On("Mempool", ctx).Return(nil, kavaErr).Once()
+
+	mem, err = servicer.Mempool(ctx, &types.NetworkRequest{NetworkIdentifier: networkIdentifier})
+	assert.Nil(t, mem)
+	assert.Equal(t, ErrKava.Code, err.Code)
+	assert.Equal(t, ErrKava.Message, err.Message)
+	assert.Equal(t, kavaErr.Error(), err.Details["context"])
+
+	transaction := &types.Transaction{
+		TransactionIdentifier: transactionIdentifier,
+		Operations:            []*types.Operation{},
+	}
+	mockClient.On("MempoolTransaction", ctx, transactionIdentifier).Return(transaction, nil).Once()
+
+	memTransaction, err := servicer.MempoolTransaction(ctx, &types.MempoolTransactionRequest{
+		NetworkIdentifier:     networkIdentifier,
+		TransactionIdentifier: transactionIdentifier,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, transaction, memTransaction.Transaction)
+
+	mockClient.On("MempoolTransaction", ctx, transactionIdentifier).Return(nil, kavaErr).Once()
+
+	memTransaction, err = servicer.MempoolTransaction(ctx, &types.MempoolTransactionRequest{
+		NetworkIdentifier:     networkIdentifier,
+		TransactionIdentifier: transactionIdentifier,
+	})
+	assert.Nil(t, memTransaction)
+	assert.Equal(t, ErrKava.Code, err.Code)
+	assert.Equal(t, ErrKava.Message, err.Message)
+	assert.Equal(t, kavaErr.Error(), err.Details["context"])
+
+	mockClient.AssertExpectations(t)
 }
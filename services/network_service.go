@@ -49,9 +49,7 @@ func (s *NetworkAPIService) NetworkList(
 	request *types.MetadataRequest,
 ) (*types.NetworkListResponse, *types.Error) {
 	return &types.NetworkListResponse{
-		NetworkIdentifiers: []*types.NetworkIdentifier{
-			s.config.NetworkIdentifier,
-		},
+		NetworkIdentifiers: s.config.NetworkIdentifiers(),
 	}, nil
 }
 
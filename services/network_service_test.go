@@ -169,3 +169,27 @@ func TestNetworkEndpoints_Online(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestNetworkList_AdditionalNetworks(t *testing.T) {
+	testnetIdentifier := &types.NetworkIdentifier{
+		Blockchain: kava.Blockchain,
+		Network:    "kava-testnet-2",
+	}
+	cfg := &configuration.Configuration{
+		Mode:              configuration.Online,
+		NetworkIdentifier: networkIdentifier,
+		AdditionalNetworks: []*configuration.Configuration{
+			{Mode: configuration.Online, NetworkIdentifier: testnetIdentifier},
+		},
+	}
+	servicer := NewNetworkAPIService(cfg, &mocks.Client{})
+
+	networkList, err := servicer.NetworkList(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &types.NetworkListResponse{
+		NetworkIdentifiers: []*types.NetworkIdentifier{
+			networkIdentifier,
+			testnetIdentifier,
+		},
+	}, networkList)
+}
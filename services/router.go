@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/server"
@@ -32,6 +33,7 @@ import (
 func NewBlockchainRouter(
 	config *configuration.Configuration,
 	client Client,
+	registry *kava.CurrencyRegistry,
 	asserter *asserter.Asserter,
 ) http.Handler {
 	networkAPIService := NewNetworkAPIService(config, client)
@@ -53,19 +55,19 @@ func NewBlockchainRouter(
 	)
 
 	encodingConfig := app.MakeEncodingConfig()
-	constructionAPIService := NewConstructionAPIService(config, client, encodingConfig)
+	constructionAPIService := NewConstructionAPIService(config, client, registry, encodingConfig)
 	constructionAPIController := server.NewConstructionAPIController(
 		constructionAPIService,
 		asserter,
 	)
 
-	mempoolAPIService := NewMempoolAPIService()
+	mempoolAPIService := NewMempoolAPIService(config, client)
 	mempoolAPIController := server.NewMempoolAPIController(
 		mempoolAPIService,
 		asserter,
 	)
 
-	callAPIService := NewCallAPIService(config, client)
+	callAPIService := NewCallAPIService(config, client, encodingConfig)
 	callAPIController := server.NewCallAPIController(
 		callAPIService,
 		asserter,
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/kava-labs/rosetta-kava/configuration"
+	"github.com/kava-labs/rosetta-kava/kava"
 	mocks "github.com/kava-labs/rosetta-kava/mocks/services"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
@@ -50,7 +51,7 @@ func TestRouter_Offline(t *testing.T) {
 	)
 	assert.NoError(t, err)
 
-	handler := NewBlockchainRouter(cfg, &mockClient, server)
+	handler := NewBlockchainRouter(cfg, &mockClient, kava.NewCurrencyRegistry(kava.Currencies), server)
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
 
@@ -20,10 +20,15 @@ package services
 import (
 	"context"
 
+	"github.com/kava-labs/rosetta-kava/kava"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
 // Client is used services to get blockchain
@@ -40,8 +45,24 @@ type Client interface {
 
 	Block(context.Context, *types.PartialBlockIdentifier) (*types.BlockResponse, error)
 
+	BlockTransaction(
+		context.Context,
+		*types.BlockIdentifier,
+		*types.TransactionIdentifier,
+	) (*types.Transaction, error)
+
+	Delegations(context.Context, sdk.AccAddress) (stakingtypes.DelegationResponses, error)
+
+	UnbondingDelegations(context.Context, sdk.AccAddress) (stakingtypes.UnbondingDelegations, error)
+
+	Validators(context.Context, string) (stakingtypes.Validators, error)
+
+	DelegationRewards(context.Context, sdk.AccAddress) (*distrtypes.QueryDelegationTotalRewardsResponse, error)
+
 	EstimateGas(context.Context, authsigning.Tx, float64) (uint64, error)
 
+	SimulateTransaction(context.Context, authsigning.Tx) (*kava.SimulationResult, error)
+
 	Status(context.Context) (
 		*types.BlockIdentifier,
 		int64,
@@ -51,5 +72,13 @@ type Client interface {
 		error,
 	)
 
+	SyncInfo(context.Context) (*ctypes.SyncInfo, error)
+
+	Mempool(context.Context) ([]*types.TransactionIdentifier, error)
+
+	MempoolTransaction(context.Context, *types.TransactionIdentifier) (*types.Transaction, error)
+
+	Transaction(context.Context, *types.TransactionIdentifier) (*types.BlockIdentifier, *types.Transaction, error)
+
 	PostTx(ctx context.Context, txBytes []byte) (*types.TransactionIdentifier, error)
 }